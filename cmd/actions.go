@@ -0,0 +1,166 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/samber/lo"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// actionTimeout bounds an action including retries of its API calls
+const actionTimeout = 30 * time.Second
+
+// action is a mutating operation against the cluster triggered from the UI
+type action struct {
+	// description names the change in the status line, e.g. "delete pod default/web-1"
+	description string
+	// kubectl is the equivalent kubectl command, shown and logged when the action runs
+	kubectl string
+	// requires names the capabilities the action needs, see capabilities
+	requires []string
+	// run performs the change, passing dryRun through to the API request options.
+	// Each API call is wrapped in retry.
+	run func(ctx context.Context, client kubernetes.Interface, dryRun []string) error
+	// onSuccess optionally updates the model once the change has been applied
+	onSuccess func()
+	// then optionally continues once the action has finished, whether it succeeded or not
+	then func(err error) tea.Cmd
+	// undo optionally reverts the change, computed from the cached state before it ran
+	undo *action
+	// undoing marks actions run by undo, which are not recorded for undo themselves
+	undoing bool
+}
+
+// actionResultMsg reports the outcome of an action
+type actionResultMsg struct {
+	action action
+	// task is the id of the background task that ran the action
+	task int
+	err  error
+}
+
+// dryRunResultMsg reports the server-side dry-run outcome of actions awaiting confirmation
+type dryRunResultMsg struct {
+	actions []action
+	errs    []error
+}
+
+// runAction executes the actions, or when confirmation is on, dry-runs them
+// first and asks before applying
+func (m *Model) runAction(actions ...action) tea.Cmd {
+	if refused := m.refuseActions(actions); refused != nil {
+		return refused
+	}
+	if m.confirmActions {
+		return m.dryRun(actions)
+	}
+	return m.applyActions(actions)
+}
+
+// runConfirmedAction executes actions the user already confirmed in a menu, without a dry-run prompt
+func (m *Model) runConfirmedAction(actions ...action) tea.Cmd {
+	if refused := m.refuseActions(actions); refused != nil {
+		return refused
+	}
+	return m.applyActions(actions)
+}
+
+// refuseActions reports why the actions cannot run, or returns nil if they can
+func (m *Model) refuseActions(actions []action) tea.Cmd {
+	if m.kubeClient == nil {
+		return m.setStatus("actions are unavailable without a cluster connection")
+	}
+	for _, a := range actions {
+		if err := m.permitted(a); err != nil {
+			return m.setStatus(fmt.Sprintf("%s: %v", a.description, err))
+		}
+	}
+	return nil
+}
+
+// dryRun runs the actions with server-side dry-run in the background
+func (m *Model) dryRun(actions []action) tea.Cmd {
+	client := m.kubeClient
+	return func() tea.Msg {
+		ctx, cancel := m.callContext(actionTimeout)
+		defer cancel()
+		result := dryRunResultMsg{actions: actions}
+		for _, a := range actions {
+			result.errs = append(result.errs, a.runTraced(ctx, client, []string{metav1.DryRunAll}))
+		}
+		return result
+	}
+}
+
+// confirmDryRun shows what the dry-run would do and asks for confirmation to apply
+func (m *Model) confirmDryRun(msg dryRunResultMsg) tea.Cmd {
+	failed := lo.Filter(msg.errs, func(err error, _ int) bool { return err != nil })
+	summary := msg.actions[0].description
+	if len(msg.actions) > 1 {
+		summary = fmt.Sprintf("%s and %d more", summary, len(msg.actions)-1)
+	}
+	outcome := "dry-run ok"
+	if len(failed) > 0 {
+		outcome = fmt.Sprintf("dry-run: %d of %d would fail (%v)", len(failed), len(msg.actions), failed[0])
+	}
+	m.menu = &menu{title: fmt.Sprintf("%s? %s", summary, outcome), items: []menuItem{
+		{key: "y", label: "apply", choose: func() tea.Cmd { return m.applyActions(msg.actions) }},
+		{key: "n", label: "cancel", choose: func() tea.Cmd { return m.setStatus("cancelled " + summary) }},
+	}}
+	return nil
+}
+
+// applyActions executes the actions concurrently as background tasks, reporting
+// each outcome to the event loop
+func (m *Model) applyActions(actions []action) tea.Cmd {
+	client := m.kubeClient
+	cmds := make([]tea.Cmd, 0, len(actions)+2)
+	for _, a := range actions {
+		a := a
+		m.logKubectl(a.kubectl)
+		id, ctx, spin := m.startTask(a.description, actionTimeout)
+		cmds = append(cmds, spin, func() tea.Msg {
+			return actionResultMsg{action: a, task: id, err: a.runTraced(ctx, client, nil)}
+		})
+	}
+	if len(actions) == 1 && actions[0].kubectl != "" {
+		cmds = append(cmds, m.setStatus("$ "+actions[0].kubectl))
+	}
+	return tea.Batch(cmds...)
+}
+
+// logKubectl records the kubectl equivalent of an action in the session log and the --kubectl-log file
+func (m *Model) logKubectl(command string) {
+	if command == "" {
+		return
+	}
+	m.kubectlHistory = append(m.kubectlHistory, command)
+	if m.kubectlLog == nil {
+		return
+	}
+	if _, err := fmt.Fprintln(m.kubectlLog, command); err != nil {
+		m.kubectlLog = nil
+	}
+}
+
+// actionResult surfaces an action outcome in the status line
+func (m *Model) actionResult(msg actionResultMsg) tea.Cmd {
+	var status tea.Cmd
+	if msg.err != nil {
+		status = m.setStatus(fmt.Sprintf("%s failed: %v", msg.action.description, msg.err))
+	} else {
+		if msg.action.onSuccess != nil {
+			msg.action.onSuccess()
+		}
+		m.pushUndo(msg.action)
+		status = m.setStatus(fmt.Sprintf("%s: done", msg.action.description))
+	}
+	if msg.action.then == nil {
+		return status
+	}
+	return tea.Batch(status, msg.action.then(msg.err))
+}
@@ -0,0 +1,220 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/bwagner5/kube-demo/pkg/format"
+	"github.com/bwagner5/kube-demo/pkg/node"
+)
+
+// alertKind is a condition the headless alert mode watches for
+type alertKind string
+
+const (
+	// alertNodeNotReady fires for every node not ready for longer than the rule's duration
+	alertNodeNotReady alertKind = "node-not-ready"
+	// alertPending fires when more than the rule's threshold of pods are pending for longer than its duration
+	alertPending alertKind = "pending"
+	// alertEvent fires for every new warning event with the rule's reason
+	alertEvent alertKind = "event"
+)
+
+// alertRule is a parsed --alert condition
+type alertRule struct {
+	text      string
+	kind      alertKind
+	threshold int
+	duration  time.Duration
+	reason    string
+}
+
+// parseAlertRule parses node-not-ready[:duration], pending>N[:duration] or event=Reason
+func parseAlertRule(text string) (alertRule, error) {
+	rule := alertRule{text: text}
+	spec, duration, hasDuration := strings.Cut(text, ":")
+	if hasDuration {
+		d, err := time.ParseDuration(duration)
+		if err != nil {
+			return rule, fmt.Errorf("invalid duration in %q: %w", text, err)
+		}
+		rule.duration = d
+	}
+	switch {
+	case spec == string(alertNodeNotReady):
+		rule.kind = alertNodeNotReady
+	case strings.HasPrefix(spec, string(alertPending)+">"):
+		threshold, err := strconv.Atoi(strings.TrimPrefix(spec, string(alertPending)+">"))
+		if err != nil || threshold < 0 {
+			return rule, fmt.Errorf("invalid pending threshold in %q", text)
+		}
+		rule.kind, rule.threshold = alertPending, threshold
+	case strings.HasPrefix(spec, string(alertEvent)+"="):
+		if hasDuration {
+			return rule, fmt.Errorf("event alerts fire once per event and take no duration, got %q", text)
+		}
+		rule.kind, rule.reason = alertEvent, strings.TrimPrefix(spec, string(alertEvent)+"=")
+	default:
+		return rule, fmt.Errorf("unknown alert %q, expected node-not-ready[:duration], pending>N[:duration] or event=Reason", text)
+	}
+	return rule, nil
+}
+
+// alert is a rule firing for a subject: a node, the pending pods or an event
+type alert struct {
+	Rule    string    `json:"rule"`
+	Subject string    `json:"subject"`
+	State   string    `json:"state"`
+	Message string    `json:"message"`
+	Time    time.Time `json:"time"`
+}
+
+// alerter evaluates the rules against the cluster, remembering since when each subject matches
+// so conditions fire once they have held for the rule's duration and resolve once they clear
+type alerter struct {
+	rules []alertRule
+	// since is when a rule started matching a subject, keyed by rule and subject
+	since map[string]time.Time
+	// firing are the rule and subject keys alerted on and not resolved yet
+	firing map[string]bool
+	// seenEvents are the warning events already alerted on or present at startup
+	seenEvents map[types.UID]bool
+}
+
+func newAlerter(rules []alertRule) *alerter {
+	return &alerter{rules: rules, since: map[string]time.Time{}, firing: map[string]bool{}, seenEvents: map[types.UID]bool{}}
+}
+
+// alertMatches returns the subjects the rule currently matches, each with a message
+func (m *Model) alertMatches(rule alertRule) map[string]string {
+	matches := map[string]string{}
+	switch rule.kind {
+	case alertNodeNotReady:
+		for _, n := range m.getNodes() {
+			if !node.IsReady(n) {
+				matches["node/"+n.Name] = fmt.Sprintf("node %s is not ready", n.Name)
+			}
+		}
+	case alertPending:
+		if pending := m.getPendingPods(); len(pending) > rule.threshold {
+			matches["pending"] = fmt.Sprintf("%d pods are pending, oldest %s/%s", len(pending), pending[0].Namespace, pending[0].Name)
+		}
+	case alertEvent:
+		for _, obj := range m.store.Events.List() {
+			if event := obj.(*corev1.Event); event.Type == corev1.EventTypeWarning && event.Reason == rule.reason {
+				object := event.InvolvedObject.Name
+				if event.InvolvedObject.Namespace != "" {
+					object = event.InvolvedObject.Namespace + "/" + object
+				}
+				matches["event/"+string(event.UID)] = fmt.Sprintf("%s on %s %s: %s", event.Reason, strings.ToLower(event.InvolvedObject.Kind), object, strings.TrimSpace(event.Message))
+			}
+		}
+	}
+	return matches
+}
+
+// evaluate returns the alerts fired and resolved since the last evaluation. Events present on
+// the first evaluation are the backlog, not news, and are skipped.
+func (a *alerter) evaluate(m *Model, now time.Time, first bool) []alert {
+	var alerts []alert
+	for _, rule := range a.rules {
+		matches := m.alertMatches(rule)
+		if rule.kind == alertEvent {
+			for subject, message := range matches {
+				uid := types.UID(strings.TrimPrefix(subject, "event/"))
+				if a.seenEvents[uid] {
+					continue
+				}
+				a.seenEvents[uid] = true
+				if !first {
+					alerts = append(alerts, alert{Rule: rule.text, Subject: subject, State: "firing", Message: message, Time: now})
+				}
+			}
+			continue
+		}
+		for subject, message := range matches {
+			key := rule.text + "\x00" + subject
+			since, ok := a.since[key]
+			if !ok {
+				since = now
+				a.since[key] = now
+			}
+			if !a.firing[key] && now.Sub(since) >= rule.duration {
+				a.firing[key] = true
+				if rule.duration > 0 {
+					message += " for " + format.Duration(now.Sub(since))
+				}
+				alerts = append(alerts, alert{Rule: rule.text, Subject: subject, State: "firing", Message: message, Time: now})
+			}
+		}
+		prefix := rule.text + "\x00"
+		for key := range a.since {
+			subject := strings.TrimPrefix(key, prefix)
+			if !strings.HasPrefix(key, prefix) || matches[subject] != "" {
+				continue
+			}
+			delete(a.since, key)
+			if a.firing[key] {
+				delete(a.firing, key)
+				alerts = append(alerts, alert{Rule: rule.text, Subject: subject, State: "resolved", Message: subject + " no longer matches", Time: now})
+			}
+		}
+	}
+	return alerts
+}
+
+// runAlerts watches the cluster without the TUI, writing a line to out and posting to the
+// webhook, if any, whenever an alert fires or resolves, checking every interval until interrupted
+func runAlerts(m *Model, out io.Writer, rules []alertRule, webhook string, interval time.Duration) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
+	defer stop()
+	defer m.shutdown()
+	if m.informerFactory != nil {
+		m.informerFactory.WaitForCacheSync(m.stopCh)
+	}
+	if interval <= 0 {
+		interval = time.Second
+	}
+	texts := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		texts = append(texts, rule.text)
+	}
+	fmt.Fprintf(out, "%s watching %s for %s\n", time.Now().Format(time.RFC3339), m.identity, strings.Join(texts, ", "))
+	a := newAlerter(rules)
+	first := true
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		for _, fired := range a.evaluate(m, time.Now(), first) {
+			fmt.Fprintf(out, "%s %s %s: %s\n", fired.Time.Format(time.RFC3339), strings.ToUpper(fired.State), fired.Rule, fired.Message)
+			if webhook != "" {
+				if err := postWebhook(webhook, fired); err != nil {
+					log.Printf("could not post alert to webhook: %v", err)
+				}
+			}
+		}
+		first = false
+	wait:
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-m.k8sStateUpdate:
+				// drained so informer handlers do not back up, the rules are evaluated on the ticker
+			case <-ticker.C:
+				break wait
+			}
+		}
+	}
+}
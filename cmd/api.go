@@ -0,0 +1,155 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/encoding"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	apiv1 "github.com/bwagner5/kube-demo/pkg/api/v1"
+)
+
+// apiSubscriberBuffer is how many events a subscriber may fall behind before it is disconnected
+const apiSubscriberBuffer = 1024
+
+// apiSubscriber is a Watch call receiving the node and pod changes
+type apiSubscriber struct {
+	events     chan *apiv1.Event
+	namespaces map[string]bool
+	// dropped is closed once the subscriber fell too far behind
+	dropped chan struct{}
+}
+
+// wants reports whether the subscriber asked for the event
+func (s *apiSubscriber) wants(e *apiv1.Event) bool {
+	return e.Pod == nil || len(s.namespaces) == 0 || s.namespaces[e.Pod.Namespace]
+}
+
+// apiServer streams the nodes and pods of the store to the subscribers of the State API.
+// It is fed from informer goroutines, so the subscribers are guarded by a mutex.
+type apiServer struct {
+	apiv1.UnimplementedStateServer
+	store       ClusterStore
+	mu          sync.Mutex
+	subscribers map[*apiSubscriber]struct{}
+}
+
+func newAPIServer(store ClusterStore) *apiServer {
+	return &apiServer{store: store, subscribers: map[*apiSubscriber]struct{}{}}
+}
+
+// serve listens on the address and serves the State API until the returned stop is called
+func (a *apiServer) serve(address string) (func(), error) {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return nil, err
+	}
+	// JSON clients call with the "json" content-subtype
+	encoding.RegisterCodec(apiv1.Codec{})
+	server := grpc.NewServer()
+	apiv1.RegisterStateServer(server, a)
+	// reflection lets generic clients such as grpcurl discover the service
+	reflection.Register(server)
+	go func() { _ = server.Serve(listener) }()
+	return server.Stop, nil
+}
+
+// Watch sends the current nodes and pods, then every change until the subscriber leaves.
+// A change racing the snapshot may be sent twice, once in the snapshot and once as an event.
+func (a *apiServer) Watch(request *apiv1.WatchRequest, stream apiv1.State_WatchServer) error {
+	s := &apiSubscriber{
+		events:     make(chan *apiv1.Event, apiSubscriberBuffer),
+		namespaces: map[string]bool{},
+		dropped:    make(chan struct{}),
+	}
+	for _, namespace := range request.Namespaces {
+		s.namespaces[namespace] = true
+	}
+	a.mu.Lock()
+	a.subscribers[s] = struct{}{}
+	a.mu.Unlock()
+	defer func() {
+		a.mu.Lock()
+		delete(a.subscribers, s)
+		a.mu.Unlock()
+	}()
+	for _, e := range a.snapshot() {
+		if !s.wants(e) {
+			continue
+		}
+		if err := stream.Send(e); err != nil {
+			return err
+		}
+	}
+	if err := stream.Send(&apiv1.Event{Type: apiv1.EventSynced}); err != nil {
+		return err
+	}
+	for {
+		select {
+		case e := <-s.events:
+			if err := stream.Send(e); err != nil {
+				return err
+			}
+		case <-s.dropped:
+			return status.Error(codes.ResourceExhausted, fmt.Sprintf("fell more than %d events behind", apiSubscriberBuffer))
+		case <-stream.Context().Done():
+			return nil
+		}
+	}
+}
+
+// snapshot returns the current nodes and pods as snapshot events
+func (a *apiServer) snapshot() []*apiv1.Event {
+	var events []*apiv1.Event
+	for _, obj := range a.store.Nodes.List() {
+		events = append(events, &apiv1.Event{Type: apiv1.EventSnapshot, Node: apiv1.NodeOf(obj.(*corev1.Node))})
+	}
+	for _, obj := range a.store.Pods.List() {
+		events = append(events, &apiv1.Event{Type: apiv1.EventSnapshot, Pod: apiv1.PodOf(obj.(*corev1.Pod))})
+	}
+	return events
+}
+
+// publish hands the event to every subscriber that wants it, dropping those that fell behind
+func (a *apiServer) publish(e *apiv1.Event) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for s := range a.subscribers {
+		if !s.wants(e) {
+			continue
+		}
+		select {
+		case s.events <- e:
+		default:
+			close(s.dropped)
+			delete(a.subscribers, s)
+		}
+	}
+}
+
+// handler returns informer callbacks publishing node and pod changes
+func (a *apiServer) handler() cache.ResourceEventHandlerFuncs {
+	publish := func(eventType apiv1.EventType, obj interface{}) {
+		if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+			obj = tombstone.Obj
+		}
+		switch obj := obj.(type) {
+		case *corev1.Node:
+			a.publish(&apiv1.Event{Type: eventType, Node: apiv1.NodeOf(obj)})
+		case *corev1.Pod:
+			a.publish(&apiv1.Event{Type: eventType, Pod: apiv1.PodOf(obj)})
+		}
+	}
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { publish(apiv1.EventAdded, obj) },
+		UpdateFunc: func(_, obj interface{}) { publish(apiv1.EventUpdated, obj) },
+		DeleteFunc: func(obj interface{}) { publish(apiv1.EventDeleted, obj) },
+	}
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// auditEntry records the outcome of a mutating action
+type auditEntry struct {
+	at          time.Time
+	description string
+	kubectl     string
+	err         error
+}
+
+func (e auditEntry) result() string {
+	if e.err != nil {
+		return "failed: " + e.err.Error()
+	}
+	return "ok"
+}
+
+// audit records a finished action in the session log and the --audit-log file
+func (m *Model) audit(msg actionResultMsg) {
+	entry := auditEntry{at: time.Now(), description: msg.action.description, kubectl: msg.action.kubectl, err: msg.err}
+	m.auditLog = append(m.auditLog, entry)
+	if m.auditFile == nil {
+		return
+	}
+	if _, err := fmt.Fprintf(m.auditFile, "%s\t%s\t%s\n", entry.at.Format(time.RFC3339), entry.description, entry.result()); err != nil {
+		m.auditFile = nil
+	}
+}
+
+// auditPanel renders the session's actions, newest first
+func (m *Model) auditPanel() string {
+	if len(m.auditLog) == 0 {
+		return "No actions taken this session"
+	}
+	var table strings.Builder
+	w := tabwriter.NewWriter(&table, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TIME\tACTION\tRESULT\tKUBECTL\t")
+	for i := len(m.auditLog) - 1; i >= 0; i-- {
+		entry := m.auditLog[i]
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t\n", entry.at.Format("15:04:05"), entry.description, entry.result(), entry.kubectl)
+	}
+	w.Flush()
+	return strings.TrimRight(table.String(), "\n")
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	certificatesv1 "k8s.io/api/certificates/v1"
+
+	"github.com/bwagner5/kube-demo/pkg/format"
+)
+
+// certWarning is how close to expiry an issued certificate is flagged
+const certWarning = 30 * 24 * time.Hour
+
+// certificate is the latest CSR of a requester and signer
+type certificate struct {
+	csr       *certificatesv1.CertificateSigningRequest
+	state     string
+	notAfter  time.Time
+	requester string
+}
+
+// kubeletNode returns the node a kubelet serving certificate belongs to, or ""
+func (c certificate) kubeletNode() string {
+	if c.csr.Spec.SignerName != certificatesv1.KubeletServingSignerName {
+		return ""
+	}
+	return strings.TrimPrefix(c.requester, "system:node:")
+}
+
+// warning describes why the certificate needs attention, or returns "" if it does not
+func (c certificate) warning() string {
+	switch {
+	case c.state == "Denied" || c.state == "Failed":
+		return strings.ToLower(c.state)
+	case c.state == "Pending" && time.Since(c.csr.CreationTimestamp.Time) > time.Hour:
+		return "pending approval for " + format.Duration(time.Since(c.csr.CreationTimestamp.Time))
+	case c.notAfter.IsZero():
+		return ""
+	case time.Until(c.notAfter) <= 0:
+		return "expired"
+	case time.Until(c.notAfter) < certWarning:
+		return "expires " + format.Until(c.notAfter)
+	}
+	return ""
+}
+
+// csrState summarizes the CSR conditions and issued certificate
+func csrState(csr *certificatesv1.CertificateSigningRequest) string {
+	for _, condition := range csr.Status.Conditions {
+		switch condition.Type {
+		case certificatesv1.CertificateDenied, certificatesv1.CertificateFailed:
+			return string(condition.Type)
+		}
+	}
+	if len(csr.Status.Certificate) > 0 {
+		return "Issued"
+	}
+	for _, condition := range csr.Status.Conditions {
+		if condition.Type == certificatesv1.CertificateApproved {
+			return "Approved"
+		}
+	}
+	return "Pending"
+}
+
+// certificateExpiry returns the NotAfter of the first certificate in the PEM bundle
+func certificateExpiry(data []byte) time.Time {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return time.Time{}
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return time.Time{}
+	}
+	return cert.NotAfter
+}
+
+// getCertificates returns the newest CSR per requester and signer, those needing attention first
+func (m *Model) getCertificates() []certificate {
+	latest := map[string]certificate{}
+	for _, obj := range m.store.CertificateSigningRequests.List() {
+		csr := obj.(*certificatesv1.CertificateSigningRequest)
+		key := csr.Spec.SignerName + "/" + csr.Spec.Username
+		if current, ok := latest[key]; ok && current.csr.CreationTimestamp.After(csr.CreationTimestamp.Time) {
+			continue
+		}
+		latest[key] = certificate{csr: csr, state: csrState(csr), notAfter: certificateExpiry(csr.Status.Certificate), requester: csr.Spec.Username}
+	}
+	certs := make([]certificate, 0, len(latest))
+	for _, c := range latest {
+		certs = append(certs, c)
+	}
+	sort.Slice(certs, func(i, j int) bool {
+		if wi, wj := certs[i].warning() != "", certs[j].warning() != ""; wi != wj {
+			return wi
+		}
+		return certs[i].csr.Name < certs[j].csr.Name
+	})
+	return certs
+}
+
+// certificates renders the certificate warnings panel
+func (m *Model) certificates() string {
+	certs := m.getCertificates()
+	if len(certs) == 0 {
+		return "No CertificateSigningRequests"
+	}
+	var table strings.Builder
+	w := tabwriter.NewWriter(&table, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "REQUESTER\tSIGNER\tSTATE\tEXPIRES\tWARNING\t")
+	for _, c := range certs {
+		expires := "-"
+		if !c.notAfter.IsZero() {
+			expires = c.notAfter.Format("2006-01-02")
+		}
+		warning := c.warning()
+		if warning != "" {
+			warning = pullErrorStyle.Render(warning)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t\n", c.requester, c.csr.Spec.SignerName, c.state, expires, warning)
+	}
+	w.Flush()
+	return strings.TrimRight(table.String(), "\n")
+}
+
+// certificateBadges flags the node's kubelet serving certificate when it needs attention
+func (m *Model) certificateBadges(nodeName string) []string {
+	var badges []string
+	for _, c := range m.certs {
+		if c.kubeletNode() == nodeName {
+			if warning := c.warning(); warning != "" {
+				badges = append(badges, "kubelet cert "+warning)
+			}
+		}
+	}
+	return badges
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+// chaosReason marks node conditions faked by the chaos menu
+const chaosReason = "KubeDemoChaos"
+
+// chaosMenu offers failure injection actions. Pod actions only ever touch
+// namespaces in the --chaos-namespaces allowlist.
+func (m *Model) chaosMenu() tea.Cmd {
+	if len(m.chaosNamespaces) == 0 {
+		return m.setStatus("chaos is disabled: pass --chaos-namespaces to allow it")
+	}
+	m.menu = &menu{title: "chaos", items: []menuItem{
+		{key: "1", label: "kill random pod", choose: m.killRandomPod},
+		{key: "2", label: "kill pods on node", choose: m.killNodePods},
+		{key: "3", label: "mark node NotReady", choose: m.markNodeNotReady},
+	}}
+	return nil
+}
+
+// chaosTargets returns the rendered pods in allowlisted namespaces, optionally limited to a node
+func (m *Model) chaosTargets(nodeName string) []*corev1.Pod {
+	pods := m.listPods()
+	if nodeName != "" {
+		pods = m.clusterState().PodsOn(nodeName)
+	}
+	var targets []*corev1.Pod
+	for _, p := range pods {
+		if m.chaosNamespaces[p.Namespace] && p.DeletionTimestamp == nil {
+			targets = append(targets, p)
+		}
+	}
+	return targets
+}
+
+func deletePod(p *corev1.Pod) action {
+	namespace, name := p.Namespace, p.Name
+	return action{
+		description: fmt.Sprintf("delete pod %s/%s", namespace, name),
+		kubectl:     fmt.Sprintf("kubectl delete pod %s -n %s", name, namespace),
+		requires:    []string{"delete pods"},
+		run: func(ctx context.Context, client kubernetes.Interface, dryRun []string) error {
+			return retry(ctx, func(ctx context.Context) error {
+				return client.CoreV1().Pods(namespace).Delete(ctx, name, metav1.DeleteOptions{DryRun: dryRun})
+			})
+		},
+	}
+}
+
+func (m *Model) killRandomPod() tea.Cmd {
+	targets := m.chaosTargets("")
+	if len(targets) == 0 {
+		return m.setStatus("chaos: no pods in allowed namespaces")
+	}
+	return m.runAction(deletePod(targets[rand.Intn(len(targets))]))
+}
+
+func (m *Model) killNodePods() tea.Cmd {
+	if len(m.getNodes()) == 0 {
+		return nil
+	}
+	targets := m.chaosTargets(m.getNodes()[m.selectedNode].Name)
+	if len(targets) == 0 {
+		return m.setStatus("chaos: no pods in allowed namespaces on this node")
+	}
+	actions := make([]action, 0, len(targets))
+	for _, p := range targets {
+		actions = append(actions, deletePod(p))
+	}
+	return m.runAction(actions...)
+}
+
+// markNodeNotReady overwrites the node's Ready condition. The kubelet restores
+// it on its next status update, so the outage only lasts a few seconds.
+func (m *Model) markNodeNotReady() tea.Cmd {
+	if len(m.getNodes()) == 0 {
+		return nil
+	}
+	name := m.getNodes()[m.selectedNode].Name
+	patch := fmt.Sprintf(`{"status":{"conditions":[{"type":"Ready","status":"False","reason":%q,"message":"marked NotReady by the kube-demo chaos menu","lastTransitionTime":%q}]}}`,
+		chaosReason, time.Now().UTC().Format(time.RFC3339))
+	return m.runAction(action{
+		description: fmt.Sprintf("mark node %s NotReady", name),
+		kubectl:     fmt.Sprintf("kubectl patch node %s --subresource=status --type=strategic -p '%s'", name, patch),
+		requires:    []string{"patch nodes/status"},
+		run: func(ctx context.Context, client kubernetes.Interface, dryRun []string) error {
+			return retry(ctx, func(ctx context.Context) error {
+				_, err := client.CoreV1().Nodes().Patch(ctx, name, types.StrategicMergePatchType, []byte(patch), metav1.PatchOptions{DryRun: dryRun}, "status")
+				return err
+			})
+		},
+	})
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/bwagner5/kube-demo/pkg/format"
+)
+
+// churnWindow is how far back pod creates and deletes are counted
+const churnWindow = 10 * time.Minute
+
+// churnTop is how many namespaces and nodes the churn panel lists
+const churnTop = 10
+
+// churnEvent is a pod created in a namespace, bound to a node or deleted.
+// Either namespace or node may be empty when the event only applies to one.
+type churnEvent struct {
+	at        time.Time
+	namespace string
+	node      string
+	created   bool
+}
+
+// churnTracker records pod creates and deletes for the session.
+// It is fed from informer goroutines, so access is guarded by a mutex.
+type churnTracker struct {
+	mu     sync.Mutex
+	start  time.Time
+	events []churnEvent
+}
+
+func newChurnTracker() *churnTracker {
+	return &churnTracker{start: time.Now()}
+}
+
+// handler returns informer callbacks that observe pod churn. Pods created
+// before the session started are the initial list, not churn.
+func (c *churnTracker) handler() cache.ResourceEventHandlerFuncs {
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if p := obj.(*corev1.Pod); p.CreationTimestamp.After(c.start) {
+				c.record(churnEvent{at: time.Now(), namespace: p.Namespace, node: p.Spec.NodeName, created: true})
+			}
+		},
+		UpdateFunc: func(oldObj, obj interface{}) {
+			// pods are usually created unscheduled, so they count towards their node once bound
+			if old, p := oldObj.(*corev1.Pod), obj.(*corev1.Pod); old.Spec.NodeName == "" && p.Spec.NodeName != "" {
+				c.record(churnEvent{at: time.Now(), node: p.Spec.NodeName, created: true})
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			if p, ok := obj.(*corev1.Pod); ok {
+				c.record(churnEvent{at: time.Now(), namespace: p.Namespace, node: p.Spec.NodeName})
+			}
+		},
+	}
+}
+
+func (c *churnTracker) record(e churnEvent) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cutoff := time.Now().Add(-churnWindow)
+	for len(c.events) > 0 && c.events[0].at.Before(cutoff) {
+		c.events = c.events[1:]
+	}
+	c.events = append(c.events, e)
+}
+
+// churnStats counts the creates and deletes of one namespace or node
+type churnStats struct {
+	name    string
+	created int
+	deleted int
+}
+
+// perMinute is the churn rate over the observed part of the window
+func (s churnStats) perMinute(window time.Duration) float64 {
+	return float64(s.created+s.deleted) / window.Minutes()
+}
+
+// stats aggregates the events in the window by namespace and by node, busiest first
+func (c *churnTracker) stats() (namespaces []churnStats, nodes []churnStats, window time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	window = time.Since(c.start)
+	if window > churnWindow {
+		window = churnWindow
+	}
+	cutoff := time.Now().Add(-churnWindow)
+	byNamespace, byNode := map[string]*churnStats{}, map[string]*churnStats{}
+	count := func(stats map[string]*churnStats, name string, created bool) {
+		if name == "" {
+			return
+		}
+		s, ok := stats[name]
+		if !ok {
+			s = &churnStats{name: name}
+			stats[name] = s
+		}
+		if created {
+			s.created++
+		} else {
+			s.deleted++
+		}
+	}
+	for _, e := range c.events {
+		if e.at.Before(cutoff) {
+			continue
+		}
+		count(byNamespace, e.namespace, e.created)
+		count(byNode, e.node, e.created)
+	}
+	return busiest(byNamespace), busiest(byNode), window
+}
+
+func busiest(stats map[string]*churnStats) []churnStats {
+	out := make([]churnStats, 0, len(stats))
+	for _, s := range stats {
+		out = append(out, *s)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if ci, cj := out[i].created+out[i].deleted, out[j].created+out[j].deleted; ci != cj {
+			return ci > cj
+		}
+		return out[i].name < out[j].name
+	})
+	if len(out) > churnTop {
+		out = out[:churnTop]
+	}
+	return out
+}
+
+// churnPanel renders the busiest namespaces and nodes by pod churn
+func (m *Model) churnPanel() string {
+	namespaces, nodes, window := m.churn.stats()
+	if len(namespaces) == 0 && len(nodes) == 0 {
+		return fmt.Sprintf("No pod churn in the last %s", format.Precise(window))
+	}
+	var out strings.Builder
+	fmt.Fprintf(&out, "Pod churn over the last %s\n\n", format.Precise(window))
+	for _, section := range []struct {
+		title string
+		stats []churnStats
+	}{{"NAMESPACE", namespaces}, {"NODE", nodes}} {
+		w := tabwriter.NewWriter(&out, 0, 0, 2, ' ', 0)
+		fmt.Fprintf(w, "%s\tCREATED\tDELETED\tPER MIN\t\n", section.title)
+		for _, s := range section.stats {
+			fmt.Fprintf(w, "%s\t%d\t%d\t%.1f\t\n", s.name, s.created, s.deleted, s.perMinute(window))
+		}
+		w.Flush()
+		out.WriteString("\n")
+	}
+	return strings.TrimRight(out.String(), "\n")
+}
+
+// snapshot returns a copy of the events still in the window
+func (c *churnTracker) snapshot() []churnEvent {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]churnEvent(nil), c.events...)
+}
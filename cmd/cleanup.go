@@ -0,0 +1,362 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/pflag"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes"
+	clientretry "k8s.io/client-go/util/retry"
+)
+
+// sessionLabel marks the objects a session created or changed, valued with the session id.
+// Nodes are changed by many sessions, the label only marks them as tracked and which
+// sessions changed them is in the trackedAnnotation.
+const sessionLabel = "kube-demo.dev/session"
+
+// trackedAnnotation records on a node the labels and taints each session set on it with
+// the values they had before, so cleanup restores those and touches nothing else. It holds
+// a JSON object of trackedChanges by session id.
+const trackedAnnotation = "kube-demo.dev/tracked"
+
+// trackedChanges are the labels and taints a session set on a node, valued with what they
+// were before the session first changed them, null for those the session added
+type trackedChanges struct {
+	Labels map[string]*string `json:"labels,omitempty"`
+	// Taints are keyed by key:Effect and valued with the previous value of the taint
+	Taints map[string]*string `json:"taints,omitempty"`
+}
+
+// newSessionID returns the id the objects created and changed in this session are labeled with
+func newSessionID() string {
+	return time.Now().UTC().Format("20060102-150405")
+}
+
+// sessionSelector selects the objects of the session, or of every session when empty
+func sessionSelector(session string) string {
+	if session == "" {
+		return sessionLabel
+	}
+	return sessionLabel + "=" + session
+}
+
+// tracked wraps the action so that, once it has run, the label or taint it set on the node
+// is recorded under the session for cleanup along with the value it had before
+func (m *Model) tracked(a action, nodeName string, label string, taint string) action {
+	run := a.run
+	session := m.session
+	a.run = func(ctx context.Context, client kubernetes.Interface, dryRun []string) error {
+		var previous *string
+		if err := retry(ctx, func(ctx context.Context) error {
+			n, err := client.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
+			if err == nil {
+				previous = previousValue(n, label, taint)
+			}
+			return err
+		}); err != nil {
+			return err
+		}
+		if err := run(ctx, client, dryRun); err != nil {
+			return err
+		}
+		return trackNodeChange(ctx, client, nodeName, session, label, taint, previous, dryRun)
+	}
+	return a
+}
+
+// previousValue returns the node's value of the label, or of the key:Effect taint, nil if it has none
+func previousValue(n *corev1.Node, label string, taint string) *string {
+	if label != "" {
+		if value, ok := n.Labels[label]; ok {
+			return &value
+		}
+		return nil
+	}
+	for _, t := range n.Spec.Taints {
+		if t.Key+":"+string(t.Effect) == taint {
+			value := t.Value
+			return &value
+		}
+	}
+	return nil
+}
+
+// trackNodeChange records the label key or taint under the session in the node's tracked changes
+// and labels it as tracked. Only the first change of a key in a session records its previous value.
+func trackNodeChange(ctx context.Context, client kubernetes.Interface, nodeName string, session string, label string, taint string, previous *string, dryRun []string) error {
+	return clientretry.RetryOnConflict(clientretry.DefaultRetry, func() error {
+		return retry(ctx, func(ctx context.Context) error {
+			n, err := client.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
+			if err != nil {
+				return err
+			}
+			sessions := trackedChangesOf(n)
+			changes := sessions[session]
+			if changes.Labels == nil {
+				changes.Labels = map[string]*string{}
+			}
+			if changes.Taints == nil {
+				changes.Taints = map[string]*string{}
+			}
+			if _, ok := changes.Labels[label]; label != "" && !ok {
+				changes.Labels[label] = previous
+			}
+			if _, ok := changes.Taints[taint]; taint != "" && !ok {
+				changes.Taints[taint] = previous
+			}
+			sessions[session] = changes
+			value, err := json.Marshal(sessions)
+			if err != nil {
+				return err
+			}
+			patch, err := json.Marshal(map[string]interface{}{"metadata": map[string]interface{}{
+				"resourceVersion": n.ResourceVersion,
+				"labels":          map[string]string{sessionLabel: session},
+				"annotations":     map[string]string{trackedAnnotation: string(value)},
+			}})
+			if err != nil {
+				return err
+			}
+			_, err = client.CoreV1().Nodes().Patch(ctx, nodeName, types.MergePatchType, patch, metav1.PatchOptions{DryRun: dryRun})
+			return err
+		})
+	})
+}
+
+// trackedChangesOf returns the changes recorded on the node by session, ignoring a malformed annotation
+func trackedChangesOf(n *corev1.Node) map[string]trackedChanges {
+	sessions := map[string]trackedChanges{}
+	if err := json.Unmarshal([]byte(n.Annotations[trackedAnnotation]), &sessions); err != nil {
+		return map[string]trackedChanges{}
+	}
+	return sessions
+}
+
+// cleanupAction removes what the session, or every session when empty, left behind: it
+// restores the labels and taints they set on nodes and deletes the deployments and
+// namespaces they created
+func cleanupAction(session string) action {
+	selector := sessionSelector(session)
+	kubectl := fmt.Sprintf("kubectl delete deployments,namespaces -A -l %s && %s cleanup", selector, programName())
+	if session != "" {
+		kubectl += " --session " + session
+	}
+	return action{
+		description: "clean up " + selector,
+		kubectl:     kubectl,
+		requires:    []string{"patch nodes", "delete deployments", "delete namespaces"},
+		run: func(ctx context.Context, client kubernetes.Interface, dryRun []string) error {
+			_, err := cleanup(ctx, client, session, dryRun)
+			return err
+		},
+	}
+}
+
+// cleanup reverts the tracked node changes and deletes the tracked objects of the session, or
+// of every session when empty, returning what it did
+func cleanup(ctx context.Context, client kubernetes.Interface, session string, dryRun []string) ([]string, error) {
+	var done []string
+	var nodes *corev1.NodeList
+	if err := retry(ctx, func(ctx context.Context) (err error) {
+		nodes, err = client.CoreV1().Nodes().List(ctx, metav1.ListOptions{LabelSelector: sessionLabel})
+		return err
+	}); err != nil {
+		return done, err
+	}
+	for _, n := range nodes.Items {
+		reverted, err := untrackNode(ctx, client, n.Name, session, dryRun)
+		if err != nil {
+			return done, fmt.Errorf("reverting node %s: %w", n.Name, err)
+		}
+		if reverted {
+			done = append(done, "reverted node "+n.Name)
+		}
+	}
+	list := metav1.ListOptions{LabelSelector: sessionSelector(session)}
+	deletion := metav1.DeleteOptions{DryRun: dryRun}
+	var deployments *appsv1.DeploymentList
+	if err := retry(ctx, func(ctx context.Context) (err error) {
+		deployments, err = client.AppsV1().Deployments(metav1.NamespaceAll).List(ctx, list)
+		return err
+	}); err != nil {
+		return done, err
+	}
+	for _, d := range deployments.Items {
+		if err := retry(ctx, func(ctx context.Context) error {
+			return client.AppsV1().Deployments(d.Namespace).Delete(ctx, d.Name, deletion)
+		}); err != nil {
+			return done, fmt.Errorf("deleting deployment %s/%s: %w", d.Namespace, d.Name, err)
+		}
+		done = append(done, fmt.Sprintf("deleted deployment %s/%s", d.Namespace, d.Name))
+	}
+	var namespaces *corev1.NamespaceList
+	if err := retry(ctx, func(ctx context.Context) (err error) {
+		namespaces, err = client.CoreV1().Namespaces().List(ctx, list)
+		return err
+	}); err != nil {
+		return done, err
+	}
+	for _, namespace := range namespaces.Items {
+		if err := retry(ctx, func(ctx context.Context) error {
+			return client.CoreV1().Namespaces().Delete(ctx, namespace.Name, deletion)
+		}); err != nil {
+			return done, fmt.Errorf("deleting namespace %s: %w", namespace.Name, err)
+		}
+		done = append(done, "deleted namespace "+namespace.Name)
+	}
+	return done, nil
+}
+
+// untrackNode reverts the labels and taints the session, or every session when empty, set on
+// the node, reporting whether it had any. A key a later session changed again keeps its
+// current value, and that session restores the value from before the reverted one instead.
+func untrackNode(ctx context.Context, client kubernetes.Interface, nodeName string, session string, dryRun []string) (reverted bool, err error) {
+	err = clientretry.RetryOnConflict(clientretry.DefaultRetry, func() error {
+		return retry(ctx, func(ctx context.Context) error {
+			n, err := client.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
+			if err != nil {
+				return err
+			}
+			sessions := trackedChangesOf(n)
+			var ids []string
+			for id := range sessions {
+				if session == "" || id == session {
+					ids = append(ids, id)
+				}
+			}
+			reverted = len(ids) > 0
+			if !reverted {
+				return nil
+			}
+			// session ids are timestamps, so reverting newest first leaves the oldest values
+			sort.Sort(sort.Reverse(sort.StringSlice(ids)))
+			labels, taints := map[string]*string{}, map[string]*string{}
+			for _, id := range ids {
+				changes := sessions[id]
+				delete(sessions, id)
+				for key, previous := range changes.Labels {
+					if later := laterSession(sessions, id, func(c trackedChanges) map[string]*string { return c.Labels }, key); later != "" {
+						sessions[later].Labels[key] = previous
+					} else {
+						labels[key] = previous
+					}
+				}
+				for key, previous := range changes.Taints {
+					if later := laterSession(sessions, id, func(c trackedChanges) map[string]*string { return c.Taints }, key); later != "" {
+						sessions[later].Taints[key] = previous
+					} else {
+						taints[key] = previous
+					}
+				}
+			}
+			metadata := map[string]interface{}{"resourceVersion": n.ResourceVersion, "labels": labels}
+			if len(sessions) == 0 {
+				// a null value removes the key in a merge patch
+				labels[sessionLabel] = nil
+				metadata["annotations"] = map[string]*string{trackedAnnotation: nil}
+			} else {
+				value, err := json.Marshal(sessions)
+				if err != nil {
+					return err
+				}
+				metadata["annotations"] = map[string]string{trackedAnnotation: string(value)}
+			}
+			patch, err := json.Marshal(map[string]interface{}{
+				"metadata": metadata,
+				"spec":     map[string]interface{}{"taints": restoreTaints(n.Spec.Taints, taints)},
+			})
+			if err != nil {
+				return err
+			}
+			_, err = client.CoreV1().Nodes().Patch(ctx, nodeName, types.MergePatchType, patch, metav1.PatchOptions{DryRun: dryRun})
+			return err
+		})
+	})
+	return reverted, err
+}
+
+// laterSession returns the oldest session after id that also changed the key, or ""
+func laterSession(sessions map[string]trackedChanges, id string, keys func(trackedChanges) map[string]*string, key string) string {
+	later := ""
+	for other, changes := range sessions {
+		if _, ok := keys(changes)[key]; ok && other > id && (later == "" || other < later) {
+			later = other
+		}
+	}
+	return later
+}
+
+// restoreTaints sets the key:Effect taints back to their previous values, removing those that had none
+func restoreTaints(current []corev1.Taint, previous map[string]*string) []corev1.Taint {
+	taints := []corev1.Taint{}
+	for _, t := range current {
+		if _, ok := previous[t.Key+":"+string(t.Effect)]; !ok {
+			taints = append(taints, t)
+		}
+	}
+	keys := make([]string, 0, len(previous))
+	for key := range previous {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if value := previous[key]; value != nil {
+			name, effect, _ := strings.Cut(key, ":")
+			taints = append(taints, corev1.Taint{Key: name, Value: *value, Effect: corev1.TaintEffect(effect)})
+		}
+	}
+	return taints
+}
+
+// confirmCleanup asks before cleaning up after the current session
+func (m *Model) confirmCleanup() tea.Cmd {
+	m.menu = &menu{title: fmt.Sprintf("revert the labels and taints and remove the workloads of session %s?", m.session), items: []menuItem{
+		{key: "y", label: "clean up", choose: func() tea.Cmd { return m.runConfirmedAction(cleanupAction(m.session)) }},
+		{key: "n", label: "cancel", choose: func() tea.Cmd { return m.setStatus("cancelled clean up") }},
+	}}
+	return nil
+}
+
+// runCleanup implements the cleanup subcommand, removing what every session, or the
+// one named with --session, left behind in the cluster
+func runCleanup(args []string) error {
+	configFlags := genericclioptions.NewConfigFlags(true)
+	flags := pflag.NewFlagSet("cleanup", pflag.ExitOnError)
+	configFlags.AddFlags(flags)
+	session := flags.String("session", "", "only clean up after the session with this id (defaults to every session)")
+	dryRun := flags.Bool("dry-run", false, "only print what would be cleaned up")
+	_ = flags.Parse(args)
+	config, err := configFlags.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+	client, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+	var dryRunAll []string
+	if *dryRun {
+		dryRunAll = []string{metav1.DryRunAll}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), actionTimeout)
+	defer cancel()
+	done, err := cleanup(ctx, client, *session, dryRunAll)
+	for _, line := range done {
+		fmt.Fprintln(os.Stdout, line)
+	}
+	if err == nil && len(done) == 0 {
+		fmt.Println("nothing to clean up")
+	}
+	return err
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// colorLegendTop is how many namespaces or label values the coloring legend names
+const colorLegendTop = 6
+
+// colorMode is what the pod box borders are colored by
+type colorMode int
+
+const (
+	colorByState colorMode = iota
+	colorByNamespace
+	colorByLabel
+	colorModeCount
+)
+
+func (c colorMode) String() string {
+	switch c {
+	case colorByNamespace:
+		return "namespace"
+	case colorByLabel:
+		return "label"
+	}
+	return "state"
+}
+
+// palette are the colors handed out to namespaces and label values, chosen to stay apart from each other on a black background
+var palette = []lipgloss.Color{
+	"#E6194B", "#3CB44B", "#FFE119", "#4363D8", "#F58231", "#911EB4",
+	"#46F0F0", "#F032E6", "#BCF60C", "#FABEBE", "#008080", "#E6BEFF",
+	"#9A6324", "#FFFAC8", "#AAFFC3", "#808000", "#FFD8B1", "#A9A9A9",
+}
+
+// paletteColor returns the color of a key, the same for the same key in every session
+func paletteColor(key string) lipgloss.Color {
+	hash := fnv.New32a()
+	hash.Write([]byte(key))
+	return palette[hash.Sum32()%uint32(len(palette))]
+}
+
+// colorKey returns what the pod is colored by in the active coloring mode, false if the pod
+// does not have the color label
+func (m *Model) colorKey(pod *corev1.Pod) (string, bool) {
+	if m.colorMode == colorByLabel {
+		value, ok := pod.Labels[m.colorLabel]
+		return value, ok
+	}
+	return pod.Namespace, true
+}
+
+// podColor picks the border color of a pod box in the active coloring mode.
+// Pods without the color label keep the default border.
+func (m *Model) podColor(pod *corev1.Pod, color lipgloss.TerminalColor) lipgloss.TerminalColor {
+	if m.colorMode == colorByState {
+		return m.podBorderColor(pod, color)
+	}
+	key, ok := m.colorKey(pod)
+	if !ok {
+		return nodeBorder
+	}
+	return paletteColor(key)
+}
+
+// cycleColorMode advances to the next coloring mode
+func (m *Model) cycleColorMode() tea.Cmd {
+	m.colorMode = (m.colorMode + 1) % colorModeCount
+	if m.colorMode == colorByLabel {
+		return m.setStatus(fmt.Sprintf("coloring pods by label %s", m.colorLabel))
+	}
+	return m.setStatus(fmt.Sprintf("coloring pods by %s", m.colorMode))
+}
+
+// colorLegend names the colors of the namespaces or label values with the most rendered pods
+func (m *Model) colorLegend() string {
+	counts := map[string]int{}
+	unlabeled := 0
+	for _, p := range m.listPods() {
+		key, ok := m.colorKey(p)
+		if !ok {
+			unlabeled++
+			continue
+		}
+		counts[key]++
+	}
+	keys := make([]string, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if counts[keys[i]] == counts[keys[j]] {
+			return keys[i] < keys[j]
+		}
+		return counts[keys[i]] > counts[keys[j]]
+	})
+	entries := []string{"colored by namespace:"}
+	if m.colorMode == colorByLabel {
+		entries = []string{fmt.Sprintf("colored by %s:", m.colorLabel)}
+		if len(keys) == 0 {
+			return fmt.Sprintf("no pods have the label %s, press h to color by state", m.colorLabel)
+		}
+	}
+	for i, key := range keys {
+		if i == colorLegendTop {
+			entries = append(entries, fmt.Sprintf("+%d more", len(keys)-colorLegendTop))
+			break
+		}
+		swatch := lipgloss.NewStyle().Foreground(paletteColor(key)).Render("■")
+		entries = append(entries, fmt.Sprintf("%s %s (%d)", swatch, key, counts[key]))
+	}
+	if unlabeled > 0 {
+		swatch := lipgloss.NewStyle().Foreground(nodeBorder).Render("■")
+		entries = append(entries, fmt.Sprintf("%s unlabeled (%d)", swatch, unlabeled))
+	}
+	return strings.Join(entries, "  ")
+}
@@ -0,0 +1,305 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/bwagner5/kube-demo/pkg/format"
+)
+
+var differenceStyle = lipgloss.NewStyle().Foreground(yellow)
+
+// comparedPodBorder marks the pods selected for comparison
+var comparedPodBorder = white
+
+// absent is shown for a field one side of a comparison does not have
+const absent = "-"
+
+// comparisonRow is a field of two compared objects
+type comparisonRow struct {
+	field string
+	a, b  string
+}
+
+// toggleComparison switches to the side-by-side comparison of the two selected pods, or
+// of the two selected nodes
+func (m *Model) toggleComparison() tea.Cmd {
+	if m.view == viewCompare {
+		m.toggleView(viewCompare)
+		return nil
+	}
+	switch {
+	case len(m.comparedPods()) == 2:
+		m.comparingPods = true
+	case len(m.batchNodes()) == 2:
+		m.comparingPods = false
+	default:
+		return m.setStatus("select exactly two nodes, or two pods, with space to compare them")
+	}
+	m.toggleView(viewCompare)
+	return nil
+}
+
+// comparison renders the active comparison
+func (m *Model) comparison() string {
+	if m.comparingPods {
+		pods := m.comparedPods()
+		if len(pods) != 2 {
+			return "Select exactly two pods with space to compare them"
+		}
+		return comparisonTable(podName(pods[0]), podName(pods[1]), m.podComparison(pods[0], pods[1]))
+	}
+	nodes := m.batchNodes()
+	if len(nodes) != 2 {
+		return "Select exactly two nodes with space to compare them"
+	}
+	return comparisonTable(nodes[0].Name, nodes[1].Name, m.nodeComparison(nodes[0], nodes[1]))
+}
+
+// comparisonTable lays the rows out side by side, marking and highlighting the fields that differ
+func comparisonTable(a, b string, rows []comparisonRow) string {
+	var table strings.Builder
+	w := tabwriter.NewWriter(&table, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, " \tFIELD\t%s\t%s\t\n", a, b)
+	differences := 0
+	for _, row := range rows {
+		mark := " "
+		if row.a != row.b {
+			mark = "≠"
+			differences++
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t\n", mark, row.field, row.a, row.b)
+	}
+	w.Flush()
+	lines := strings.Split(strings.TrimRight(table.String(), "\n"), "\n")
+	for i, row := range rows {
+		if row.a != row.b {
+			lines[i+1] = differenceStyle.Render(lines[i+1])
+		}
+	}
+	return strings.Join(lines, "\n") + fmt.Sprintf("\n\n%d of %d fields differ\n", differences, len(rows))
+}
+
+// mapRows compares two string maps key by key, such as labels, in key order
+func mapRows(prefix string, a, b map[string]string) []comparisonRow {
+	keys := map[string]bool{}
+	for key := range a {
+		keys[key] = true
+	}
+	for key := range b {
+		keys[key] = true
+	}
+	sorted := make([]string, 0, len(keys))
+	for key := range keys {
+		sorted = append(sorted, key)
+	}
+	sort.Strings(sorted)
+	rows := make([]comparisonRow, 0, len(sorted))
+	for _, key := range sorted {
+		row := comparisonRow{field: prefix + " " + key, a: absent, b: absent}
+		if value, ok := a[key]; ok {
+			row.a = value
+		}
+		if value, ok := b[key]; ok {
+			row.b = value
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// nodeComparison lists the fields of two nodes: system info, capacity and allocatable,
+// labels, taints and the workloads running on each
+func (m *Model) nodeComparison(a, b *corev1.Node) []comparisonRow {
+	rows := []comparisonRow{
+		{"kubelet version", a.Status.NodeInfo.KubeletVersion, b.Status.NodeInfo.KubeletVersion},
+		{"os image", a.Status.NodeInfo.OSImage, b.Status.NodeInfo.OSImage},
+		{"kernel", a.Status.NodeInfo.KernelVersion, b.Status.NodeInfo.KernelVersion},
+		{"container runtime", a.Status.NodeInfo.ContainerRuntimeVersion, b.Status.NodeInfo.ContainerRuntimeVersion},
+		{"architecture", a.Status.NodeInfo.Architecture, b.Status.NodeInfo.Architecture},
+	}
+	rows = append(rows, resourceRows("capacity", a.Status.Capacity, b.Status.Capacity)...)
+	rows = append(rows, resourceRows("allocatable", a.Status.Allocatable, b.Status.Allocatable)...)
+	rows = append(rows, mapRows("label", a.Labels, b.Labels)...)
+	rows = append(rows, mapRows("taint", taintsByKey(a), taintsByKey(b))...)
+	return append(rows, mapRows("workload", m.workloadCounts(a), m.workloadCounts(b))...)
+}
+
+// resourceRows compares two resource lists, in resource name order
+func resourceRows(prefix string, a, b corev1.ResourceList) []comparisonRow {
+	formatted := func(list corev1.ResourceList) map[string]string {
+		out := map[string]string{}
+		for name, quantity := range list {
+			out[string(name)] = format.Quantity(name, quantity)
+		}
+		return out
+	}
+	return mapRows(prefix, formatted(a), formatted(b))
+}
+
+// taintsByKey formats the node's taints as value:Effect by key
+func taintsByKey(n *corev1.Node) map[string]string {
+	taints := map[string]string{}
+	for _, taint := range n.Spec.Taints {
+		taints[taint.Key] = taint.Value + ":" + string(taint.Effect)
+	}
+	return taints
+}
+
+// workloadCounts counts the pods of each workload running on the node
+func (m *Model) workloadCounts(n *corev1.Node) map[string]string {
+	counts := map[string]int{}
+	for _, p := range m.getPods(n) {
+		counts[m.workloadOf(p).String()]++
+	}
+	out := make(map[string]string, len(counts))
+	for workload, count := range counts {
+		out[workload] = fmt.Sprintf("%d pods", count)
+		if count == 1 {
+			out[workload] = "1 pod"
+		}
+	}
+	return out
+}
+
+// podName is the namespace/name of the pod
+func podName(p *corev1.Pod) string {
+	return p.Namespace + "/" + p.Name
+}
+
+// podComparison lists the fields of two pods that explain a rollout: the workload and
+// revision, then the image, command, resources and environment of each container by name,
+// the oldest pod being the left one
+func (m *Model) podComparison(a, b *corev1.Pod) []comparisonRow {
+	rows := []comparisonRow{
+		{"workload", m.workloadOf(a).String(), m.workloadOf(b).String()},
+		{"revision", podRevision(a), podRevision(b)},
+		{"node", orAbsent(a.Spec.NodeName), orAbsent(b.Spec.NodeName)},
+		{"phase", string(a.Status.Phase), string(b.Status.Phase)},
+		{"service account", orAbsent(a.Spec.ServiceAccountName), orAbsent(b.Spec.ServiceAccountName)},
+	}
+	containersA, containersB := containersByName(a), containersByName(b)
+	for _, name := range containerNames(a, b) {
+		ca, cb := containersA[name], containersB[name]
+		prefix := "container " + name
+		rows = append(rows,
+			comparisonRow{prefix + " image", containerField(ca, func(c *corev1.Container) string { return c.Image }), containerField(cb, func(c *corev1.Container) string { return c.Image })},
+			comparisonRow{prefix + " command", containerField(ca, containerCommand), containerField(cb, containerCommand)},
+		)
+		var requestsA, requestsB, limitsA, limitsB corev1.ResourceList
+		if ca != nil {
+			requestsA, limitsA = ca.Resources.Requests, ca.Resources.Limits
+		}
+		if cb != nil {
+			requestsB, limitsB = cb.Resources.Requests, cb.Resources.Limits
+		}
+		rows = append(rows, resourceRows(prefix+" requests", requestsA, requestsB)...)
+		rows = append(rows, resourceRows(prefix+" limits", limitsA, limitsB)...)
+		rows = append(rows, mapRows(prefix+" env", containerEnv(ca), containerEnv(cb))...)
+	}
+	return append(rows, mapRows("label", a.Labels, b.Labels)...)
+}
+
+// podRevision is the revision of the workload template the pod was created from
+func podRevision(p *corev1.Pod) string {
+	for _, label := range []string{appsv1.DefaultDeploymentUniqueLabelKey, appsv1.ControllerRevisionHashLabelKey} {
+		if revision, ok := p.Labels[label]; ok {
+			return revision
+		}
+	}
+	return absent
+}
+
+func orAbsent(value string) string {
+	if value == "" {
+		return absent
+	}
+	return value
+}
+
+// containersByName indexes the init and regular containers of the pod, init ones prefixed with init:
+func containersByName(p *corev1.Pod) map[string]*corev1.Container {
+	containers := map[string]*corev1.Container{}
+	for i := range p.Spec.InitContainers {
+		containers["init:"+p.Spec.InitContainers[i].Name] = &p.Spec.InitContainers[i]
+	}
+	for i := range p.Spec.Containers {
+		containers[p.Spec.Containers[i].Name] = &p.Spec.Containers[i]
+	}
+	return containers
+}
+
+// containerNames lists the containers of either pod, in the order of the first pod then
+// the containers only the second one has
+func containerNames(a, b *corev1.Pod) []string {
+	var names []string
+	seen := map[string]bool{}
+	for _, p := range []*corev1.Pod{a, b} {
+		for _, c := range p.Spec.InitContainers {
+			if name := "init:" + c.Name; !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+		for _, c := range p.Spec.Containers {
+			if !seen[c.Name] {
+				seen[c.Name] = true
+				names = append(names, c.Name)
+			}
+		}
+	}
+	return names
+}
+
+// containerField reads a field of a container the pod may not have
+func containerField(c *corev1.Container, field func(*corev1.Container) string) string {
+	if c == nil {
+		return absent
+	}
+	return orAbsent(field(c))
+}
+
+// containerCommand joins the command and arguments of the container
+func containerCommand(c *corev1.Container) string {
+	return strings.Join(append(append([]string{}, c.Command...), c.Args...), " ")
+}
+
+// containerEnv formats the container's environment variables by name, naming the source of
+// those read from secrets, config maps or fields rather than resolving them
+func containerEnv(c *corev1.Container) map[string]string {
+	env := map[string]string{}
+	if c == nil {
+		return env
+	}
+	for _, e := range c.Env {
+		source := e.ValueFrom
+		switch {
+		case source == nil:
+			env[e.Name] = e.Value
+		case source.SecretKeyRef != nil:
+			env[e.Name] = fmt.Sprintf("secret %s/%s", source.SecretKeyRef.Name, source.SecretKeyRef.Key)
+		case source.ConfigMapKeyRef != nil:
+			env[e.Name] = fmt.Sprintf("config map %s/%s", source.ConfigMapKeyRef.Name, source.ConfigMapKeyRef.Key)
+		case source.FieldRef != nil:
+			env[e.Name] = "field " + source.FieldRef.FieldPath
+		case source.ResourceFieldRef != nil:
+			env[e.Name] = "resource " + source.ResourceFieldRef.Resource
+		}
+	}
+	for _, from := range c.EnvFrom {
+		switch {
+		case from.SecretRef != nil:
+			env[from.Prefix+"*"] = "secret " + from.SecretRef.Name
+		case from.ConfigMapRef != nil:
+			env[from.Prefix+"*"] = "config map " + from.ConfigMapRef.Name
+		}
+	}
+	return env
+}
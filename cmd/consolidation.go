@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/bwagner5/kube-demo/pkg/binpack"
+	"github.com/bwagner5/kube-demo/pkg/node"
+	"github.com/bwagner5/kube-demo/pkg/pod"
+)
+
+var consolidationBorder = purple
+
+// consolidationCandidates returns the nodes whose movable pods would all fit on
+// the other schedulable nodes. Like Karpenter, nodes running pods without a
+// controller are never candidates since deleting them would lose the pod.
+func (m *Model) consolidationCandidates() map[string]bool {
+	nodes := m.getNodes()
+	bins := make([]*binpack.Bin, len(nodes))
+	for i, n := range nodes {
+		bins[i] = binpack.NewBin(n, m.getPods(n))
+	}
+	candidates := map[string]bool{}
+	for i, n := range nodes {
+		requests, movable := m.podsToMove(n)
+		if !movable {
+			continue
+		}
+		var others []*binpack.Bin
+		for j, other := range nodes {
+			if j != i && node.IsReady(other) && !node.IsCordoned(other) {
+				others = append(others, bins[j])
+			}
+		}
+		if binpack.PlaceAll(others, requests) {
+			candidates[n.Name] = true
+		}
+	}
+	return candidates
+}
+
+// podsToMove returns the requests of the pods that would be rescheduled if the
+// node were removed, and false if a pod on it cannot be moved
+func (m *Model) podsToMove(n *corev1.Node) ([]corev1.ResourceList, bool) {
+	var requests []corev1.ResourceList
+	for _, p := range m.getPods(n) {
+		if !drainable(p) {
+			continue
+		}
+		if metav1.GetControllerOf(p) == nil {
+			return nil, false
+		}
+		requests = append(requests, pod.Requests(p))
+	}
+	return requests, true
+}
+
+// consolidationBorderOf colors consolidation candidates while the overlay is on
+func (m *Model) consolidationBorderOf(nodeName string, color lipgloss.TerminalColor) lipgloss.TerminalColor {
+	if m.consolidation[nodeName] {
+		return consolidationBorder
+	}
+	return color
+}
+
+// consolidationBadges marks consolidation candidates while the overlay is on
+func (m *Model) consolidationBadges(nodeName string) []string {
+	if !m.consolidation[nodeName] {
+		return nil
+	}
+	return []string{"consolidation candidate"}
+}
+
+// toggleConsolidation turns the consolidation candidate overlay on or off
+func (m *Model) toggleConsolidation() tea.Cmd {
+	if m.consolidation != nil {
+		m.consolidation = nil
+		return nil
+	}
+	m.consolidation = m.consolidationCandidates()
+	return m.setStatus(fmt.Sprintf("%d of %d nodes could be consolidated", len(m.consolidation), len(m.getNodes())))
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/bwagner5/kube-demo/pkg/format"
+	"github.com/bwagner5/kube-demo/pkg/pod"
+)
+
+// controlPlaneComponents are the component label values of kubeadm style control plane static pods
+var controlPlaneComponents = []string{"kube-apiserver", "kube-scheduler", "kube-controller-manager", "etcd"}
+
+// controlPlaneComponent returns the control plane component the pod runs, or "" if it is not one
+func controlPlaneComponent(p *corev1.Pod) string {
+	if p.Namespace != metav1.NamespaceSystem {
+		return ""
+	}
+	for _, component := range controlPlaneComponents {
+		// kubeadm labels static pods component=<name>, other installers only name them <name>-<node>
+		if p.Labels["component"] == component || strings.HasPrefix(p.Name, component+"-") {
+			return component
+		}
+	}
+	return ""
+}
+
+// getControlPlanePods returns the control plane pods sorted by component and node
+func (m *Model) getControlPlanePods() []*corev1.Pod {
+	var pods []*corev1.Pod
+	for _, p := range m.listPods() {
+		if controlPlaneComponent(p) != "" {
+			pods = append(pods, p)
+		}
+	}
+	sort.Slice(pods, func(i, j int) bool {
+		if ci, cj := controlPlaneComponent(pods[i]), controlPlaneComponent(pods[j]); ci != cj {
+			return ci < cj
+		}
+		return pods[i].Spec.NodeName < pods[j].Spec.NodeName
+	})
+	return pods
+}
+
+// controlPlane renders the control plane pods with their health
+func (m *Model) controlPlane() string {
+	pods := m.getControlPlanePods()
+	if len(pods) == 0 {
+		return "No control plane pods visible: the control plane may be managed by your provider, or hidden by --namespace/--selector"
+	}
+	var table strings.Builder
+	w := tabwriter.NewWriter(&table, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "COMPONENT\tNODE\tPOD\tREADY\tRESTARTS\tAGE\t")
+	for _, p := range pods {
+		ready := "yes"
+		if !pod.IsReady(p) {
+			ready = pullErrorStyle.Render(string(p.Status.Phase))
+		}
+		restarts := int32(0)
+		for _, status := range p.Status.ContainerStatuses {
+			restarts += status.RestartCount
+		}
+		_, static := p.Annotations[corev1.MirrorPodAnnotationKey]
+		name := p.Name
+		if static {
+			name += " (static)"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%s\t\n", controlPlaneComponent(p), p.Spec.NodeName, name, ready, restarts,
+			format.Duration(time.Since(p.CreationTimestamp.Time)))
+	}
+	w.Flush()
+	return strings.TrimRight(table.String(), "\n")
+}
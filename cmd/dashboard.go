@@ -0,0 +1,233 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/bwagner5/kube-demo/pkg/format"
+	"github.com/bwagner5/kube-demo/pkg/node"
+)
+
+// dashboardTop is how many namespaces and events the dashboard lists
+const dashboardTop = 8
+
+// dashboardBarWidth is the width of the longest bar in a dashboard widget
+const dashboardBarWidth = 20
+
+var widgetStyle = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(grey).Padding(0, 1).Margin(0, 1, 1, 0)
+
+var widgetTitleStyle = lipgloss.NewStyle().Bold(true)
+
+// ringCells are the row and column of each cell of the donut, clockwise from the top
+var ringCells = [][2]int{
+	{0, 3}, {0, 4}, {0, 5}, {0, 6}, {0, 7}, {1, 9}, {2, 10}, {3, 9},
+	{4, 7}, {4, 6}, {4, 5}, {4, 4}, {4, 3}, {3, 1}, {2, 0}, {1, 1},
+}
+
+// segment is a labeled share of a donut or bar chart
+type segment struct {
+	label string
+	count int
+	color lipgloss.TerminalColor
+	// symbol draws the segment in a donut, so segments stay apart in monochrome mode
+	symbol string
+}
+
+// dashboard renders the cluster at a glance, as a landing page before the node grid
+func (m *Model) dashboard() string {
+	widgets := []string{m.nodesWidget(), m.podsWidget(), m.namespacesWidget()}
+	return lipgloss.JoinVertical(lipgloss.Left,
+		lipgloss.JoinHorizontal(lipgloss.Top, widgets...),
+		m.eventsWidget(),
+		breadcrumbStyle.Render("press enter for the node grid"),
+	)
+}
+
+func widget(title string, body string) string {
+	return widgetStyle.Render(widgetTitleStyle.Render(title) + "\n\n" + body)
+}
+
+// nodeStatusCounts counts the rendered nodes by status, NotReady taking precedence over cordoned
+func (m *Model) nodeStatusCounts() (ready int, cordoned int, notReady int) {
+	for _, n := range m.getNodes() {
+		switch {
+		case !node.IsReady(n):
+			notReady++
+		case node.IsCordoned(n):
+			cordoned++
+		default:
+			ready++
+		}
+	}
+	return ready, cordoned, notReady
+}
+
+// podPhaseCounts counts the rendered pods by phase
+func (m *Model) podPhaseCounts() map[corev1.PodPhase]int {
+	phases := map[corev1.PodPhase]int{}
+	for _, p := range m.listPods() {
+		phases[p.Status.Phase]++
+	}
+	return phases
+}
+
+// nodesWidget charts the nodes by status as a donut
+func (m *Model) nodesWidget() string {
+	ready, cordoned, notReady := m.nodeStatusCounts()
+	segments := []segment{{"Ready", ready, green, "●"}, {"Cordoned", cordoned, yellow, "●"}, {"NotReady", notReady, red, "●"}}
+	if m.monochrome {
+		segments[0].symbol, segments[1].symbol, segments[2].symbol = "●", "◐", "○"
+	}
+	legend := make([]string, 0, len(segments))
+	for _, s := range segments {
+		legend = append(legend, fmt.Sprintf("%s %-9s %d", lipgloss.NewStyle().Foreground(s.color).Render(s.symbol), s.label, s.count))
+	}
+	chart := lipgloss.JoinHorizontal(lipgloss.Center, donut(segments, len(m.getNodes())), "   ", strings.Join(legend, "\n"))
+	return widget("Nodes", chart)
+}
+
+// donut draws the segments as shares of a ring with the total in the middle
+func donut(segments []segment, total int) string {
+	grid := make([][]string, 5)
+	for r := range grid {
+		grid[r] = strings.Split(strings.Repeat(" ", 11), "")
+	}
+	label := fmt.Sprint(total)
+	if len(label) <= 9 {
+		start := 1 + (9-len(label))/2
+		for i, c := range label {
+			grid[2][start+i] = string(c)
+		}
+	}
+	for i, cell := range ringCells {
+		color, symbol := lipgloss.TerminalColor(grey), "·"
+		if total > 0 {
+			// the cell takes the color of the segment its midpoint falls in
+			position := (float64(i) + 0.5) / float64(len(ringCells)) * float64(total)
+			cumulative := 0
+			for _, s := range segments {
+				cumulative += s.count
+				if position < float64(cumulative) {
+					color, symbol = s.color, s.symbol
+					break
+				}
+			}
+		}
+		grid[cell[0]][cell[1]] = lipgloss.NewStyle().Foreground(color).Render(symbol)
+	}
+	rows := make([]string, 0, len(grid))
+	for _, row := range grid {
+		rows = append(rows, strings.Join(row, ""))
+	}
+	return strings.Join(rows, "\n")
+}
+
+// podsWidget charts the pods by phase as bars
+func (m *Model) podsWidget() string {
+	phases := m.podPhaseCounts()
+	phaseColors := map[corev1.PodPhase]lipgloss.TerminalColor{
+		corev1.PodRunning: green, corev1.PodPending: yellow, corev1.PodSucceeded: blue, corev1.PodFailed: red, corev1.PodUnknown: grey,
+	}
+	segments := make([]segment, 0, len(podPhases))
+	for _, phase := range podPhases {
+		segments = append(segments, segment{label: string(phase), count: phases[phase], color: phaseColors[phase]})
+	}
+	return widget("Pods", bars(segments))
+}
+
+// bars draws a bar per segment scaled to the largest one
+func bars(segments []segment) string {
+	largest := 0
+	for _, s := range segments {
+		if s.count > largest {
+			largest = s.count
+		}
+	}
+	lines := make([]string, 0, len(segments))
+	for _, s := range segments {
+		width := 0
+		if largest > 0 {
+			width = s.count * dashboardBarWidth / largest
+		}
+		bar := lipgloss.NewStyle().Foreground(s.color).Render(strings.Repeat("█", width))
+		lines = append(lines, fmt.Sprintf("%-10s %s%s %d", s.label, bar, strings.Repeat(" ", dashboardBarWidth-width), s.count))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// namespacesWidget lists the namespaces requesting the most CPU
+func (m *Model) namespacesWidget() string {
+	namespaces := m.getNamespaces()
+	sort.SliceStable(namespaces, func(i, j int) bool {
+		return namespaces[i].Requests.Cpu().Cmp(*namespaces[j].Requests.Cpu()) > 0
+	})
+	if len(namespaces) > dashboardTop {
+		namespaces = namespaces[:dashboardTop]
+	}
+	if len(namespaces) == 0 {
+		return widget("Top namespaces by requests", "No pods")
+	}
+	lines := []string{fmt.Sprintf("%-20s %8s %8s", "NAMESPACE", "CPU", "MEMORY")}
+	for _, namespace := range namespaces {
+		lines = append(lines, fmt.Sprintf("%-20s %8s %8s", truncate(namespace.Name, 20), format.CPU(*namespace.Requests.Cpu()), format.Bytes(*namespace.Requests.Memory())))
+	}
+	return widget("Top namespaces by requests", strings.Join(lines, "\n"))
+}
+
+// recentEvents returns up to limit of the cluster's events matching keep, newest first
+func (m *Model) recentEvents(limit int, keep func(*corev1.Event) bool) []*corev1.Event {
+	var events []*corev1.Event
+	for _, obj := range m.store.Events.List() {
+		if event := obj.(*corev1.Event); keep(event) {
+			events = append(events, event)
+		}
+	}
+	sort.Slice(events, func(i, j int) bool { return eventTime(events[i]).After(eventTime(events[j])) })
+	if len(events) > limit {
+		events = events[:limit]
+	}
+	return events
+}
+
+// eventsWidget lists the most recent events in the cluster, warnings highlighted
+func (m *Model) eventsWidget() string {
+	events := m.recentEvents(dashboardTop, func(*corev1.Event) bool { return true })
+	if len(events) == 0 {
+		return widget("Recent events", "No events")
+	}
+	lines := make([]string, 0, len(events))
+	for _, event := range events {
+		line := fmt.Sprintf("%4s  %-20s %s/%s: %s", format.Duration(time.Since(eventTime(event))), truncate(event.Reason, 20),
+			strings.ToLower(event.InvolvedObject.Kind), event.InvolvedObject.Name, truncate(event.Message, 80))
+		if event.Type == corev1.EventTypeWarning {
+			line = pullErrorStyle.Render(line)
+		}
+		lines = append(lines, line)
+	}
+	return widget("Recent events", strings.Join(lines, "\n"))
+}
+
+// eventTime returns when the event last happened
+func eventTime(event *corev1.Event) time.Time {
+	if !event.LastTimestamp.IsZero() {
+		return event.LastTimestamp.Time
+	}
+	if !event.EventTime.IsZero() {
+		return event.EventTime.Time
+	}
+	return event.CreationTimestamp.Time
+}
+
+// truncate shortens s to at most width runes, marking the cut with an ellipsis
+func truncate(s string, width int) string {
+	runes := []rune(s)
+	if len(runes) <= width {
+		return s
+	}
+	return string(runes[:width-1]) + "…"
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// startTarget is the object the session opens navigated to, from --focus-node,
+// --focus-workload or a --restore state file
+type startTarget struct {
+	node string
+	// pod is namespace/name of a pod to select
+	pod string
+	// workload is namespace/name of a workload whose first scheduled pod is selected
+	workload string
+	// details opens the details of the target
+	details bool
+}
+
+// newStartTarget returns the start target of the options, or nil if there is none
+func newStartTarget(opts Options) *startTarget {
+	switch {
+	case opts.FocusNode != "" || opts.FocusWorkload != "":
+		return &startTarget{node: opts.FocusNode, workload: opts.FocusWorkload, details: true}
+	case opts.Restore != nil && opts.Restore.Node != "":
+		return &startTarget{node: opts.Restore.Node, pod: opts.Restore.Pod, details: opts.Restore.Details}
+	}
+	return nil
+}
+
+// parseWorkloadTarget splits a --focus-workload namespace/name
+func parseWorkloadTarget(target string) (string, string, error) {
+	namespace, name, ok := strings.Cut(target, "/")
+	if !ok || namespace == "" || name == "" || strings.Contains(name, "/") {
+		return "", "", fmt.Errorf("expected namespace/name, got %q", target)
+	}
+	return namespace, name, nil
+}
+
+// openStartTarget navigates to the start target and opens its details, once the caches
+// have synced. It only ever runs once per session.
+func (m *Model) openStartTarget() tea.Cmd {
+	target := m.startTarget
+	if target == nil {
+		return nil
+	}
+	m.startTarget = nil
+	bookmark := mark{nodeName: target.node}
+	switch {
+	case target.workload != "":
+		p := m.firstWorkloadPod(target.workload)
+		if p == nil {
+			return m.setStatus(fmt.Sprintf("--focus-workload: no scheduled pods of %s", target.workload))
+		}
+		bookmark = mark{nodeName: p.Spec.NodeName, podUID: p.UID}
+	case target.pod != "":
+		obj, ok, _ := m.store.Pods.GetByKey(target.pod)
+		if !ok {
+			m.navigateTo(bookmark)
+			return m.setStatus(fmt.Sprintf("pod %s no longer exists, selected its node", target.pod))
+		}
+		bookmark.podUID = obj.(*corev1.Pod).UID
+	}
+	nodeFound, podFound := m.navigateTo(bookmark)
+	switch {
+	case !nodeFound:
+		return m.setStatus(fmt.Sprintf("node %s is not shown", bookmark.nodeName))
+	case bookmark.podUID != "" && !podFound:
+		return m.setStatus("the pod to open is hidden by the active filter")
+	case !target.details:
+		return nil
+	}
+	m.pushFocus(focusDetails)
+	m.resetTabs()
+	return m.loadDetails()
+}
+
+// firstWorkloadPod returns the scheduled pod of the workload namespace/name that sorts first by name
+func (m *Model) firstWorkloadPod(target string) *corev1.Pod {
+	namespace, name, err := parseWorkloadTarget(target)
+	if err != nil {
+		return nil
+	}
+	var pods []*corev1.Pod
+	for _, p := range m.listPods() {
+		if p.Namespace != namespace || p.Spec.NodeName == "" {
+			continue
+		}
+		if workload := m.workloadOf(p); workload.Name == name {
+			pods = append(pods, p)
+		}
+	}
+	if len(pods) == 0 {
+		return nil
+	}
+	sort.Slice(pods, func(i, j int) bool { return pods[i].Name < pods[j].Name })
+	return pods[0]
+}
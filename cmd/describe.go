@@ -0,0 +1,282 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/bwagner5/kube-demo/pkg/format"
+	"github.com/bwagner5/kube-demo/pkg/pod"
+)
+
+// describeNode renders the node the way kubectl describe node does
+func (m *Model) describeNode(n *corev1.Node) string {
+	var out strings.Builder
+	w := tabwriter.NewWriter(&out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "Name:\t%s\n", n.Name)
+	fmt.Fprintf(w, "Roles:\t%s\n", strings.Join(nodeRoles(n), ","))
+	writeMap(w, "Labels", n.Labels)
+	writeMap(w, "Annotations", cachedAnnotations(n))
+	fmt.Fprintf(w, "CreationTimestamp:\t%s\n", n.CreationTimestamp.Time.Format(time.RFC1123Z))
+	fmt.Fprintf(w, "Taints:\t%s\n", describeTaints(n.Spec.Taints))
+	fmt.Fprintf(w, "Unschedulable:\t%t\n", n.Spec.Unschedulable)
+	w.Flush()
+
+	out.WriteString("Conditions:\n")
+	w = tabwriter.NewWriter(&out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "  Type\tStatus\tLastHeartbeatTime\tReason\tMessage")
+	for _, c := range n.Status.Conditions {
+		fmt.Fprintf(w, "  %s\t%s\t%s\t%s\t%s\n", c.Type, c.Status, c.LastHeartbeatTime.Time.Format(time.RFC1123Z), c.Reason, c.Message)
+	}
+	w.Flush()
+
+	out.WriteString("Addresses:\n")
+	for _, address := range n.Status.Addresses {
+		fmt.Fprintf(&out, "  %s:\t%s\n", address.Type, address.Address)
+	}
+	out.WriteString("Capacity:\n")
+	writeResources(&out, n.Status.Capacity)
+	out.WriteString("Allocatable:\n")
+	writeResources(&out, n.Status.Allocatable)
+
+	info := n.Status.NodeInfo
+	w = tabwriter.NewWriter(&out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "System Info:")
+	fmt.Fprintf(w, "  Kernel Version:\t%s\n", info.KernelVersion)
+	fmt.Fprintf(w, "  OS Image:\t%s\n", info.OSImage)
+	fmt.Fprintf(w, "  Operating System:\t%s\n", info.OperatingSystem)
+	fmt.Fprintf(w, "  Architecture:\t%s\n", info.Architecture)
+	fmt.Fprintf(w, "  Container Runtime Version:\t%s\n", info.ContainerRuntimeVersion)
+	fmt.Fprintf(w, "  Kubelet Version:\t%s\n", info.KubeletVersion)
+	fmt.Fprintf(w, "ProviderID:\t%s\n", n.Spec.ProviderID)
+	w.Flush()
+
+	var running []*corev1.Pod
+	for _, p := range m.getPods(n) {
+		if !pod.IsTerminal(p) {
+			running = append(running, p)
+		}
+	}
+	fmt.Fprintf(&out, "Non-terminated Pods:\t(%d in total)\n", len(running))
+	w = tabwriter.NewWriter(&out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "  Namespace\tName\tCPU Requests\tCPU Limits\tMemory Requests\tMemory Limits\tAge")
+	requests, limits := corev1.ResourceList{}, corev1.ResourceList{}
+	for _, p := range running {
+		podRequests, podLimits := pod.Requests(p), pod.Limits(p)
+		pod.AddResources(requests, podRequests)
+		pod.AddResources(limits, podLimits)
+		fmt.Fprintf(w, "  %s\t%s\t%s\t%s\t%s\t%s\t%s\n", p.Namespace, p.Name,
+			withPercent(corev1.ResourceCPU, podRequests.Cpu(), n.Status.Allocatable.Cpu()), withPercent(corev1.ResourceCPU, podLimits.Cpu(), n.Status.Allocatable.Cpu()),
+			withPercent(corev1.ResourceMemory, podRequests.Memory(), n.Status.Allocatable.Memory()), withPercent(corev1.ResourceMemory, podLimits.Memory(), n.Status.Allocatable.Memory()),
+			format.Duration(time.Since(p.CreationTimestamp.Time)))
+	}
+	w.Flush()
+
+	out.WriteString("Allocated resources:\n")
+	w = tabwriter.NewWriter(&out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "  Resource\tRequests\tLimits")
+	for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory, corev1.ResourceEphemeralStorage} {
+		allocatable := n.Status.Allocatable[name]
+		request, limit := requests[name], limits[name]
+		fmt.Fprintf(w, "  %s\t%s\t%s\n", name, withPercent(name, &request, &allocatable), withPercent(name, &limit, &allocatable))
+	}
+	w.Flush()
+
+	out.WriteString("Events:\n")
+	out.WriteString(m.describeEvents(n.UID))
+	return out.String()
+}
+
+// describePod renders the pod the way kubectl describe pod does
+func (m *Model) describePod(p *corev1.Pod) string {
+	var out strings.Builder
+	w := tabwriter.NewWriter(&out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "Name:\t%s\n", p.Name)
+	fmt.Fprintf(w, "Namespace:\t%s\n", p.Namespace)
+	if p.Spec.Priority != nil {
+		fmt.Fprintf(w, "Priority:\t%d\n", *p.Spec.Priority)
+	}
+	if p.Spec.PriorityClassName != "" {
+		fmt.Fprintf(w, "Priority Class Name:\t%s\n", p.Spec.PriorityClassName)
+	}
+	fmt.Fprintf(w, "Service Account:\t%s\n", p.Spec.ServiceAccountName)
+	fmt.Fprintf(w, "Node:\t%s\n", valueOrNone(p.Spec.NodeName))
+	if p.Status.StartTime != nil {
+		fmt.Fprintf(w, "Start Time:\t%s\n", p.Status.StartTime.Time.Format(time.RFC1123Z))
+	}
+	writeMap(w, "Labels", p.Labels)
+	writeMap(w, "Annotations", cachedAnnotations(p))
+	status := string(p.Status.Phase)
+	if p.DeletionTimestamp != nil {
+		status = fmt.Sprintf("Terminating (lasts %s)", format.Duration(time.Since(p.DeletionTimestamp.Time)))
+	}
+	fmt.Fprintf(w, "Status:\t%s\n", status)
+	if p.Status.Reason != "" {
+		fmt.Fprintf(w, "Reason:\t%s\n", p.Status.Reason)
+	}
+	fmt.Fprintf(w, "IP:\t%s\n", valueOrNone(p.Status.PodIP))
+	if owner := metav1.GetControllerOf(p); owner != nil {
+		fmt.Fprintf(w, "Controlled By:\t%s/%s\n", owner.Kind, owner.Name)
+	}
+	w.Flush()
+
+	if len(p.Spec.InitContainers) > 0 {
+		out.WriteString("Init Containers:\n")
+		writeContainerTable(&out, p.Spec.InitContainers, p.Status.InitContainerStatuses)
+	}
+	out.WriteString("Containers:\n")
+	writeContainerTable(&out, p.Spec.Containers, p.Status.ContainerStatuses)
+
+	out.WriteString("Conditions:\n")
+	w = tabwriter.NewWriter(&out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "  Type\tStatus")
+	for _, c := range p.Status.Conditions {
+		fmt.Fprintf(w, "  %s\t%s\n", c.Type, c.Status)
+	}
+	w.Flush()
+
+	w = tabwriter.NewWriter(&out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "QoS Class:\t%s\n", p.Status.QOSClass)
+	fmt.Fprintf(w, "Privileges:\t%s\n", valueOrNone(strings.Join(pod.Privileges(p), ", ")))
+	fmt.Fprintf(w, "Node-Selectors:\t%s\n", joinMap(p.Spec.NodeSelector))
+	fmt.Fprintf(w, "Tolerations:\t%s\n", describeTolerations(p.Spec.Tolerations))
+	w.Flush()
+
+	out.WriteString("Events:\n")
+	out.WriteString(m.describeEvents(p.UID))
+	return out.String()
+}
+
+// describeEvents renders the fetched events of an object as kubectl describe's events table
+func (m *Model) describeEvents(uid types.UID) string {
+	events, ok := m.objectEvents[uid]
+	if m.kubeClient == nil || !ok || len(events) == 0 {
+		return m.eventsSummary(uid)
+	}
+	var out strings.Builder
+	w := tabwriter.NewWriter(&out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "  Type\tReason\tAge\tFrom\tMessage")
+	for _, event := range events {
+		last := event.LastTimestamp.Time
+		if last.IsZero() {
+			last = event.EventTime.Time
+		}
+		fmt.Fprintf(w, "  %s\t%s\t%s\t%s\t%s\n", event.Type, event.Reason, format.Duration(time.Since(last)), event.Source.Component, event.Message)
+	}
+	w.Flush()
+	return out.String()
+}
+
+// nodeRoles returns the roles from the node's node-role.kubernetes.io labels
+func nodeRoles(n *corev1.Node) []string {
+	var roles []string
+	for label := range n.Labels {
+		if strings.HasPrefix(label, "node-role.kubernetes.io/") {
+			roles = append(roles, strings.TrimPrefix(label, "node-role.kubernetes.io/"))
+		}
+	}
+	if len(roles) == 0 {
+		return []string{"<none>"}
+	}
+	sort.Strings(roles)
+	return roles
+}
+
+// writeMap writes a sorted key=value block, one pair per line after the first
+func writeMap(w *tabwriter.Writer, title string, values map[string]string) {
+	if len(values) == 0 {
+		fmt.Fprintf(w, "%s:\t<none>\n", title)
+		return
+	}
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for i, key := range keys {
+		if i == 0 {
+			fmt.Fprintf(w, "%s:\t%s=%s\n", title, key, values[key])
+			continue
+		}
+		fmt.Fprintf(w, "\t%s=%s\n", key, values[key])
+	}
+}
+
+// joinMap renders a map as sorted comma separated key=value pairs
+func joinMap(values map[string]string) string {
+	if len(values) == 0 {
+		return "<none>"
+	}
+	var pairs []string
+	for key, value := range values {
+		pairs = append(pairs, key+"="+value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func writeResources(out *strings.Builder, list corev1.ResourceList) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	for _, name := range sortedResourceNames(list) {
+		quantity := list[name]
+		fmt.Fprintf(w, "  %s:\t%s\n", name, format.Quantity(name, quantity))
+	}
+	w.Flush()
+}
+
+func describeTaints(taints []corev1.Taint) string {
+	if len(taints) == 0 {
+		return "<none>"
+	}
+	var out []string
+	for _, taint := range taints {
+		out = append(out, taint.ToString())
+	}
+	return strings.Join(out, ", ")
+}
+
+func describeTolerations(tolerations []corev1.Toleration) string {
+	if len(tolerations) == 0 {
+		return "<none>"
+	}
+	var out []string
+	for _, toleration := range tolerations {
+		s := toleration.Key
+		if toleration.Operator == corev1.TolerationOpEqual {
+			s += "=" + toleration.Value
+		}
+		if toleration.Effect != "" {
+			s += ":" + string(toleration.Effect)
+		}
+		if toleration.Operator == corev1.TolerationOpExists && toleration.Key == "" {
+			s = "op=Exists" + s
+		}
+		if toleration.TolerationSeconds != nil {
+			s += fmt.Sprintf(" for %ds", *toleration.TolerationSeconds)
+		}
+		out = append(out, s)
+	}
+	return strings.Join(out, ", ")
+}
+
+// withPercent renders a quantity with its share of the node's allocatable capacity
+func withPercent(name corev1.ResourceName, quantity *resource.Quantity, allocatable *resource.Quantity) string {
+	if allocatable.IsZero() {
+		return format.Quantity(name, *quantity)
+	}
+	return fmt.Sprintf("%s (%d%%)", format.Quantity(name, *quantity), quantity.MilliValue()*100/allocatable.MilliValue())
+}
+
+func valueOrNone(value string) string {
+	if value == "" {
+		return "<none>"
+	}
+	return value
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/bwagner5/kube-demo/pkg/node"
+	"github.com/bwagner5/kube-demo/pkg/pod"
+)
+
+// diagnosticsTimeout bounds the diagnostics lookups
+const diagnosticsTimeout = 15 * time.Second
+
+// diagnostics are the results of the DNS and kube-proxy quick checks
+type diagnostics struct {
+	// findings are cluster wide results shown in the status line
+	findings []string
+	// gaps are per node problems shown as badges on the node
+	gaps map[string][]string
+}
+
+// diagnosticsMsg delivers the results of runDiagnostics
+type diagnosticsMsg struct {
+	result *diagnostics
+	err    error
+}
+
+// toggleDiagnostics runs the quick checks, or clears their results if shown
+func (m *Model) toggleDiagnostics() tea.Cmd {
+	if m.diagnostics != nil {
+		m.diagnostics = nil
+		return nil
+	}
+	if m.kubeClient == nil {
+		return m.setStatus("diagnostics are unavailable without a cluster connection")
+	}
+	client := m.kubeClient
+	nodes := m.getNodes()
+	return tea.Batch(m.setStatus("running DNS and kube-proxy checks..."), func() tea.Msg {
+		ctx, cancel := m.callContext(diagnosticsTimeout)
+		defer cancel()
+		result, err := runDiagnostics(ctx, client, nodes)
+		return diagnosticsMsg{result: result, err: err}
+	})
+}
+
+// runDiagnostics checks that CoreDNS has ready pods and that every ready node
+// runs a ready kube-proxy pod. The pods are listed from kube-system directly
+// since the grid may be filtered by namespace or label selector.
+func runDiagnostics(ctx context.Context, client kubernetes.Interface, nodes []*corev1.Node) (*diagnostics, error) {
+	result := &diagnostics{gaps: map[string][]string{}}
+	dns, err := listSystemPods(ctx, client, "k8s-app=kube-dns")
+	if err != nil {
+		return nil, err
+	}
+	ready := 0
+	for _, p := range dns {
+		if pod.IsReady(p) {
+			ready++
+		} else if p.Spec.NodeName != "" {
+			result.gaps[p.Spec.NodeName] = append(result.gaps[p.Spec.NodeName], "DNS pod "+p.Name+" not ready")
+		}
+	}
+	switch {
+	case len(dns) == 0:
+		result.findings = append(result.findings, "no CoreDNS pods (k8s-app=kube-dns) found")
+	case ready == 0:
+		result.findings = append(result.findings, fmt.Sprintf("DNS down: 0/%d CoreDNS pods ready", len(dns)))
+	default:
+		result.findings = append(result.findings, fmt.Sprintf("DNS %d/%d ready", ready, len(dns)))
+	}
+
+	proxies, err := listSystemPods(ctx, client, "k8s-app=kube-proxy")
+	if err != nil {
+		return nil, err
+	}
+	if len(proxies) == 0 {
+		result.findings = append(result.findings, "no kube-proxy pods, assuming a kube-proxy replacement")
+		return result, nil
+	}
+	covered := map[string]bool{}
+	for _, p := range proxies {
+		if pod.IsReady(p) {
+			covered[p.Spec.NodeName] = true
+		}
+	}
+	missing := 0
+	for _, n := range nodes {
+		if node.IsReady(n) && !covered[n.Name] {
+			missing++
+			result.gaps[n.Name] = append(result.gaps[n.Name], "no ready kube-proxy")
+		}
+	}
+	result.findings = append(result.findings, fmt.Sprintf("kube-proxy on %d/%d nodes", len(nodes)-missing, len(nodes)))
+	return result, nil
+}
+
+func listSystemPods(ctx context.Context, client kubernetes.Interface, selector string) ([]*corev1.Pod, error) {
+	var list *corev1.PodList
+	err := retry(ctx, func(ctx context.Context) (err error) {
+		list, err = client.CoreV1().Pods(metav1.NamespaceSystem).List(ctx, metav1.ListOptions{LabelSelector: selector})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	pods := make([]*corev1.Pod, 0, len(list.Items))
+	for i := range list.Items {
+		pods = append(pods, &list.Items[i])
+	}
+	return pods, nil
+}
+
+func (d *diagnostics) summary() string {
+	line := "checks: " + strings.Join(d.findings, ", ")
+	if len(d.gaps) > 0 {
+		line += fmt.Sprintf(" (%d nodes with gaps)", len(d.gaps))
+	}
+	return line
+}
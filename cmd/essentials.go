@@ -0,0 +1,63 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+)
+
+// cniDaemonSets are the DaemonSet names of common CNI plugins
+var cniDaemonSets = map[string]bool{
+	"aws-node":        true,
+	"calico-node":     true,
+	"canal":           true,
+	"cilium":          true,
+	"kube-flannel-ds": true,
+	"weave-net":       true,
+	"antrea-agent":    true,
+	"kube-router":     true,
+}
+
+// isEssentialDeployment returns true for the core add-on Deployments the banner watches
+func isEssentialDeployment(d *appsv1.Deployment) bool {
+	return d.Name == "coredns" || d.Labels["k8s-app"] == "kube-dns" || d.Name == "metrics-server" || strings.Contains(d.Name, "csi")
+}
+
+// isEssentialDaemonSet returns true for the CNI and CSI node DaemonSets the banner watches
+func isEssentialDaemonSet(ds *appsv1.DaemonSet) bool {
+	return cniDaemonSets[ds.Name] || strings.Contains(ds.Name, "csi")
+}
+
+// degradedEssentials lists the core add-ons with fewer ready pods than desired, as "name ready/desired"
+func (m *Model) degradedEssentials() []string {
+	var degraded []string
+	for _, obj := range m.store.Deployments.List() {
+		d := obj.(*appsv1.Deployment)
+		desired := int32(1)
+		if d.Spec.Replicas != nil {
+			desired = *d.Spec.Replicas
+		}
+		if isEssentialDeployment(d) && d.Status.ReadyReplicas < desired {
+			degraded = append(degraded, fmt.Sprintf("%s %d/%d", d.Name, d.Status.ReadyReplicas, desired))
+		}
+	}
+	for _, obj := range m.store.DaemonSets.List() {
+		ds := obj.(*appsv1.DaemonSet)
+		if isEssentialDaemonSet(ds) && ds.Status.NumberReady < ds.Status.DesiredNumberScheduled {
+			degraded = append(degraded, fmt.Sprintf("%s %d/%d", ds.Name, ds.Status.NumberReady, ds.Status.DesiredNumberScheduled))
+		}
+	}
+	sort.Strings(degraded)
+	return degraded
+}
+
+// essentialsBanner renders a one line warning when core add-ons are degraded, or "" when healthy
+func (m *Model) essentialsBanner() string {
+	degraded := m.degradedEssentials()
+	if len(degraded) == 0 {
+		return ""
+	}
+	return pullErrorStyle.Render("⚠ degraded add-ons: " + strings.Join(degraded, ", "))
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/bwagner5/kube-demo/pkg/pod"
+)
+
+// sessionExport is everything measured during the session, written by --export or the export key
+type sessionExport struct {
+	ExportedAt        time.Time                `json:"exportedAt"`
+	SchedulingLatency []podLatencyRecord       `json:"schedulingLatency"`
+	NodeProvisioning  []nodeProvisioningRecord `json:"nodeProvisioning"`
+	Churn             []churnRecord            `json:"churn"`
+	Events            []eventRecord            `json:"events"`
+}
+
+type podLatencyRecord struct {
+	Namespace        string    `json:"namespace"`
+	Name             string    `json:"name"`
+	Node             string    `json:"node"`
+	Created          time.Time `json:"created"`
+	ScheduledSeconds *float64  `json:"scheduledSeconds,omitempty"`
+	ReadySeconds     *float64  `json:"readySeconds,omitempty"`
+}
+
+type nodeProvisioningRecord struct {
+	Node           string    `json:"node"`
+	ReadyAt        time.Time `json:"readyAt"`
+	LatencySeconds float64   `json:"latencySeconds"`
+}
+
+type churnRecord struct {
+	At        time.Time `json:"at"`
+	Namespace string    `json:"namespace,omitempty"`
+	Node      string    `json:"node,omitempty"`
+	Change    string    `json:"change"`
+}
+
+type eventRecord struct {
+	At        time.Time `json:"at"`
+	Namespace string    `json:"namespace"`
+	Object    string    `json:"object"`
+	Type      string    `json:"type"`
+	Reason    string    `json:"reason"`
+	Message   string    `json:"message"`
+}
+
+// exportPath returns the --export path, or a timestamped JSON file in the working directory
+func (m *Model) exportPath() string {
+	if m.exportFile != "" {
+		return m.exportFile
+	}
+	return fmt.Sprintf("kube-demo-%s.json", time.Now().Format("20060102-150405"))
+}
+
+// exportMetrics writes the session metrics and reports where they went
+func (m *Model) exportMetrics() tea.Cmd {
+	path := m.exportPath()
+	if err := m.writeExport(path); err != nil {
+		return m.setStatus(fmt.Sprintf("export failed: %v", err))
+	}
+	return m.setStatus("exported session metrics to " + path)
+}
+
+// collectExport gathers the session metrics
+func (m *Model) collectExport() sessionExport {
+	export := sessionExport{ExportedAt: time.Now()}
+	for _, obj := range m.store.Pods.List() {
+		p := obj.(*corev1.Pod)
+		record := podLatencyRecord{Namespace: p.Namespace, Name: p.Name, Node: p.Spec.NodeName, Created: p.CreationTimestamp.Time}
+		if d, ok := pod.SchedulingLatency(p); ok {
+			seconds := d.Seconds()
+			record.ScheduledSeconds = &seconds
+		}
+		if d, ok := pod.ReadyLatency(p); ok {
+			seconds := d.Seconds()
+			record.ReadySeconds = &seconds
+		}
+		export.SchedulingLatency = append(export.SchedulingLatency, record)
+	}
+	sort.Slice(export.SchedulingLatency, func(i, j int) bool {
+		return export.SchedulingLatency[i].Created.Before(export.SchedulingLatency[j].Created)
+	})
+	for name, p := range m.provisioned {
+		export.NodeProvisioning = append(export.NodeProvisioning, nodeProvisioningRecord{Node: name, ReadyAt: p.readyAt, LatencySeconds: p.latency.Seconds()})
+	}
+	sort.Slice(export.NodeProvisioning, func(i, j int) bool {
+		return export.NodeProvisioning[i].ReadyAt.Before(export.NodeProvisioning[j].ReadyAt)
+	})
+	for _, e := range m.churn.snapshot() {
+		change := "deleted"
+		if e.created {
+			change = "created"
+		}
+		export.Churn = append(export.Churn, churnRecord{At: e.at, Namespace: e.namespace, Node: e.node, Change: change})
+	}
+	for _, obj := range m.store.Events.List() {
+		e := obj.(*corev1.Event)
+		at := e.LastTimestamp.Time
+		if at.IsZero() {
+			at = e.EventTime.Time
+		}
+		export.Events = append(export.Events, eventRecord{At: at, Namespace: e.Namespace,
+			Object: e.InvolvedObject.Kind + "/" + e.InvolvedObject.Name, Type: e.Type, Reason: e.Reason, Message: e.Message})
+	}
+	sort.Slice(export.Events, func(i, j int) bool { return export.Events[i].At.Before(export.Events[j].At) })
+	return export
+}
+
+// writeExport writes the session metrics as CSV if the path ends in .csv, JSON otherwise
+func (m *Model) writeExport(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	export := m.collectExport()
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		return writeExportCSV(f, export)
+	}
+	encoder := json.NewEncoder(f)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(export)
+}
+
+// writeExportCSV flattens every metric into one table with a metric column
+func writeExportCSV(out io.Writer, export sessionExport) error {
+	w := csv.NewWriter(out)
+	rows := [][]string{{"metric", "time", "namespace", "name", "node", "value", "detail"}}
+	seconds := func(v *float64) string {
+		if v == nil {
+			return ""
+		}
+		return fmt.Sprintf("%.3f", *v)
+	}
+	for _, r := range export.SchedulingLatency {
+		rows = append(rows, []string{"scheduling_latency_seconds", r.Created.Format(time.RFC3339), r.Namespace, r.Name, r.Node, seconds(r.ScheduledSeconds), ""})
+		rows = append(rows, []string{"ready_latency_seconds", r.Created.Format(time.RFC3339), r.Namespace, r.Name, r.Node, seconds(r.ReadySeconds), ""})
+	}
+	for _, r := range export.NodeProvisioning {
+		rows = append(rows, []string{"node_provisioning_seconds", r.ReadyAt.Format(time.RFC3339), "", r.Node, r.Node, fmt.Sprintf("%.3f", r.LatencySeconds), ""})
+	}
+	for _, r := range export.Churn {
+		rows = append(rows, []string{"pod_churn", r.At.Format(time.RFC3339), r.Namespace, "", r.Node, "1", r.Change})
+	}
+	for _, r := range export.Events {
+		rows = append(rows, []string{"event", r.At.Format(time.RFC3339), r.Namespace, r.Object, "", r.Reason, r.Message})
+	}
+	if err := w.WriteAll(rows); err != nil {
+		return err
+	}
+	return w.Error()
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/bwagner5/kube-demo/pkg/node"
+)
+
+// nodeFilter narrows the node grid to nodes in a particular state
+type nodeFilter int
+
+const (
+	filterNone nodeFilter = iota
+	filterCordoned
+	filterNotReady
+	filterTaint
+	filterCount
+)
+
+func (f nodeFilter) String() string {
+	switch f {
+	case filterCordoned:
+		return "cordoned"
+	case filterNotReady:
+		return "not ready"
+	case filterTaint:
+		return "tainted"
+	}
+	return "all"
+}
+
+// matches returns true if the node should be shown under the filter
+func (f nodeFilter) matches(n *corev1.Node, taintKey string) bool {
+	switch f {
+	case filterCordoned:
+		return node.IsCordoned(n)
+	case filterNotReady:
+		return !node.IsReady(n)
+	case filterTaint:
+		return node.HasTaint(n, taintKey)
+	}
+	return true
+}
+
+// cycleFilter advances to the next node filter, moving the cursor back to the first node
+// and out of its pods
+func (m *Model) cycleFilter() {
+	m.nodeFilter = (m.nodeFilter + 1) % filterCount
+	m.selectedNode = 0
+	m.selectedPod = 0
+	m.unfocus(focusNode)
+	m.clampSelection()
+}
+
+// filterLabel describes the active node and pod filters for the status line
+func (m *Model) filterLabel() string {
+	label := ""
+	switch {
+	case m.nodeFilter == filterNone:
+	case m.nodeFilter == filterTaint && m.taintKey != "":
+		label = fmt.Sprintf("filter: taint %s", m.taintKey)
+	default:
+		label = fmt.Sprintf("filter: %s", m.nodeFilter)
+	}
+	if m.privilegedOnly {
+		label = strings.TrimSpace(label + "  privileged pods only")
+	}
+	return label
+}
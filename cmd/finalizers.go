@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+// maxFinalizerChoices is how many finalizers the removal menu can number
+const maxFinalizerChoices = 9
+
+// finalizersSummary lists the object's finalizers for the details, or returns "" when it has none
+func finalizersSummary(obj metav1.Object) string {
+	if len(obj.GetFinalizers()) == 0 {
+		return ""
+	}
+	var out strings.Builder
+	out.WriteString("Finalizers:\n")
+	for i, f := range obj.GetFinalizers() {
+		fmt.Fprintf(&out, "  %d  %s\n", i+1, f)
+	}
+	out.WriteString("  press F to remove one\n")
+	return out.String()
+}
+
+// detailsObject returns the kind and object whose details are open, or false if none are
+func (m *Model) detailsObject() (string, metav1.Object, bool) {
+	nodes := m.getNodes()
+	if m.view != viewNodes || !m.showingDetails() || len(nodes) == 0 {
+		return "", nil, false
+	}
+	if p := m.selectedPodOrNil(); p != nil {
+		return "pod", p, true
+	}
+	return "node", nodes[m.selectedNode], true
+}
+
+// finalizerMenu offers the finalizers of the object in the details for removal
+func (m *Model) finalizerMenu() tea.Cmd {
+	kind, obj, ok := m.detailsObject()
+	if !ok {
+		return m.setStatus("open the details of a node or pod with enter first")
+	}
+	finalizers := obj.GetFinalizers()
+	if len(finalizers) == 0 {
+		return m.setStatus(fmt.Sprintf("%s %s has no finalizers", kind, objectName(obj)))
+	}
+	var items []menuItem
+	for i, f := range finalizers {
+		if i == maxFinalizerChoices {
+			break
+		}
+		i, f := i, f
+		items = append(items, menuItem{key: fmt.Sprint(i + 1), label: f, choose: func() tea.Cmd { return m.confirmFinalizerRemoval(kind, obj, i, f) }})
+	}
+	m.menu = &menu{title: fmt.Sprintf("remove a finalizer from %s %s", kind, objectName(obj)), items: items}
+	return nil
+}
+
+// confirmFinalizerRemoval warns that the finalizer's controller is bypassed before removing it
+func (m *Model) confirmFinalizerRemoval(kind string, obj metav1.Object, index int, finalizer string) tea.Cmd {
+	title := fmt.Sprintf("remove %s from %s %s? Whatever cleanup it guards will be skipped", finalizer, kind, objectName(obj))
+	m.menu = &menu{title: title, items: []menuItem{
+		{key: "y", label: "remove", choose: func() tea.Cmd { return m.runConfirmedAction(removeFinalizer(kind, obj, index, finalizer)) }},
+		{key: "n", label: "cancel", choose: func() tea.Cmd { return m.setStatus("cancelled finalizer removal") }},
+	}}
+	return nil
+}
+
+// removeFinalizer json patches the finalizer at index out of the object. The patch tests
+// the finalizer is still at that index so a concurrent change fails instead of removing another one.
+func removeFinalizer(kind string, obj metav1.Object, index int, finalizer string) action {
+	namespace, name := obj.GetNamespace(), obj.GetName()
+	path := fmt.Sprintf("/metadata/finalizers/%d", index)
+	patch, _ := json.Marshal([]map[string]interface{}{
+		{"op": "test", "path": path, "value": finalizer},
+		{"op": "remove", "path": path},
+	})
+	kubectl := fmt.Sprintf("kubectl patch %s %s --type=json -p '%s'", kind, name, patch)
+	if namespace != "" {
+		kubectl += " -n " + namespace
+	}
+	return action{
+		description: fmt.Sprintf("remove finalizer %s from %s %s", finalizer, kind, objectName(obj)),
+		kubectl:     kubectl,
+		requires:    []string{fmt.Sprintf("patch %ss", kind)},
+		run: func(ctx context.Context, client kubernetes.Interface, dryRun []string) error {
+			options := metav1.PatchOptions{DryRun: dryRun}
+			return retry(ctx, func(ctx context.Context) (err error) {
+				if kind == "pod" {
+					_, err = client.CoreV1().Pods(namespace).Patch(ctx, name, types.JSONPatchType, patch, options)
+				} else {
+					_, err = client.CoreV1().Nodes().Patch(ctx, name, types.JSONPatchType, patch, options)
+				}
+				return err
+			})
+		},
+	}
+}
+
+// objectName returns namespace/name for namespaced objects and name otherwise
+func objectName(obj metav1.Object) string {
+	if obj.GetNamespace() == "" {
+		return obj.GetName()
+	}
+	return obj.GetNamespace() + "/" + obj.GetName()
+}
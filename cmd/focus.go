@@ -0,0 +1,145 @@
+package main
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// focusLevel is one step of the drill down from the cluster to a single container
+type focusLevel int
+
+const (
+	// focusCluster moves the cursor between nodes
+	focusCluster focusLevel = iota
+	// focusNode moves the cursor between the pods of the selected node
+	focusNode
+	// focusDetails shows the details of the node or pod under the cursor
+	focusDetails
+	// focusContainer moves the cursor between the containers of the pod in the details
+	focusContainer
+)
+
+var breadcrumbStyle = lipgloss.NewStyle().Foreground(grey)
+
+// focused returns the innermost focus level
+func (m *Model) focused() focusLevel {
+	return m.focusStack[len(m.focusStack)-1]
+}
+
+// inFocus returns true if level is anywhere on the focus stack
+func (m *Model) inFocus(level focusLevel) bool {
+	for _, l := range m.focusStack {
+		if l == level {
+			return true
+		}
+	}
+	return false
+}
+
+// pushFocus drills one level further in
+func (m *Model) pushFocus(level focusLevel) {
+	m.focusStack = append(m.focusStack, level)
+}
+
+// popFocus steps back out one level, never past the cluster
+func (m *Model) popFocus() {
+	if len(m.focusStack) > 1 {
+		m.focusStack = m.focusStack[:len(m.focusStack)-1]
+	}
+	if !m.inFocus(focusDetails) {
+		m.focus = paneGrid
+	}
+}
+
+// unfocus pops every level from level inwards, leaving the stack unchanged if level is not on it
+func (m *Model) unfocus(level focusLevel) {
+	for i, l := range m.focusStack {
+		if l == level && i > 0 {
+			m.focusStack = m.focusStack[:i]
+			break
+		}
+	}
+	if !m.inFocus(focusDetails) {
+		m.focus = paneGrid
+	}
+}
+
+// showingDetails returns true while the details of the selection are open
+func (m *Model) showingDetails() bool {
+	return m.inFocus(focusDetails)
+}
+
+// selectingPods returns true while the cursor moves between the pods of a node
+func (m *Model) selectingPods() bool {
+	return m.inFocus(focusNode)
+}
+
+// togglePodSelection drills into the pods of the selected node, or back out to the nodes
+func (m *Model) togglePodSelection() {
+	m.selectedPod = 0
+	if m.selectingPods() {
+		m.unfocus(focusNode)
+		return
+	}
+	if m.view == viewNodes && len(m.getNodes()) > 0 {
+		m.unfocus(focusDetails)
+		m.pushFocus(focusNode)
+	}
+}
+
+// enter opens the details of the selection, then drills into the containers of a pod,
+// closing node details and container selection like a toggle
+func (m *Model) enter() tea.Cmd {
+	switch m.focused() {
+	case focusDetails:
+		if m.view == viewNodes && m.selectingPods() {
+			m.selectedContainer = 0
+			m.pushFocus(focusContainer)
+			return m.loadDetails()
+		}
+		m.popFocus()
+	case focusContainer:
+		m.popFocus()
+		return m.loadDetails()
+	default:
+		if m.hasSelection() {
+			m.pushFocus(focusDetails)
+			m.resetTabs()
+			return m.loadDetails()
+		}
+	}
+	return nil
+}
+
+// focusedContainers returns the init and app containers of the pod in the details
+func focusedContainers(p *corev1.Pod) []corev1.Container {
+	return append(append([]corev1.Container(nil), p.Spec.InitContainers...), p.Spec.Containers...)
+}
+
+// selectedContainerName returns the container under the cursor while containers are focused
+func (m *Model) selectedContainerName(p *corev1.Pod) (string, bool) {
+	containers := focusedContainers(p)
+	if !m.inFocus(focusContainer) || m.selectedContainer >= len(containers) {
+		return "", false
+	}
+	return containers[m.selectedContainer].Name, true
+}
+
+// breadcrumbs renders the path from the cluster to the focused object
+func (m *Model) breadcrumbs() string {
+	n := m.selectedNodeOrNil()
+	if m.view != viewNodes || len(m.focusStack) == 1 || n == nil {
+		return ""
+	}
+	crumbs := []string{"cluster", n.Name}
+	if p := m.selectedPodOrNil(); p != nil {
+		crumbs = append(crumbs, p.Namespace+"/"+p.Name)
+		if container, ok := m.selectedContainerName(p); ok {
+			crumbs = append(crumbs, container)
+		}
+	}
+	return breadcrumbStyle.Render(strings.Join(crumbs, " › "))
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/bwagner5/kube-demo/pkg/binpack"
+	"github.com/bwagner5/kube-demo/pkg/format"
+	"github.com/bwagner5/kube-demo/pkg/node"
+)
+
+// fragmentationBarWidth is the width of the free CPU and memory bars
+const fragmentationBarWidth = 20
+
+// largestPod is the biggest requests a single new pod could have and still be scheduled on a node
+type largestPod struct {
+	node   string
+	cpu    resource.Quantity
+	memory resource.Quantity
+	// reason explains why no pod fits at all, empty when one does
+	reason string
+}
+
+// largestPodOn returns the largest requests a pod could have to fit in what the node has left.
+// A pod must fit both dimensions on one node, so free capacity split across nodes cannot be combined.
+func largestPodOn(n *corev1.Node, bin *binpack.Bin) largestPod {
+	largest := largestPod{node: n.Name}
+	switch {
+	case !node.IsReady(n):
+		largest.reason = "not ready"
+	case node.IsCordoned(n):
+		largest.reason = "cordoned"
+	case bin.Free.Pods().Value() <= 0 && !n.Status.Allocatable.Pods().IsZero():
+		largest.reason = "no pod slots left"
+	}
+	if largest.reason != "" {
+		return largest
+	}
+	largest.cpu, largest.memory = nonNegative(*bin.Free.Cpu()), nonNegative(*bin.Free.Memory())
+	return largest
+}
+
+func nonNegative(q resource.Quantity) resource.Quantity {
+	if q.Sign() < 0 {
+		return resource.Quantity{Format: q.Format}
+	}
+	return q
+}
+
+// fragmentation renders, for every node, the largest pod that could still be scheduled there,
+// compared with the free capacity of the whole cluster
+func (m *Model) fragmentation() string {
+	nodes := m.getNodes()
+	if len(nodes) == 0 {
+		return "No nodes"
+	}
+	var largest []largestPod
+	var totalCPU, totalMemory, largestCPU, largestMemory int64
+	for _, n := range nodes {
+		l := largestPodOn(n, binpack.NewBin(n, m.getPods(n)))
+		largest = append(largest, l)
+		totalCPU += l.cpu.MilliValue()
+		totalMemory += l.memory.Value()
+		if l.cpu.MilliValue() > largestCPU {
+			largestCPU = l.cpu.MilliValue()
+		}
+		if l.memory.Value() > largestMemory {
+			largestMemory = l.memory.Value()
+		}
+	}
+	var out strings.Builder
+	w := tabwriter.NewWriter(&out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NODE\tLARGEST POD\tFREE CPU\t\tFREE MEMORY\t\t")
+	for i, l := range largest {
+		fit := fmt.Sprintf("cpu %s, mem %s", format.CPU(l.cpu), format.Bytes(l.memory))
+		if l.reason != "" {
+			fit = "none, " + l.reason
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t\n", l.node, fit,
+			freeBar(l.cpu.MilliValue(), nodes[i].Status.Allocatable.Cpu().MilliValue()), format.CPU(l.cpu),
+			freeBar(l.memory.Value(), nodes[i].Status.Allocatable.Memory().Value()), format.Bytes(l.memory))
+	}
+	w.Flush()
+	fmt.Fprintf(&out, "\nThe cluster has cpu %s and mem %s free in total, but no single pod can request more than cpu %s or mem %s",
+		format.CPU(*resource.NewMilliQuantity(totalCPU, resource.DecimalSI)), format.Bytes(*resource.NewQuantity(totalMemory, resource.BinarySI)),
+		format.CPU(*resource.NewMilliQuantity(largestCPU, resource.DecimalSI)), format.Bytes(*resource.NewQuantity(largestMemory, resource.BinarySI)))
+	if totalCPU > 0 && totalMemory > 0 {
+		fmt.Fprintf(&out, "\n(%.0f%% of the free cpu and %.0f%% of the free memory is usable by one pod)",
+			float64(largestCPU)/float64(totalCPU)*100, float64(largestMemory)/float64(totalMemory)*100)
+	}
+	return out.String()
+}
+
+// freeBar draws the free part of a node's allocatable capacity
+func freeBar(free int64, allocatable int64) string {
+	filled := 0
+	if allocatable > 0 {
+		filled = int(free * fragmentationBarWidth / allocatable)
+	}
+	if filled > fragmentationBarWidth {
+		filled = fragmentationBarWidth
+	}
+	return strings.Repeat("█", filled) + strings.Repeat("░", fragmentationBarWidth-filled)
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	certificatesv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/cache"
+)
+
+// loadFileStore fills a store from kubectl get -o yaml or -o json dumps, each holding
+// a List, a single object or several YAML documents. "-" reads from stdin.
+func loadFileStore(paths []string) (ClusterStore, error) {
+	store := NewClusterStore()
+	err := decodeFiles(paths, func(obj runtime.Object) error { return addObject(store, obj) })
+	return store, err
+}
+
+// decodeFiles decodes every object in the files, "-" being stdin, and visits it
+func decodeFiles(paths []string, visit func(runtime.Object) error) error {
+	for _, path := range paths {
+		var data []byte
+		var err error
+		if path == "-" {
+			data, err = io.ReadAll(os.Stdin)
+		} else {
+			data, err = os.ReadFile(path)
+		}
+		if err != nil {
+			return err
+		}
+		if err := decodeObjects(data, visit); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// decodeObjects decodes every document of data and visits it
+func decodeObjects(data []byte, visit func(runtime.Object) error) error {
+	decoder := utilyaml.NewYAMLOrJSONDecoder(bytes.NewReader(data), 4096)
+	for {
+		var raw runtime.RawExtension
+		if err := decoder.Decode(&raw); errors.Is(err, io.EOF) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if len(bytes.TrimSpace(raw.Raw)) == 0 || string(raw.Raw) == "null" {
+			continue
+		}
+		if err := decodeObject(raw.Raw, visit); err != nil {
+			return err
+		}
+	}
+}
+
+// decodeObject decodes a single object, or the items of a List, and visits it.
+// Kinds the client-go scheme does not know are skipped.
+func decodeObject(raw []byte, visit func(runtime.Object) error) error {
+	obj, _, err := scheme.Codecs.UniversalDeserializer().Decode(raw, nil, nil)
+	if runtime.IsNotRegisteredError(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if list, ok := obj.(*corev1.List); ok {
+		for _, item := range list.Items {
+			if err := decodeObject(item.Raw, visit); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return visit(obj)
+}
+
+// addObject adds the object to the store of its kind. Kinds the UI does not show are skipped.
+func addObject(store ClusterStore, obj runtime.Object) error {
+	var target cache.Store
+	switch obj.(type) {
+	case *corev1.Node:
+		target = store.Nodes
+	case *corev1.Pod:
+		target = store.Pods
+	case *corev1.ResourceQuota:
+		target = store.ResourceQuotas
+	case *corev1.LimitRange:
+		target = store.LimitRanges
+	case *corev1.Event:
+		target = store.Events
+	case *corev1.Service:
+		target = store.Services
+	case *corev1.Namespace:
+		target = store.Namespaces
+	case *appsv1.ReplicaSet:
+		target = store.ReplicaSets
+	case *appsv1.Deployment:
+		target = store.Deployments
+	case *appsv1.DaemonSet:
+		target = store.DaemonSets
+	case *discoveryv1.EndpointSlice:
+		target = store.EndpointSlices
+	case *networkingv1.Ingress:
+		target = store.Ingresses
+	case *networkingv1.NetworkPolicy:
+		target = store.NetworkPolicies
+	case *certificatesv1.CertificateSigningRequest:
+		target = store.CertificateSigningRequests
+	default:
+		return nil
+	}
+	return target.Add(obj)
+}
+
+// newFileModel constructs a Model showing the objects of --from-file dumps, without any API server connection
+func newFileModel(opts Options) *Model {
+	store, err := loadFileStore(opts.FromFiles)
+	if err != nil {
+		log.Fatalf("could not load --from-file: %v", err)
+	}
+	model := newModel(store, opts)
+	model.identity = "file " + strings.Join(opts.FromFiles, ",")
+	return model
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"sigs.k8s.io/yaml"
+)
+
+// viewState is what another user needs to see the same thing: the cluster, the
+// settings of the view and the object in focus
+type viewState struct {
+	Context   string  `json:"context,omitempty"`
+	Namespace string  `json:"namespace,omitempty"`
+	Selector  string  `json:"selector,omitempty"`
+	Settings  profile `json:"settings"`
+	Node      string  `json:"node,omitempty"`
+	// Pod is namespace/name of the selected pod
+	Pod     string `json:"pod,omitempty"`
+	Details bool   `json:"details,omitempty"`
+}
+
+// currentState captures the cluster, settings and focus of the session
+func (m *Model) currentState() viewState {
+	state := viewState{
+		Context:   m.contextName,
+		Namespace: m.namespace,
+		Selector:  m.selector,
+		Settings:  m.currentProfile(""),
+	}
+	nodes := m.getNodes()
+	if m.view != viewNodes || len(nodes) == 0 {
+		return state
+	}
+	state.Node = nodes[m.selectedNode].Name
+	if p := m.selectedPodOrNil(); p != nil {
+		state.Pod = p.Namespace + "/" + p.Name
+	}
+	state.Details = m.showingDetails()
+	return state
+}
+
+// exportState writes the current state to a file in the working directory for --restore
+func (m *Model) exportState() tea.Cmd {
+	data, err := yaml.Marshal(m.currentState())
+	if err != nil {
+		return m.setStatus(fmt.Sprintf("could not export state: %v", err))
+	}
+	path := fmt.Sprintf("kube-demo-state-%s.yaml", time.Now().Format("20060102-150405"))
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return m.setStatus(fmt.Sprintf("could not export state: %v", err))
+	}
+	return m.setStatus(fmt.Sprintf("state written, share it and run with --restore %s", path))
+}
+
+// loadState reads a state file written by exportState
+func loadState(path string) (*viewState, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var state viewState
+	if err := yaml.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	state.Settings.Name = filepath.Base(path)
+	return &state, nil
+}
+
+// restoredSettings returns the view settings of the state being restored, or nil if there is none
+func restoredSettings(opts Options) *profile {
+	if opts.Restore == nil {
+		return nil
+	}
+	return &opts.Restore.Settings
+}
+
+// restoreOptions points the options at the state's cluster, unless they were set explicitly
+func restoreOptions(opts *Options, state *viewState) {
+	if *opts.ConfigFlags.Context == "" {
+		*opts.ConfigFlags.Context = state.Context
+	}
+	if *opts.ConfigFlags.Namespace == "" {
+		*opts.ConfigFlags.Namespace = state.Namespace
+	}
+	if opts.LabelSelector == "" {
+		opts.LabelSelector = state.Selector
+	}
+	opts.Restore = state
+}
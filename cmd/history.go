@@ -0,0 +1,120 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bwagner5/kube-demo/pkg/format"
+)
+
+// historyInterval is the minimum spacing between recorded snapshots
+const historyInterval = time.Second
+
+// timelineWidth is the number of cells used to draw the scrub timeline
+const timelineWidth = 40
+
+// history is a fixed-size ring buffer of snapshots, oldest first
+type history struct {
+	buf   []*snapshot
+	start int
+	size  int
+}
+
+func newHistory(window time.Duration) *history {
+	capacity := int(window / historyInterval)
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &history{buf: make([]*snapshot, capacity)}
+}
+
+// push appends a snapshot, overwriting the oldest one once the buffer is full
+func (h *history) push(s *snapshot) {
+	if h.size < len(h.buf) {
+		h.buf[(h.start+h.size)%len(h.buf)] = s
+		h.size++
+		return
+	}
+	h.buf[h.start] = s
+	h.start = (h.start + 1) % len(h.buf)
+}
+
+// at returns the i-th snapshot where 0 is the oldest
+func (h *history) at(i int) *snapshot {
+	return h.buf[(h.start+i)%len(h.buf)]
+}
+
+func (h *history) len() int {
+	return h.size
+}
+
+func (h *history) latest() *snapshot {
+	if h.size == 0 {
+		return nil
+	}
+	return h.at(h.size - 1)
+}
+
+// indexOf finds the snapshot taken at t, returning -1 if it has been evicted
+func (h *history) indexOf(t time.Time) int {
+	for i := 0; i < h.size; i++ {
+		if h.at(i).takenAt.Equal(t) {
+			return i
+		}
+	}
+	return -1
+}
+
+// recordHistory stores the current informer state if enough time has passed
+// since the last recording. Changes inside the interval are picked up by a later
+// call (state change or refresh tick) through historyDirty.
+func (m *Model) recordHistory() {
+	if !m.historyDirty {
+		return
+	}
+	if latest := m.history.latest(); latest != nil && time.Since(latest.takenAt) < historyInterval {
+		return
+	}
+	m.history.push(m.takeSnapshot())
+	m.historyDirty = false
+}
+
+// scrub moves the display delta snapshots through history. Scrubbing forward
+// past the newest snapshot returns to the live view.
+func (m *Model) scrub(delta int) {
+	m.recordHistory()
+	if m.history.len() == 0 {
+		return
+	}
+	pos := m.history.len()
+	if m.scrubbing {
+		if pos = m.history.indexOf(m.frozen.takenAt); pos == -1 {
+			pos = 0
+		}
+	}
+	pos += delta
+	if pos < 0 {
+		pos = 0
+	}
+	if pos >= m.history.len() {
+		m.scrubbing = false
+		m.frozen = nil
+		m.clampSelection()
+		return
+	}
+	m.scrubbing = true
+	m.frozen = m.history.at(pos)
+	m.clampSelection()
+}
+
+// timeline renders the scrub position within the recorded history
+func (m *Model) timeline() string {
+	pos := m.history.indexOf(m.frozen.takenAt)
+	marker := timelineWidth - 1
+	if m.history.len() > 1 && pos >= 0 {
+		marker = pos * (timelineWidth - 1) / (m.history.len() - 1)
+	}
+	bar := strings.Repeat("─", marker) + "●" + strings.Repeat("─", timelineWidth-1-marker)
+	return fmt.Sprintf("[%s] -%s", bar, format.Precise(time.Since(m.frozen.takenAt)))
+}
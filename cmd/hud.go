@@ -0,0 +1,138 @@
+package main
+
+import (
+	"fmt"
+	"runtime"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/bwagner5/kube-demo/pkg/format"
+)
+
+// hudFrames is the number of recent frames render times are summarized over
+const hudFrames = 60
+
+// hudSampleInterval is how often the event rate and memory usage are sampled, reading
+// the memory stats stops the world briefly
+const hudSampleInterval = time.Second
+
+var hudStyle = lipgloss.NewStyle().Border(lipgloss.NormalBorder()).BorderForeground(lipgloss.Color("8")).Padding(0, 1)
+
+// perfStats measures the UI for the performance HUD. The frames are only touched by the
+// event loop, events is counted from the informer goroutines.
+type perfStats struct {
+	events uint64
+	// frames are the render times of the latest frames, next is where the following one goes
+	frames []time.Duration
+	next   int
+
+	sampledAt    time.Time
+	sampleEvents uint64
+	eventRate    float64
+	memory       runtime.MemStats
+	caches       []string
+}
+
+func newPerfStats() *perfStats {
+	return &perfStats{frames: make([]time.Duration, 0, hudFrames)}
+}
+
+// countEvent records an informer event
+func (p *perfStats) countEvent() {
+	atomic.AddUint64(&p.events, 1)
+}
+
+// recordFrame records the render time of a frame started at start
+func (p *perfStats) recordFrame(start time.Time) {
+	elapsed := time.Since(start)
+	if len(p.frames) < hudFrames {
+		p.frames = append(p.frames, elapsed)
+		return
+	}
+	p.frames[p.next] = elapsed
+	p.next = (p.next + 1) % hudFrames
+}
+
+// sample refreshes the event rate and memory usage at most once per hudSampleInterval,
+// reporting whether it did
+func (p *perfStats) sample() bool {
+	now := time.Now()
+	elapsed := now.Sub(p.sampledAt)
+	if elapsed < hudSampleInterval {
+		return false
+	}
+	events := atomic.LoadUint64(&p.events)
+	if !p.sampledAt.IsZero() {
+		p.eventRate = float64(events-p.sampleEvents) / elapsed.Seconds()
+	}
+	p.sampledAt, p.sampleEvents = now, events
+	runtime.ReadMemStats(&p.memory)
+	return true
+}
+
+// frameTimes returns the latest, median and slowest render time of the recent frames
+func (p *perfStats) frameTimes() (latest, median, slowest time.Duration) {
+	if len(p.frames) == 0 {
+		return 0, 0, 0
+	}
+	latest = p.frames[(p.next+len(p.frames)-1)%len(p.frames)]
+	sorted := append([]time.Duration(nil), p.frames...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return latest, sorted[len(sorted)/2], sorted[len(sorted)-1]
+}
+
+// toggleHUD shows or hides the performance HUD
+func (m *Model) toggleHUD() {
+	m.showHUD = !m.showHUD
+}
+
+// hud renders the performance HUD: render times, informer event rate, cache sizes,
+// goroutines and memory, to quote in performance reports
+func (m *Model) hud() string {
+	if !m.showHUD {
+		return ""
+	}
+	p := m.perf
+	if p.sample() {
+		p.caches = m.cacheSizes()
+	}
+	latest, median, slowest := p.frameTimes()
+	lines := []string{
+		fmt.Sprintf("render %s  (median %s, slowest %s over %d frames)  events %s/s",
+			latest.Round(10*time.Microsecond), median.Round(10*time.Microsecond), slowest.Round(10*time.Microsecond), len(p.frames), format.Number(p.eventRate)),
+		"cache " + strings.Join(p.caches, ", "),
+		fmt.Sprintf("goroutines %d  heap %s (%d objects)  from OS %s  GC cycles %d",
+			runtime.NumGoroutine(), format.Bytes(*resource.NewQuantity(int64(p.memory.HeapAlloc), resource.BinarySI)), p.memory.HeapObjects,
+			format.Bytes(*resource.NewQuantity(int64(p.memory.Sys), resource.BinarySI)), p.memory.NumGC),
+	}
+	return hudStyle.Render(strings.Join(lines, "\n"))
+}
+
+// cacheSizes counts the objects in the largest informer caches
+func (m *Model) cacheSizes() []string {
+	caches := []struct {
+		name  string
+		store cache.Store
+	}{
+		{"nodes", m.store.Nodes},
+		{"pods", m.store.Pods},
+		{"events", m.store.Events},
+		{"replicasets", m.store.ReplicaSets},
+		{"deployments", m.store.Deployments},
+		{"endpointslices", m.store.EndpointSlices},
+		{"services", m.store.Services},
+	}
+	var sizes []string
+	for _, c := range caches {
+		if c.store != nil {
+			sizes = append(sizes, fmt.Sprintf("%d %s", len(c.store.ListKeys()), c.name))
+		}
+	}
+	return sizes
+}
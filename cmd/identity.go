@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/rest"
+)
+
+// contextName returns the kubeconfig context in use. The config flags load
+// kubeconfigs with the same rules as kubectl: --kubeconfig if set, otherwise
+// every file in the KUBECONFIG path list merged with the first file taking
+// precedence, falling back to ~/.kube/config.
+func contextName(flags *genericclioptions.ConfigFlags) string {
+	if flags.Context != nil && *flags.Context != "" {
+		return *flags.Context
+	}
+	raw, err := flags.ToRawKubeConfigLoader().RawConfig()
+	if err != nil {
+		return ""
+	}
+	return raw.CurrentContext
+}
+
+// identityLabel combines the kubeconfig context with any impersonated identity
+func identityLabel(flags *genericclioptions.ConfigFlags, config *rest.Config) string {
+	return strings.TrimSpace(contextName(flags) + " " + impersonationLabel(config))
+}
+
+// impersonationLabel describes the --as/--as-group identity requests are made
+// with, or returns an empty string when not impersonating
+func impersonationLabel(config *rest.Config) string {
+	user := config.Impersonate.UserName
+	groups := config.Impersonate.Groups
+	if user == "" && len(groups) == 0 {
+		return ""
+	}
+	if user == "" {
+		user = "(self)"
+	}
+	if len(groups) == 0 {
+		return fmt.Sprintf("as %s", user)
+	}
+	return fmt.Sprintf("as %s [%s]", user, strings.Join(groups, ","))
+}
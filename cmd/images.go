@@ -0,0 +1,138 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/charmbracelet/lipgloss"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var pullErrorStyle = lipgloss.NewStyle().Foreground(pink).Bold(true)
+var selectedRowStyle = lipgloss.NewStyle().Reverse(true)
+
+// pullErrorReasons are the waiting reasons that indicate an image could not be pulled
+var pullErrorReasons = map[string]bool{
+	"ErrImagePull":     true,
+	"ImagePullBackOff": true,
+	"InvalidImageName": true,
+}
+
+// imageSummary aggregates the pods running a container image
+type imageSummary struct {
+	Image      string
+	Registry   string
+	Pods       []*corev1.Pod
+	Nodes      []string
+	PullErrors []string
+}
+
+// registry returns the registry host an image reference is pulled from
+func registry(image string) string {
+	parts := strings.SplitN(image, "/", 2)
+	if len(parts) == 2 && (strings.ContainsAny(parts[0], ".:") || parts[0] == "localhost") {
+		return parts[0]
+	}
+	return "docker.io"
+}
+
+// nodeHasImage returns true if the node reports the image in its status
+func nodeHasImage(node *corev1.Node, image string) bool {
+	candidates := []string{image, "docker.io/" + image, "docker.io/library/" + image}
+	for _, nodeImage := range node.Status.Images {
+		for _, name := range nodeImage.Names {
+			for _, candidate := range candidates {
+				if name == candidate {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// getImages summarizes rendered pods by container image, images with pull errors first
+func (m *Model) getImages() []*imageSummary {
+	byImage := map[string]*imageSummary{}
+	for _, pod := range m.listPods() {
+		statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+		for _, container := range append(append([]corev1.Container{}, pod.Spec.InitContainers...), pod.Spec.Containers...) {
+			summary, ok := byImage[container.Image]
+			if !ok {
+				summary = &imageSummary{Image: container.Image, Registry: registry(container.Image)}
+				byImage[container.Image] = summary
+			}
+			summary.Pods = append(summary.Pods, pod)
+			if waiting := containerStatus(container.Name, statuses).State.Waiting; waiting != nil && pullErrorReasons[waiting.Reason] {
+				summary.PullErrors = append(summary.PullErrors, fmt.Sprintf("%s/%s: %s: %s", pod.Namespace, pod.Name, waiting.Reason, waiting.Message))
+			}
+		}
+	}
+	nodes := m.getNodes()
+	images := make([]*imageSummary, 0, len(byImage))
+	for _, summary := range byImage {
+		for _, node := range nodes {
+			if nodeHasImage(node, summary.Image) {
+				summary.Nodes = append(summary.Nodes, node.Name)
+			}
+		}
+		images = append(images, summary)
+	}
+	sort.Slice(images, func(i, j int) bool {
+		if (len(images[i].PullErrors) > 0) != (len(images[j].PullErrors) > 0) {
+			return len(images[i].PullErrors) > 0
+		}
+		return images[i].Image < images[j].Image
+	})
+	return images
+}
+
+// images renders the image overview table
+func (m *Model) images() string {
+	var table strings.Builder
+	w := tabwriter.NewWriter(&table, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "IMAGE\tREGISTRY\tPODS\tNODES PULLED\tPULL ERRORS\t")
+	images := m.getImages()
+	for _, image := range images {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%d\t\n", image.Image, image.Registry, len(image.Pods), len(image.Nodes), len(image.PullErrors))
+	}
+	w.Flush()
+	lines := strings.Split(strings.TrimRight(table.String(), "\n"), "\n")
+	for i, image := range images {
+		line := lines[i+1]
+		if len(image.PullErrors) > 0 {
+			line = pullErrorStyle.Render(line)
+		}
+		if i == m.selectedImage {
+			line = selectedRowStyle.Render(line)
+		}
+		lines[i+1] = line
+	}
+	return strings.Join(lines, "\n")
+}
+
+// imageDetails lists the pods, nodes and pull errors of an image
+func imageDetails(image *imageSummary) string {
+	var out strings.Builder
+	fmt.Fprintf(&out, "Image: %s\nRegistry: %s\n", image.Image, image.Registry)
+	if len(image.PullErrors) > 0 {
+		out.WriteString("\nPull Errors:\n")
+		for _, pullError := range image.PullErrors {
+			fmt.Fprintf(&out, "  %s\n", pullError)
+		}
+	}
+	out.WriteString("\nPods:\n")
+	for _, pod := range image.Pods {
+		fmt.Fprintf(&out, "  %s/%s (%s) on %s\n", pod.Namespace, pod.Name, pod.Status.Phase, pod.Spec.NodeName)
+	}
+	out.WriteString("\nNodes with image:\n")
+	if len(image.Nodes) == 0 {
+		out.WriteString("  <none>\n")
+	}
+	for _, node := range image.Nodes {
+		fmt.Fprintf(&out, "  %s\n", node)
+	}
+	return out.String()
+}
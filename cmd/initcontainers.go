@@ -0,0 +1,88 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/bwagner5/kube-demo/pkg/format"
+	"github.com/bwagner5/kube-demo/pkg/pod"
+)
+
+// initializing returns true while the pod is still working through its init containers
+func initializing(p *corev1.Pod) bool {
+	if len(p.Spec.InitContainers) == 0 || pod.IsTerminal(p) || p.Spec.NodeName == "" {
+		return false
+	}
+	for _, c := range p.Status.Conditions {
+		if c.Type == corev1.PodInitialized {
+			return c.Status != corev1.ConditionTrue
+		}
+	}
+	return true
+}
+
+// initProgress renders each init container's state with how long it has been in it,
+// so a slow or blocked init container stands out in pod details
+func initProgress(p *corev1.Pod) string {
+	if len(p.Spec.InitContainers) == 0 {
+		return ""
+	}
+	var out strings.Builder
+	completed := 0
+	// each init container starts once the previous one has finished, or with the pod
+	since := time.Time{}
+	if p.Status.StartTime != nil {
+		since = p.Status.StartTime.Time
+	}
+	var lines []string
+	for _, container := range p.Spec.InitContainers {
+		status := containerStatus(container.Name, p.Status.InitContainerStatuses)
+		state := status.State
+		switch {
+		case state.Terminated != nil && state.Terminated.ExitCode == 0:
+			completed++
+			took := state.Terminated.FinishedAt.Sub(state.Terminated.StartedAt.Time)
+			lines = append(lines, fmt.Sprintf("  ✓ %s completed in %s", container.Name, format.Precise(took)))
+			since = state.Terminated.FinishedAt.Time
+		case state.Running != nil:
+			lines = append(lines, fmt.Sprintf("  ▶ %s running for %s%s", container.Name, format.Precise(time.Since(state.Running.StartedAt.Time)), restarts(status)))
+			since = time.Time{}
+		case state.Terminated != nil:
+			lines = append(lines, fmt.Sprintf("  ✗ %s failed with %s (exit %d)%s", container.Name, state.Terminated.Reason, state.Terminated.ExitCode, restarts(status)))
+			since = time.Time{}
+		case state.Waiting != nil && !since.IsZero():
+			lines = append(lines, fmt.Sprintf("  ⧗ %s blocked in %s for %s%s", container.Name, state.Waiting.Reason, format.Precise(time.Since(since)), restarts(status)))
+			since = time.Time{}
+		default:
+			lines = append(lines, fmt.Sprintf("  · %s not started", container.Name))
+			since = time.Time{}
+		}
+	}
+	fmt.Fprintf(&out, "Init containers (%d/%d complete):\n", completed, len(p.Spec.InitContainers))
+	out.WriteString(strings.Join(lines, "\n") + "\n")
+	return out.String()
+}
+
+func restarts(status corev1.ContainerStatus) string {
+	if status.RestartCount == 0 {
+		return ""
+	}
+	return fmt.Sprintf(", %d restarts", status.RestartCount)
+}
+
+// initializingBadges counts the node's pods still running init containers
+func (m *Model) initializingBadges(n *corev1.Node) []string {
+	count := 0
+	for _, p := range m.getPods(n) {
+		if initializing(p) {
+			count++
+		}
+	}
+	if count == 0 {
+		return nil
+	}
+	return []string{fmt.Sprintf("⧗ %d initializing", count)}
+}
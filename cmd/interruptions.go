@@ -0,0 +1,116 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/bwagner5/kube-demo/pkg/format"
+)
+
+// spotNoticePeriod is the warning a spot interruption gives before the instance is reclaimed
+const spotNoticePeriod = 2 * time.Minute
+
+// interruptionTaints are taints Karpenter and aws-node-termination-handler set on
+// nodes about to go away, with how long the node has left if known
+var interruptionTaints = map[string]time.Duration{
+	"karpenter.sh/disruption":                                0,
+	"karpenter.sh/disrupted":                                 0,
+	"aws-node-termination-handler/spot-itn":                  spotNoticePeriod,
+	"aws-node-termination-handler/rebalance-recommendation":  0,
+	"aws-node-termination-handler/scheduled-maintenance":     0,
+	"aws-node-termination-handler/asg-lifecycle-termination": 0,
+	"node.cloudprovider.kubernetes.io/shutdown":              0,
+}
+
+// interruptionReasons are node event reasons recorded by Karpenter and
+// aws-node-termination-handler for interruptions
+var interruptionReasons = map[string]time.Duration{
+	"SpotInterrupted":           spotNoticePeriod,
+	"SpotInterruption":          spotNoticePeriod,
+	"RebalanceRecommendation":   0,
+	"ScheduledEvent":            0,
+	"ASGLifecycle":              0,
+	"TerminatingOnInterruption": 0,
+	"Disrupting":                0,
+}
+
+// interruption is a pending termination of a node
+type interruption struct {
+	reason string
+	since  time.Time
+	// deadline is when the node is reclaimed, zero if unknown
+	deadline time.Time
+}
+
+// interruptions finds the nodes with an interruption taint or event, keeping the earliest notice per node
+func (m *Model) interruptions() map[string]interruption {
+	found := map[string]interruption{}
+	add := func(nodeName string, reason string, since time.Time, notice time.Duration) {
+		i := interruption{reason: reason, since: since}
+		if notice > 0 {
+			i.deadline = since.Add(notice)
+		}
+		if current, ok := found[nodeName]; !ok || i.since.Before(current.since) {
+			found[nodeName] = i
+		}
+	}
+	for _, n := range m.listNodes() {
+		for _, taint := range n.Spec.Taints {
+			notice, ok := interruptionTaints[taint.Key]
+			if !ok {
+				continue
+			}
+			// only NoExecute taints carry a timestamp, otherwise count from when the taint was first seen
+			since := time.Now()
+			if taint.TimeAdded != nil {
+				since = taint.TimeAdded.Time
+			} else if previous, ok := m.interrupted[n.Name]; ok && previous.reason == taint.Key {
+				since = previous.since
+			}
+			add(n.Name, taint.Key, since, notice)
+		}
+	}
+	for _, obj := range m.store.Events.List() {
+		event := obj.(*corev1.Event)
+		notice, ok := interruptionReasons[event.Reason]
+		if !ok || event.InvolvedObject.Kind != "Node" {
+			continue
+		}
+		since := event.LastTimestamp.Time
+		if since.IsZero() {
+			since = event.EventTime.Time
+		}
+		add(event.InvolvedObject.Name, event.Reason, since, notice)
+	}
+	return found
+}
+
+// interruptionBorder flashes the border of interrupted nodes, alternating every second
+func (m *Model) interruptionBorder(nodeName string, color lipgloss.TerminalColor) lipgloss.TerminalColor {
+	if _, ok := m.interrupted[nodeName]; !ok {
+		return color
+	}
+	if time.Now().Unix()%2 == 0 {
+		return red
+	}
+	return orange
+}
+
+// interruptionBadges shows the interruption reason with a countdown to the deadline, if known
+func (m *Model) interruptionBadges(nodeName string) []string {
+	i, ok := m.interrupted[nodeName]
+	if !ok {
+		return nil
+	}
+	if i.deadline.IsZero() {
+		return []string{fmt.Sprintf("⚡ %s %s", i.reason, format.Ago(i.since))}
+	}
+	left := time.Until(i.deadline)
+	if left <= 0 {
+		return []string{fmt.Sprintf("⚡ %s: reclaimed", i.reason)}
+	}
+	return []string{fmt.Sprintf("⚡ %s T-%d:%02d", i.reason, int(left.Minutes()), int(left.Seconds())%60)}
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// promptJump asks for the node to move the cursor to, by its position in the grid or a name
+// prefix. typed is what was already typed to open the prompt, such as the first digit.
+func (m *Model) promptJump(typed string) tea.Cmd {
+	if m.view != viewNodes || len(m.getNodes()) == 0 {
+		return nil
+	}
+	cmd := m.openPrompt("jump to node", "number or name prefix", m.jumpToNode)
+	m.prompt.input.SetValue(typed)
+	m.prompt.input.CursorEnd()
+	return cmd
+}
+
+// jumpToNode selects the Nth node of the grid, counting from 1, or the first node whose
+// name starts with the prefix
+func (m *Model) jumpToNode(value string) tea.Cmd {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+	nodes := m.getNodes()
+	if n, err := strconv.Atoi(value); err == nil {
+		if n < 1 || n > len(nodes) {
+			return m.setStatus(fmt.Sprintf("no node %d, %d nodes are shown", n, len(nodes)))
+		}
+		m.navigateTo(mark{nodeName: nodes[n-1].Name})
+		return nil
+	}
+	for _, node := range nodes {
+		if strings.HasPrefix(node.Name, value) {
+			m.navigateTo(mark{nodeName: node.Name})
+			return nil
+		}
+	}
+	return m.setStatus(fmt.Sprintf("no node shown starts with %q", value))
+}
@@ -0,0 +1,285 @@
+package main
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+)
+
+type keyMap map[string]key.Binding
+
+var keyMappings = keyMap{
+	"Move": key.NewBinding(
+		key.WithKeys("up", "down", "left", "right", "home", "end", "ctrl+left", "ctrl+right"),
+		key.WithHelp("↑/↓/←/→ home/end ctrl+←/→", "move, to first/last, to row start/end"),
+	),
+	"Tick": key.NewBinding(
+		key.WithKeys("t"),
+		key.WithHelp("t", "pause refresh tick"),
+	),
+	"Pause": key.NewBinding(
+		key.WithKeys("p"),
+		key.WithHelp("p", "pause/resume display"),
+	),
+	"Scrub": key.NewBinding(
+		key.WithKeys("[", "]"),
+		key.WithHelp("[/]", "scrub history"),
+	),
+	"Filter": key.NewBinding(
+		key.WithKeys("f"),
+		key.WithHelp("f", "cycle node filter"),
+	),
+	"Namespaces": key.NewBinding(
+		key.WithKeys("n"),
+		key.WithHelp("n", "namespace view"),
+	),
+	"Pods": key.NewBinding(
+		key.WithKeys("tab"),
+		key.WithHelp("tab", "select pods"),
+	),
+	"Details": key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("enter", "details"),
+	),
+	"Images": key.NewBinding(
+		key.WithKeys("i"),
+		key.WithHelp("i", "image view"),
+	),
+	"WhatIf": key.NewBinding(
+		key.WithKeys("w"),
+		key.WithHelp("w", "what-if capacity"),
+	),
+	"Pending": key.NewBinding(
+		key.WithKeys("P"),
+		key.WithHelp("P", "pending pods"),
+	),
+	"Chaos": key.NewBinding(
+		key.WithKeys("x"),
+		key.WithHelp("x", "chaos menu"),
+	),
+	"Mark": key.NewBinding(
+		key.WithKeys("m"),
+		key.WithHelp("m+letter", "set mark"),
+	),
+	"Jump": key.NewBinding(
+		key.WithKeys("'"),
+		key.WithHelp("'+letter", "jump to mark"),
+	),
+	"Cordon": key.NewBinding(
+		key.WithKeys("c"),
+		key.WithHelp("c", "cordon/uncordon"),
+	),
+	"Drain": key.NewBinding(
+		key.WithKeys("D"),
+		key.WithHelp("D", "drain node"),
+	),
+	"Delete": key.NewBinding(
+		key.WithKeys("d"),
+		key.WithHelp("d", "delete pod"),
+	),
+	"Scale": key.NewBinding(
+		key.WithKeys("s"),
+		key.WithHelp("s", "scale workload"),
+	),
+	"Audit": key.NewBinding(
+		key.WithKeys("A"),
+		key.WithHelp("A", "audit log"),
+	),
+	"Confirm": key.NewBinding(
+		key.WithKeys("C"),
+		key.WithHelp("C", "toggle dry-run confirm"),
+	),
+	"Label": key.NewBinding(
+		key.WithKeys("L"),
+		key.WithHelp("L", "edit node label/annotation"),
+	),
+	"Taint": key.NewBinding(
+		key.WithKeys("T"),
+		key.WithHelp("T", "add/remove node taint"),
+	),
+	"Reschedule": key.NewBinding(
+		key.WithKeys("R"),
+		key.WithHelp("R", "reschedule pod"),
+	),
+	"Topology": key.NewBinding(
+		key.WithKeys("S"),
+		key.WithHelp("S", "topology spread of workload"),
+	),
+	"Services": key.NewBinding(
+		key.WithKeys("v"),
+		key.WithHelp("v", "services (enter highlights endpoints)"),
+	),
+	"Traffic": key.NewBinding(
+		key.WithKeys("g"),
+		key.WithHelp("g", "ingress/gateway traffic map"),
+	),
+	"NetworkPolicy": key.NewBinding(
+		key.WithKeys("N"),
+		key.WithHelp("N", "network policy reachability of pod"),
+	),
+	"Diagnostics": key.NewBinding(
+		key.WithKeys("!"),
+		key.WithHelp("!", "DNS/kube-proxy checks"),
+	),
+	"Certificates": key.NewBinding(
+		key.WithKeys("E"),
+		key.WithHelp("E", "certificate expiry"),
+	),
+	"ControlPlane": key.NewBinding(
+		key.WithKeys("K"),
+		key.WithHelp("K", "control plane"),
+	),
+	"Rightsizing": key.NewBinding(
+		key.WithKeys("U"),
+		key.WithHelp("U", "usage vs requests"),
+	),
+	"Packing": key.NewBinding(
+		key.WithKeys("B"),
+		key.WithHelp("B", "bin-packing score"),
+	),
+	"Consolidation": key.NewBinding(
+		key.WithKeys("O"),
+		key.WithHelp("O", "consolidation candidates"),
+	),
+	"NodePools": key.NewBinding(
+		key.WithKeys("G"),
+		key.WithHelp("G", "group by node pool"),
+	),
+	"Upgrade": key.NewBinding(
+		key.WithKeys("V"),
+		key.WithHelp("V", "upgrade waves"),
+	),
+	"Churn": key.NewBinding(
+		key.WithKeys("Z"),
+		key.WithHelp("Z", "pod churn"),
+	),
+	"Export": key.NewBinding(
+		key.WithKeys("X"),
+		key.WithHelp("X", "export session metrics"),
+	),
+	"Split": key.NewBinding(
+		key.WithKeys("|"),
+		key.WithHelp("|", "split details pane"),
+	),
+	"Resize": key.NewBinding(
+		key.WithKeys("<", ">"),
+		key.WithHelp("</>", "resize split"),
+	),
+	"Focus": key.NewBinding(
+		key.WithKeys("shift+tab"),
+		key.WithHelp("shift+tab", "switch pane focus"),
+	),
+	"Tabs": key.NewBinding(
+		key.WithKeys("1", "2", "3", "4", "5"),
+		key.WithHelp("1-5/tab", "detail tabs"),
+	),
+	"Back": key.NewBinding(
+		key.WithKeys("esc"),
+		key.WithHelp("esc", "back"),
+	),
+	"Tasks": key.NewBinding(
+		key.WithKeys("J"),
+		key.WithHelp("J", "background tasks"),
+	),
+	"Undo": key.NewBinding(
+		key.WithKeys("u"),
+		key.WithHelp("u", "undo last action"),
+	),
+	"Rebalance": key.NewBinding(
+		key.WithKeys("b"),
+		key.WithHelp("b", "suggest rebalance"),
+	),
+	"Privileged": key.NewBinding(
+		key.WithKeys("H"),
+		key.WithHelp("H", "privileged pods only"),
+	),
+	"Finalizers": key.NewBinding(
+		key.WithKeys("F"),
+		key.WithHelp("F", "remove finalizer"),
+	),
+	"Fragmentation": key.NewBinding(
+		key.WithKeys("W"),
+		key.WithHelp("W", "fragmentation"),
+	),
+	"Dashboard": key.NewBinding(
+		key.WithKeys("a"),
+		key.WithHelp("a", "dashboard"),
+	),
+	"Profiles": key.NewBinding(
+		key.WithKeys("o"),
+		key.WithHelp("o", "profiles"),
+	),
+	"ShareState": key.NewBinding(
+		key.WithKeys("Y"),
+		key.WithHelp("Y", "share state"),
+	),
+	"FullNames": key.NewBinding(
+		key.WithKeys("l"),
+		key.WithHelp("l", "full names"),
+	),
+	"ExpandNode": key.NewBinding(
+		key.WithKeys("e"),
+		key.WithHelp("e", "expand node"),
+	),
+	"Zoom": key.NewBinding(
+		key.WithKeys("z"),
+		key.WithHelp("z", "zoom node"),
+	),
+	"ColorMode": key.NewBinding(
+		key.WithKeys("h"),
+		key.WithHelp("h", "color by"),
+	),
+	"Simulate": key.NewBinding(
+		key.WithKeys("r"),
+		key.WithHelp("r", "simulate"),
+	),
+	"Cleanup": key.NewBinding(
+		key.WithKeys("Q"),
+		key.WithHelp("Q", "clean up session"),
+	),
+	"Tour": key.NewBinding(
+		key.WithKeys("k"),
+		key.WithHelp("k", "next tour step"),
+	),
+	"HUD": key.NewBinding(
+		key.WithKeys("I"),
+		key.WithHelp("I", "perf HUD"),
+	),
+	"JumpNode": key.NewBinding(
+		key.WithKeys("j"),
+		key.WithHelp("j/1-9", "jump to node"),
+	),
+	"Select": key.NewBinding(
+		key.WithKeys(" "),
+		key.WithHelp("space", "select node or pod"),
+	),
+	"Compare": key.NewBinding(
+		key.WithKeys("="),
+		key.WithHelp("=", "compare selected"),
+	),
+	"Help": key.NewBinding(
+		key.WithKeys("?"),
+		key.WithHelp("?", "toggle help"),
+	),
+	"Quit": key.NewBinding(
+		key.WithKeys("q", "ctrl+c"),
+		key.WithHelp("q", "quit"),
+	),
+}
+
+// ShortHelp returns keybindings to be shown in the mini help view. It's part
+// of the key.Map interface.
+func (k keyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k["Move"], k["Pause"], k["Quit"], k["Help"]}
+}
+
+// FullHelp returns keybindings for the expanded help view. It's part of the
+// key.Map interface.
+func (k keyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k["Move"], k["Pods"], k["Details"], k["Back"], k["Tabs"], k["Split"], k["Resize"], k["Focus"], k["Help"], k["Quit"]},
+		{k["Pause"], k["Tick"], k["Scrub"], k["Export"], k["ShareState"]},
+		{k["Namespaces"], k["Images"], k["Pending"], k["Services"], k["Traffic"], k["Topology"], k["Certificates"], k["ControlPlane"], k["Churn"], k["Audit"], k["Tasks"], k["Fragmentation"], k["Dashboard"]},
+		{k["Filter"], k["NodePools"], k["WhatIf"], k["NetworkPolicy"], k["Rightsizing"], k["Packing"], k["Consolidation"], k["Upgrade"], k["Rebalance"], k["Privileged"], k["Profiles"], k["FullNames"], k["ExpandNode"], k["Zoom"], k["ColorMode"]},
+		{k["Chaos"], k["Mark"], k["Jump"], k["JumpNode"], k["Diagnostics"], k["Simulate"], k["Cleanup"], k["Tour"], k["HUD"], k["Select"], k["Compare"]},
+		{k["Cordon"], k["Drain"], k["Delete"], k["Reschedule"], k["Scale"], k["Label"], k["Taint"], k["Confirm"], k["Undo"], k["Finalizers"]},
+	}
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/bwagner5/kube-demo/pkg/pod"
+)
+
+// latencyWindow is how far back pod creations count towards the rolling latency percentiles
+const latencyWindow = 10 * time.Minute
+
+// latencyPercentiles are the rolling p50 and p95 of a latency
+type latencyPercentiles struct {
+	p50, p95 time.Duration
+	samples  int
+}
+
+// percentiles computes the p50 and p95 of the samples using the nearest rank
+func percentiles(samples []time.Duration) latencyPercentiles {
+	if len(samples) == 0 {
+		return latencyPercentiles{}
+	}
+	sorted := append([]time.Duration(nil), samples...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	rank := func(p int) time.Duration {
+		return sorted[(len(sorted)*p+99)/100-1]
+	}
+	return latencyPercentiles{p50: rank(50), p95: rank(95), samples: len(sorted)}
+}
+
+// schedulingLatencies computes the scheduling and ready latency percentiles of
+// the pods created within the latency window
+func (m *Model) schedulingLatencies() (scheduled latencyPercentiles, ready latencyPercentiles) {
+	var toScheduled, toReady []time.Duration
+	cutoff := time.Now().Add(-latencyWindow)
+	for _, p := range m.listPods() {
+		if p.CreationTimestamp.Time.Before(cutoff) {
+			continue
+		}
+		if d, ok := pod.SchedulingLatency(p); ok {
+			toScheduled = append(toScheduled, d)
+		}
+		if d, ok := pod.ReadyLatency(p); ok {
+			toReady = append(toReady, d)
+		}
+	}
+	return percentiles(toScheduled), percentiles(toReady)
+}
+
+// latencySummary renders the rolling latency percentiles for the status line, or "" without samples
+func (m *Model) latencySummary() string {
+	if m.scheduledLatency.samples == 0 {
+		return ""
+	}
+	line := fmt.Sprintf("scheduled p50 %s p95 %s", m.scheduledLatency.p50, m.scheduledLatency.p95)
+	if m.readyLatency.samples > 0 {
+		line += fmt.Sprintf(", ready p50 %s p95 %s", m.readyLatency.p50, m.readyLatency.p95)
+	}
+	return line + fmt.Sprintf(" (%d pods)", m.scheduledLatency.samples)
+}
+
+// podLatency renders the pod's own scheduling and ready latency for pod details
+func podLatency(p *corev1.Pod) string {
+	scheduled, ready := "-", "-"
+	if d, ok := pod.SchedulingLatency(p); ok {
+		scheduled = d.String()
+	}
+	if d, ok := pod.ReadyLatency(p); ok {
+		ready = d.String()
+	}
+	return fmt.Sprintf("Latency: scheduled after %s, ready after %s\n", scheduled, ready)
+}
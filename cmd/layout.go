@@ -0,0 +1,86 @@
+package main
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// pane is a region of the split layout that can hold the keyboard focus
+type pane int
+
+const (
+	paneGrid pane = iota
+	paneDetails
+)
+
+// splitStep is how much the split moves per resize key press
+const splitStep = 0.1
+
+// detailsPaneStyle draws the divider between the grid and the details pane
+var detailsPaneStyle = lipgloss.NewStyle().BorderStyle(lipgloss.NormalBorder()).BorderLeft(true).BorderForeground(grey)
+
+// splitActive returns true if details are shown beside the grid instead of full screen
+func (m *Model) splitActive() bool {
+	return m.split && m.showingDetails()
+}
+
+// toggleSplit switches between full screen details and the split layout
+func (m *Model) toggleSplit() {
+	m.split = !m.split
+	m.focus = paneGrid
+}
+
+// resizeSplit moves the divider by delta of the screen width, keeping both panes usable
+func (m *Model) resizeSplit(delta float64) {
+	m.splitRatio += delta
+	if m.splitRatio < 0.2 {
+		m.splitRatio = 0.2
+	}
+	if m.splitRatio > 0.8 {
+		m.splitRatio = 0.8
+	}
+}
+
+// cycleFocus moves the keyboard focus to the other pane of the split layout
+func (m *Model) cycleFocus() {
+	if !m.splitActive() || m.focus == paneDetails {
+		m.focus = paneGrid
+		return
+	}
+	m.focus = paneDetails
+}
+
+// updateDetailsPane scrolls the details pane while it has the focus, returning
+// false for keys the pane does not handle
+func (m *Model) updateDetailsPane(msg tea.KeyMsg) (tea.Cmd, bool) {
+	switch msg.String() {
+	case "up", "down", "pgup", "pgdown", "ctrl+u", "ctrl+d":
+		var cmd tea.Cmd
+		m.viewport, cmd = m.viewport.Update(msg)
+		return cmd, true
+	}
+	return nil, false
+}
+
+// layoutSplit renders the grid and the details pane side by side at the given size
+func (m *Model) layoutSplit(grid string, width int, height int) string {
+	style := detailsPaneStyle
+	if m.focus == paneDetails {
+		style = style.Copy().BorderForeground(selectedNodeBorder)
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top, grid, style.Render(m.detailsPane(width-style.GetHorizontalFrameSize(), height)))
+}
+
+// detailsPane renders the scrollable details of the selection at the given
+// size, under a tab bar when the selection has tabs
+func (m *Model) detailsPane(width int, height int) string {
+	m.viewport.Width = width
+	m.viewport.Height = height
+	header := ""
+	if m.hasTabs() {
+		header = m.tabBar() + "\n"
+		m.viewport.Height = height - lipgloss.Height(header) + 1
+	}
+	m.viewport.SetContent(m.detailContent())
+	return header + m.viewport.View()
+}
@@ -1,26 +1,43 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"log"
 	"os"
-	"sort"
+	"path/filepath"
 	"strings"
+	"sync"
+	"text/template"
 	"time"
 
 	"github.com/charmbracelet/bubbles/help"
-	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
 	"github.com/samber/lo"
-	"golang.org/x/term"
-	"gopkg.in/yaml.v2"
+	"github.com/spf13/pflag"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/client-go/informers"
+	coreinformers "k8s.io/client-go/informers/core/v1"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/cache"
-	"k8s.io/client-go/tools/clientcmd"
+	metricsclientset "k8s.io/metrics/pkg/client/clientset/versioned"
+
+	"github.com/bwagner5/kube-demo/pkg/binpack"
+	"github.com/bwagner5/kube-demo/pkg/format"
+	"github.com/bwagner5/kube-demo/pkg/state"
 )
 
 var canvasStyle = lipgloss.NewStyle().Padding(1, 2, 1, 2)
@@ -30,6 +47,7 @@ var black = lipgloss.Color("#000000")
 var pink = lipgloss.Color("#F87575")
 var teal = lipgloss.Color("#27CEBD")
 var grey = lipgloss.Color("#6C7D89")
+var green = lipgloss.Color("#7BD389")
 
 var nodeBorder = grey
 var selectedNodeBorder = pink
@@ -57,145 +75,865 @@ var podStyle = lipgloss.NewStyle().
 	Height(0).
 	Width(1)
 
-type keyMap map[string]key.Binding
+type k8sStateChange struct{}
 
-var keyMappings = keyMap{
-	"Move": key.NewBinding(
-		key.WithKeys("up", "down", "left", "right"),
-		key.WithHelp("↑/↓/←/→", "move"),
-	),
-	"Help": key.NewBinding(
-		key.WithKeys("?"),
-		key.WithHelp("?", "toggle help"),
-	),
-	"Quit": key.NewBinding(
-		key.WithKeys("q", "esc", "ctrl+c"),
-		key.WithHelp("q", "quit"),
-	),
+// refreshTick is emitted on every refresh interval so that time-derived fields
+// (ages, countdowns) re-render even when no informer events arrive. The id
+// ties a tick to the chain that scheduled it so stale chains die off after
+// a pause/resume.
+type refreshTick struct {
+	id int
 }
 
-// ShortHelp returns keybindings to be shown in the mini help view. It's part
-// of the key.Map interface.
-func (k keyMap) ShortHelp() []key.Binding {
-	return []key.Binding{k["Move"], k["Quit"], k["Help"]}
+// Options configures the Model
+type Options struct {
+	ConfigFlags     *genericclioptions.ConfigFlags
+	LabelSelector   string
+	RefreshInterval time.Duration
+	HistoryWindow   time.Duration
+	TaintKey        string
+	ChaosNamespaces []string
+	KubectlLog      io.Writer
+	AuditLog        io.Writer
+	ConfirmActions  bool
+	ExportFile      string
+	UnderuseRatio   float64
+	OveruseRatio    float64
+	Monochrome      bool
+	ColorLabel      string
+	QPS             float32
+	Burst           int
+	ConfigFile      string
+	Profiles        []profile
+	Profile         string
+	NodeTemplate    *template.Template
+	FocusNode       string
+	FocusWorkload   string
+	Restore         *viewState
+	FromFiles       []string
+	SimulatePods    []*corev1.Pod
+	Session         string
+	NotifyWebhook   string
+	Tour            *tour
+	Light           bool
+	Wrap            bool
 }
 
-// FullHelp returns keybindings for the expanded help view. It's part of the
-// key.Map interface.
-func (k keyMap) FullHelp() [][]key.Binding {
-	return [][]key.Binding{
-		{k["Move"], k["Help"], k["Quit"]},
-	}
+type Model struct {
+	Nodes              []*corev1.Node
+	selectedNode       int
+	selectedPod        int
+	focusStack         []focusLevel
+	selectedContainer  int
+	informerFactory    informers.SharedInformerFactory
+	store              ClusterStore
+	stopCh             chan struct{}
+	k8sStateUpdate     chan struct{}
+	help               help.Model
+	viewport           viewport.Model
+	refreshInterval    time.Duration
+	refreshPaused      bool
+	refreshID          int
+	frozen             *snapshot
+	pausedChanges      int
+	status             string
+	statusID           int
+	history            *history
+	historyDirty       bool
+	scrubbing          bool
+	nodeFilter         nodeFilter
+	taintKey           string
+	view               viewMode
+	selectedNamespace  int
+	selectedImage      int
+	readiness          *readinessTracker
+	stopOnce           sync.Once
+	fatalCh            chan error
+	fatalErr           error
+	watchErrCh         chan error
+	width              int
+	height             int
+	prompt             *prompt
+	whatIf             *whatIf
+	kubeClient         kubernetes.Interface
+	selectedPending    int
+	objectEvents       map[types.UID][]corev1.Event
+	preemptions        map[types.UID]string
+	menu               *menu
+	chaosNamespaces    map[string]bool
+	marks              map[string]mark
+	pendingKey         string
+	kubectlHistory     []string
+	kubectlLog         io.Writer
+	auditLog           []auditEntry
+	auditFile          io.Writer
+	confirmActions     bool
+	denied             map[string]bool
+	identity           string
+	retryCh            chan retryMsg
+	throttleCh         chan throttledMsg
+	rescheduling       *reschedule
+	topologyWorkload   workloadRef
+	selectedService    int
+	highlightService   string
+	highlighted        serviceEndpoints
+	selectedRoute      int
+	netpolPod          types.UID
+	netpolTarget       string
+	reachable          map[types.UID]reachability
+	diagnostics        *diagnostics
+	certs              []certificate
+	metricsClient      metricsclientset.Interface
+	podUsage           map[string][]usageSample
+	namespace          string
+	rightsizing        bool
+	underuseRatio      float64
+	overuseRatio       float64
+	showPacking        bool
+	packing            map[string]binpack.Packing
+	consolidation      map[string]bool
+	groupByPool        bool
+	groupSizes         map[string]nodeGroupSize
+	interrupted        map[string]interruption
+	upgradeMode        bool
+	versions           []string
+	churn              *churnTracker
+	scheduledLatency   latencyPercentiles
+	readyLatency       latencyPercentiles
+	provisioned        map[string]provisioning
+	exportFile         string
+	split              bool
+	splitRatio         float64
+	focus              pane
+	podLogs            map[string]string
+	detailTab          detailTab
+	tabOffsets         map[detailTab]int
+	tasks              []*task
+	nextTaskID         int
+	taskCh             chan taskProgressMsg
+	spinner            spinner.Model
+	spinning           bool
+	selectedTask       int
+	undoStack          []undoEntry
+	rebalance          *rebalancing
+	privilegedOnly     bool
+	serviceAccounts    map[string]serviceAccountAccess
+	serviceAccountErrs map[string]error
+	pullSecrets        map[types.UID][]pullSecretState
+	pullSecretErrs     map[types.UID]error
+	configFile         string
+	profiles           []profile
+	nodeTemplate       *template.Template
+	profile            string
+	startTarget        *startTarget
+	restored           *profile
+	contextName        string
+	monochrome         bool
+	selector           string
+	fullNames          bool
+	expandedNode       string
+	zoomed             bool
+	colorMode          colorMode
+	colorLabel         string
+	simulatePods       []*corev1.Pod
+	simulation         *simulation
+	session            string
+	api                *apiServer
+	milestoneTracker   *milestoneTracker
+	notifyWebhook      string
+	tour               *tour
+	tracker            *state.Tracker
+	fullObject         fullObject
+	light              bool
+	synced             bool
+	perf               *perfStats
+	showHUD            bool
+	crash              error
+	persisted          []byte
+	scrollOffsets      map[viewMode]int
+	wrap               bool
+	selectedNodes      map[string]bool
+	selectedPods       map[types.UID]bool
+	comparingPods      bool
+	preloaded          cache.Store
+	keys               keyMap
+	nodeRows           [][]int
+	nominated          map[string][]*corev1.Pod
+	rowOffsets         map[viewMode]rowOffset
 }
 
-type k8sStateChange struct{}
-
-type Model struct {
-	Nodes           []*corev1.Node
-	selectedNode    int
-	selectedPod     int
-	podSelection    bool
-	details         bool
-	informerFactory informers.SharedInformerFactory
-	nodeInformer    cache.SharedIndexInformer
-	podInformer     cache.SharedIndexInformer
-	stopCh          chan struct{}
-	k8sStateUpdate  chan struct{}
-	help            help.Model
-	viewport        viewport.Model
-}
-
-func New() *Model {
-	config, err := clientcmd.BuildConfigFromFlags("", os.Getenv("KUBECONFIG"))
+func New(opts Options) *Model {
+	if len(opts.FromFiles) > 0 {
+		return newFileModel(opts)
+	}
+	config, err := opts.ConfigFlags.ToRESTConfig()
 	if err != nil {
 		log.Fatalf("could not initialize kubeconfig: %v", err)
 	}
-	kubeclient, err := kubernetes.NewForConfig(config)
+	throttle := newThrottleReporter(opts.QPS, opts.Burst)
+	config.RateLimiter = throttle
+	kubeConfig := config
+	if opts.Light {
+		// protobuf lists are a fraction of the size of JSON ones, the custom resource and metrics clients keep JSON
+		kubeConfig = rest.CopyConfig(config)
+		kubeConfig.ContentType = runtime.ContentTypeProtobuf
+	}
+	kubeclient, err := kubernetes.NewForConfig(kubeConfig)
 	if err != nil {
 		log.Fatalf("could not initialize kube-client: %v", err)
 	}
-	informerFactory := informers.NewSharedInformerFactory(kubeclient, time.Minute*10)
-	stopCh := make(chan struct{})
-	k8sStateUpdate := make(chan struct{})
+	// only an explicit --namespace narrows the view, the kubeconfig context namespace does not
+	namespace := *opts.ConfigFlags.Namespace
+	informerFactory := informers.NewSharedInformerFactoryWithOptions(kubeclient, time.Minute*10, informers.WithNamespace(namespace))
 	nodeInformer := informerFactory.Core().V1().Nodes().Informer()
-	podInformer := informerFactory.Core().V1().Pods().Informer()
-	model := &Model{
-		informerFactory: informerFactory,
-		nodeInformer:    nodeInformer,
-		podInformer:     podInformer,
-		stopCh:          stopCh,
-		k8sStateUpdate:  k8sStateUpdate,
+	podInformer := informerFactory.InformerFor(&corev1.Pod{}, func(client kubernetes.Interface, resync time.Duration) cache.SharedIndexInformer {
+		return coreinformers.NewFilteredPodInformer(client, namespace, resync, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc, state.NodeNameIndex: state.NodeNameIndexFunc}, func(options *metav1.ListOptions) {
+			options.LabelSelector = opts.LabelSelector
+		})
+	})
+	quotaInformer := informerFactory.Core().V1().ResourceQuotas().Informer()
+	limitRangeInformer := informerFactory.Core().V1().LimitRanges().Informer()
+	eventInformer := informerFactory.Core().V1().Events().Informer()
+	replicaSetInformer := informerFactory.Apps().V1().ReplicaSets().Informer()
+	serviceInformer := informerFactory.Core().V1().Services().Informer()
+	endpointSliceInformer := informerFactory.Discovery().V1().EndpointSlices().Informer()
+	ingressInformer := informerFactory.Networking().V1().Ingresses().Informer()
+	networkPolicyInformer := informerFactory.Networking().V1().NetworkPolicies().Informer()
+	namespaceInformer := informerFactory.Core().V1().Namespaces().Informer()
+	csrInformer := informerFactory.Certificates().V1().CertificateSigningRequests().Informer()
+	deploymentInformer := informerFactory.Apps().V1().Deployments().Informer()
+	daemonSetInformer := informerFactory.Apps().V1().DaemonSets().Informer()
+	// custom resources are only watched if the cluster serves them, otherwise their store stays empty
+	var optionalInformers []cache.SharedIndexInformer
+	optionalStore := func(resource schema.GroupVersionResource) cache.Store {
+		informer, err := newOptionalInformer(config, kubeclient.Discovery(), resource, namespace)
+		if err != nil {
+			log.Fatalf("could not initialize %s informer: %v", resource.Resource, err)
+		}
+		if informer == nil {
+			return cache.NewStore(cache.MetaNamespaceKeyFunc)
+		}
+		optionalInformers = append(optionalInformers, informer)
+		return informer.GetStore()
+	}
+	model := newModel(ClusterStore{
+		Nodes:                      nodeInformer.GetStore(),
+		Pods:                       podInformer.GetStore(),
+		ResourceQuotas:             quotaInformer.GetStore(),
+		LimitRanges:                limitRangeInformer.GetStore(),
+		Events:                     eventInformer.GetStore(),
+		ReplicaSets:                replicaSetInformer.GetStore(),
+		Services:                   serviceInformer.GetStore(),
+		EndpointSlices:             endpointSliceInformer.GetStore(),
+		Ingresses:                  ingressInformer.GetStore(),
+		NetworkPolicies:            networkPolicyInformer.GetStore(),
+		Namespaces:                 namespaceInformer.GetStore(),
+		CertificateSigningRequests: csrInformer.GetStore(),
+		Deployments:                deploymentInformer.GetStore(),
+		DaemonSets:                 daemonSetInformer.GetStore(),
+		HTTPRoutes:                 optionalStore(httpRouteResource),
+		VerticalPodAutoscalers:     optionalStore(vpaResource),
+	}, opts)
+	model.informerFactory = informerFactory
+	model.kubeClient = kubeclient
+	model.namespace = namespace
+	if metricsClient, err := metricsclientset.NewForConfig(config); err == nil {
+		model.metricsClient = metricsClient
+	}
+	model.throttleCh = throttle.notices
+	model.identity = identityLabel(opts.ConfigFlags, config)
+	model.contextName = contextName(opts.ConfigFlags)
+	model.watch(nodeInformer)
+	nodeInformer.AddEventHandler(model.readiness.handler())
+	nodeInformer.AddEventHandler(model.api.handler())
+	model.watch(podInformer)
+	if opts.Light {
+		// replaces the trimming transform set by watch
+		_ = podInformer.SetTransform(lightPod)
+	}
+	podInformer.AddEventHandler(model.churn.handler())
+	podInformer.AddEventHandler(model.api.handler())
+	model.watch(quotaInformer)
+	model.watch(limitRangeInformer)
+	model.watch(eventInformer)
+	model.watch(replicaSetInformer)
+	model.watch(serviceInformer)
+	model.watch(endpointSliceInformer)
+	model.watch(ingressInformer)
+	model.watch(networkPolicyInformer)
+	model.watch(namespaceInformer)
+	model.watch(csrInformer)
+	model.watch(deploymentInformer)
+	model.watch(daemonSetInformer)
+	informerFactory.Start(model.stopCh) // runs in backgrounds
+	go model.preloadNodes(kubeclient, nodeInformer)
+	go model.tracker.Run(model.stopCh)
+	for _, informer := range optionalInformers {
+		model.watch(informer)
+		go informer.Run(model.stopCh)
+	}
+	return model
+}
+
+// newModel constructs a Model reading from the given store without any API server connection
+func newModel(store ClusterStore, opts Options) *Model {
+	preloaded := cache.NewStore(cache.MetaNamespaceKeyFunc)
+	return &Model{
+		store:           store,
+		stopCh:          make(chan struct{}),
+		k8sStateUpdate:  make(chan struct{}),
 		help:            help.New(),
 		viewport:        viewport.New(0, 0),
+		refreshInterval: opts.RefreshInterval,
+		history:         newHistory(opts.HistoryWindow),
+		splitRatio:      0.5,
+		focusStack:      []focusLevel{focusCluster},
+		view:            viewDashboard,
+		readiness:       newReadinessTracker(),
+		churn:           newChurnTracker(),
+		preloaded:       preloaded,
+		keys:            keyMappings,
+		tracker: state.NewTracker(state.Stores{
+			Nodes:          store.Nodes,
+			PreloadedNodes: preloaded,
+			Pods:           store.Pods,
+			Deployments:    store.Deployments,
+			ReplicaSets:    store.ReplicaSets,
+			DaemonSets:     store.DaemonSets,
+		}),
+		milestoneTracker:   newMilestoneTracker(),
+		notifyWebhook:      opts.NotifyWebhook,
+		tour:               opts.Tour,
+		api:                newAPIServer(store),
+		fatalCh:            make(chan error, 1),
+		watchErrCh:         make(chan error, 1),
+		retryCh:            make(chan retryMsg, 1),
+		taskCh:             make(chan taskProgressMsg, 16),
+		spinner:            spinner.New(spinner.WithSpinner(spinner.MiniDot)),
+		taintKey:           opts.TaintKey,
+		objectEvents:       map[types.UID][]corev1.Event{},
+		podLogs:            map[string]string{},
+		pullSecrets:        map[types.UID][]pullSecretState{},
+		pullSecretErrs:     map[types.UID]error{},
+		serviceAccounts:    map[string]serviceAccountAccess{},
+		serviceAccountErrs: map[string]error{},
+		detailTab:          tabDescribe,
+		tabOffsets:         map[detailTab]int{},
+		marks:              map[string]mark{},
+		podUsage:           map[string][]usageSample{},
+		provisioned:        map[string]provisioning{},
+		exportFile:         opts.ExportFile,
+		underuseRatio:      opts.UnderuseRatio,
+		overuseRatio:       opts.OveruseRatio,
+		kubectlLog:         opts.KubectlLog,
+		auditFile:          opts.AuditLog,
+		confirmActions:     opts.ConfirmActions,
+		configFile:         opts.ConfigFile,
+		profiles:           opts.Profiles,
+		nodeTemplate:       opts.NodeTemplate,
+		profile:            opts.Profile,
+		startTarget:        newStartTarget(opts),
+		selector:           opts.LabelSelector,
+		monochrome:         opts.Monochrome,
+		colorLabel:         opts.ColorLabel,
+		simulatePods:       opts.SimulatePods,
+		session:            opts.Session,
+		perf:               newPerfStats(),
+		scrollOffsets:      map[viewMode]int{},
+		rowOffsets:         map[viewMode]rowOffset{},
+		light:              opts.Light,
+		wrap:               opts.Wrap,
+		selectedNodes:      map[string]bool{},
+		selectedPods:       map[types.UID]bool{},
+		restored:           restoredSettings(opts),
+		chaosNamespaces:    lo.SliceToMap(opts.ChaosNamespaces, func(namespace string) (string, bool) { return namespace, true }),
 	}
-	model.nodeInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
-		AddFunc:    func(_ interface{}) { model.k8sStateUpdate <- struct{}{} },
-		UpdateFunc: func(_, _ interface{}) { model.k8sStateUpdate <- struct{}{} },
-		DeleteFunc: func(_ interface{}) { model.k8sStateUpdate <- struct{}{} },
-	})
-	model.podInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
-		AddFunc:    func(_ interface{}) { model.k8sStateUpdate <- struct{}{} },
-		UpdateFunc: func(_, _ interface{}) { model.k8sStateUpdate <- struct{}{} },
-		DeleteFunc: func(_ interface{}) { model.k8sStateUpdate <- struct{}{} },
+}
+
+// watch signals a state change to the UI on every event from the informer,
+// trims the objects it caches and routes its watch errors to the status line
+func (m *Model) watch(informer cache.SharedIndexInformer) {
+	_ = informer.SetWatchErrorHandler(m.watchErrorHandler)
+	_ = informer.SetTransform(trimObject)
+	// the snapshot is invalidated before the UI is signalled, so the re-render sees the change
+	tracker := m.tracker.Handler()
+	informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) { m.perf.countEvent(); tracker.OnAdd(obj); m.k8sStateUpdate <- struct{}{} },
+		UpdateFunc: func(oldObj, obj interface{}) {
+			m.perf.countEvent()
+			tracker.OnUpdate(oldObj, obj)
+			m.k8sStateUpdate <- struct{}{}
+		},
+		DeleteFunc: func(obj interface{}) { m.perf.countEvent(); tracker.OnDelete(obj); m.k8sStateUpdate <- struct{}{} },
 	})
-	informerFactory.Start(stopCh) // runs in backgrounds
-	return model
 }
 
 func (m *Model) Init() tea.Cmd {
-	return tea.Batch(func() tea.Msg {
-		m.informerFactory.WaitForCacheSync(m.stopCh)
-		return k8sStateChange{}
-	}, tea.EnterAltScreen)
+	return tea.Batch(m.waitForCacheSync, m.waitForStateChange, tea.EnterAltScreen, m.tick(), m.waitForFatal, m.waitForWatchError, m.waitForRetry, m.waitForThrottle, m.waitForTaskProgress, m.checkCapabilities(), m.sampleMetrics(0), m.applyStartProfile(), m.persist())
 }
 
-func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+// tick schedules the next refreshTick, or nothing if ticking is disabled or paused
+func (m *Model) tick() tea.Cmd {
+	if m.refreshInterval <= 0 || m.refreshPaused {
+		return nil
+	}
+	id := m.refreshID
+	return tea.Tick(m.refreshInterval, func(_ time.Time) tea.Msg {
+		return refreshTick{id: id}
+	})
+}
+
+func (m *Model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if m.prompt != nil {
+			return m, m.updatePrompt(msg)
+		}
+		if m.menu != nil {
+			return m, m.updateMenu(msg)
+		}
+		if m.pendingKey != "" {
+			return m, m.updateMarkKey(msg)
+		}
+		if m.splitActive() && m.focus == paneDetails {
+			if cmd, handled := m.updateDetailsPane(msg); handled {
+				return m, cmd
+			}
+		}
 		switch msg.String() {
 		case "ctrl+c", "q":
-			close(m.stopCh)
+			m.shutdown()
 			return m, tea.Quit
-		case "left", "right", "up", "down":
-			m.selectedNode = m.moveCursor(msg)
+		case "left", "right", "up", "down", "home", "end", "ctrl+left", "ctrl+right":
+			m.moveSelection(msg)
 		case "enter":
-			m.details = !m.details
+			switch m.view {
+			case viewServices:
+				m.toggleServiceHighlight()
+				return m, nil
+			case viewTraffic:
+				m.highlightRoute()
+				return m, nil
+			case viewTasks:
+				return m, m.cancelSelectedTask()
+			case viewDashboard:
+				m.toggleView(viewDashboard)
+				return m, nil
+			}
+			return m, m.enter()
+		case "esc":
+			if len(m.focusStack) == 1 && m.clearSelection() {
+				return m, m.setStatus("selection cleared")
+			}
+			m.popFocus()
+		case "n":
+			m.toggleView(viewNamespaces)
+		case "i":
+			m.toggleView(viewImages)
+		case "P":
+			m.toggleView(viewPending)
+		case "A":
+			m.toggleView(viewAudit)
+		case "1", "2", "3", "4", "5", "6", "7", "8", "9":
+			if m.hasTabs() {
+				if msg.String() <= "5" {
+					return m, m.selectTab(detailTab(msg.String()[0] - '1'))
+				}
+				return m, nil
+			}
+			return m, m.promptJump(msg.String())
+		case "tab":
+			if m.hasTabs() && (!m.split || m.focus == paneDetails) {
+				return m, m.nextTab()
+			}
+			m.togglePodSelection()
+		case "c":
+			return m, m.toggleCordon()
+		case "D":
+			return m, m.drain()
+		case "d":
+			return m, m.deleteSelectedPod()
+		case "s":
+			return m, m.promptScale()
+		case "C":
+			m.confirmActions = !m.confirmActions
+			return m, m.setStatus(fmt.Sprintf("confirm actions: %t", m.confirmActions))
+		case "L":
+			return m, m.metadataMenu()
+		case "T":
+			return m, m.promptTaint()
+		case "R":
+			return m, m.rescheduleSelectedPod()
+		case "S":
+			m.showTopology()
+		case "v":
+			m.toggleView(viewServices)
+		case "g":
+			m.toggleView(viewTraffic)
+		case "N":
+			m.toggleNetworkPolicy()
+		case "!":
+			return m, m.toggleDiagnostics()
+		case "E":
+			m.toggleView(viewCertificates)
+		case "K":
+			m.toggleView(viewControlPlane)
+		case "U":
+			m.rightsizing = !m.rightsizing
+		case "B":
+			m.showPacking = !m.showPacking
+			m.packing = m.packingOf()
+		case "O":
+			return m, m.toggleConsolidation()
+		case "G":
+			return m, m.toggleNodePools()
+		case "V":
+			m.upgradeMode = !m.upgradeMode
+			m.versions = m.kubeletVersions()
+		case "Z":
+			m.toggleView(viewChurn)
+		case "X":
+			return m, m.exportMetrics()
+		case "|":
+			m.toggleSplit()
+		case "<":
+			m.resizeSplit(-splitStep)
+		case ">":
+			m.resizeSplit(splitStep)
+		case "shift+tab":
+			m.cycleFocus()
+		case "J":
+			m.toggleView(viewTasks)
+		case "u":
+			return m, m.undoLast()
+		case "b":
+			return m, m.toggleRebalance()
+		case "H":
+			m.togglePrivilegedOnly()
+		case "F":
+			return m, m.finalizerMenu()
+		case "W":
+			m.toggleView(viewFragmentation)
+		case "a":
+			m.toggleView(viewDashboard)
+		case "o":
+			return m, m.profilesMenu()
+		case "Y":
+			return m, m.exportState()
+		case "l":
+			return m, m.toggleFullNames()
+		case "e":
+			return m, m.toggleExpandedNode()
+		case "z":
+			return m, m.toggleZoom()
+		case "h":
+			return m, m.cycleColorMode()
+		case "r":
+			return m, m.toggleSimulation()
+		case "Q":
+			return m, m.confirmCleanup()
+		case "k":
+			return m, m.skipTourStep()
+		case "I":
+			m.toggleHUD()
+		case "j":
+			return m, m.promptJump("")
+		case " ":
+			return m, m.toggleSelection()
+		case "=":
+			return m, m.toggleComparison()
 		case "?":
 			m.help.ShowAll = !m.help.ShowAll
+		case "t":
+			m.refreshPaused = !m.refreshPaused
+			m.refreshID++
+			return m, m.tick()
+		case "p":
+			return m, m.togglePause()
+		case "[":
+			m.scrub(-1)
+		case "]":
+			m.scrub(1)
+		case "f":
+			m.cycleFilter()
+		case "x":
+			return m, m.chaosMenu()
+		case "m", "'":
+			m.pendingKey = msg.String()
+		case "w":
+			if m.whatIf != nil {
+				m.whatIf = nil
+				return m, nil
+			}
+			return m, m.promptWhatIf()
+		}
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+	case signalMsg:
+		m.fatalErr = fmt.Errorf("received %s", msg.signal)
+		m.shutdown()
+		return m, tea.Quit
+	case fatalMsg:
+		m.fatalErr = msg.err
+		m.shutdown()
+		return m, tea.Quit
+	case persistTick:
+		if err := m.persistState(); err != nil {
+			return m, tea.Batch(m.setStatus(fmt.Sprintf("could not save the view state: %v", err)), m.persist())
+		}
+		return m, m.persist()
+	case metricsMsg:
+		return m, m.recordMetrics(msg)
+	case autoscalerStatusMsg:
+		m.groupSizes = msg.sizes
+	case diagnosticsMsg:
+		if msg.err != nil {
+			return m, m.setStatus(fmt.Sprintf("diagnostics failed: %v", msg.err))
+		}
+		m.diagnostics = msg.result
+		m.status = ""
+		return m, nil
+	case capabilitiesMsg:
+		return m, m.applyCapabilities(msg)
+	case dryRunResultMsg:
+		return m, m.confirmDryRun(msg)
+	case actionResultMsg:
+		m.finishTask(msg.task, msg.err)
+		m.audit(msg)
+		return m, m.actionResult(msg)
+	case eventsMsg:
+		if msg.err != nil {
+			return m, m.setStatus(fmt.Sprintf("could not load events: %v", msg.err))
+		}
+		m.objectEvents[msg.uid] = msg.events
+	case fullObjectMsg:
+		if msg.err != nil {
+			return m, m.setStatus(fmt.Sprintf("could not load the full object: %v", msg.err))
+		}
+		m.fullObject = msg.object
+	case pullSecretsMsg:
+		m.recordPullSecrets(msg)
+	case serviceAccountMsg:
+		m.recordServiceAccountAccess(msg)
+	case podLogsMsg:
+		if msg.err != nil {
+			return m, m.setStatus(fmt.Sprintf("could not load logs: %v", msg.err))
+		}
+		m.podLogs[msg.key] = msg.logs
+	case throttledMsg:
+		return m, tea.Batch(m.setStatus(msg.String()), m.waitForThrottle)
+	case taskProgressMsg:
+		m.updateTaskProgress(msg)
+		return m, m.waitForTaskProgress
+	case notifyResultMsg:
+		if msg.err != nil {
+			return m, m.setStatus(fmt.Sprintf("could not notify webhook: %v", msg.err))
 		}
+		return m, nil
+	case simulationStepMsg:
+		if msg.simulation == m.simulation && m.simulation.advance() {
+			return m, m.stepSimulation()
+		}
+		return m, nil
+	case spinner.TickMsg:
+		return m, m.spin(msg)
+	case retryMsg:
+		return m, tea.Batch(m.setStatus(msg.String()), m.waitForRetry)
+	case watchErrorMsg:
+		return m, tea.Batch(m.setStatus(fmt.Sprintf("watch error: %v", msg.err)), m.waitForWatchError)
+	case clearStatus:
+		if msg.id == m.statusID {
+			m.status = ""
+		}
+	case refreshTick:
+		if msg.id != m.refreshID {
+			return m, nil
+		}
+		m.recordHistory()
+		m.trackReschedule()
+		m.pollWindowSize()
+		return m, m.tick()
 	case k8sStateChange:
-		return m, func() tea.Msg {
-			select {
-			case <-m.k8sStateUpdate:
-				return k8sStateChange{}
-			case <-m.stopCh:
-				return nil
-			}
+		if m.frozen != nil {
+			m.pausedChanges++
 		}
+		m.historyDirty = true
+		m.recordHistory()
+		m.recompute()
+		m.clampSelection()
+		if !m.synced {
+			return m, tea.Batch(m.loadingStatus(), m.waitForStateChange)
+		}
+		return m, tea.Batch(m.openStartTarget(), m.notifyMilestones(), m.advanceTour(), m.waitForStateChange)
+	case cacheSyncedMsg:
+		// the start target, milestones and tour wait for the full state
+		m.synced = true
+		m.historyDirty = true
+		m.recordHistory()
+		m.recompute()
+		m.clampSelection()
+		return m, tea.Batch(m.loadingStatus(), m.openStartTarget(), m.notifyMilestones(), m.advanceTour())
 	}
 	return m, nil
 }
 
-func (m *Model) moveCursor(key tea.KeyMsg) int {
-	totalObjects := len(m.nodeInformer.GetStore().ListKeys())
-	perRow := m.GetBoxesPerRow(canvasStyle, nodeStyle)
+// moveSelection moves the cursor of the active grid view
+func (m *Model) moveSelection(key tea.KeyMsg) {
+	switch m.view {
+	case viewPending:
+		m.selectedPending = moveListCursor(key.String(), m.selectedPending, len(m.getPendingPods()), m.wrap)
+	case viewServices:
+		m.selectedService = moveListCursor(key.String(), m.selectedService, len(m.getServices()), m.wrap)
+	case viewTraffic:
+		m.selectedRoute = moveListCursor(key.String(), m.selectedRoute, len(m.getRoutes()), m.wrap)
+	case viewTasks:
+		m.selectedTask = moveListCursor(key.String(), m.selectedTask, len(m.tasks), m.wrap)
+	case viewImages:
+		m.selectedImage = moveListCursor(key.String(), m.selectedImage, len(m.getImages()), m.wrap)
+	case viewNamespaces:
+		m.selectedNamespace = moveCursor(key, m.selectedNamespace, len(m.getNamespaces()), m.GetBoxesPerRow(canvasStyle, m.namespaceBoxStyle()), m.wrap)
+	case viewNodes:
+		if len(m.getNodes()) == 0 {
+			return
+		}
+		if m.focused() == focusContainer {
+			m.selectedContainer = moveListCursor(key.String(), m.selectedContainer, len(focusedContainers(m.shownPods(m.getNodes()[m.selectedNode])[m.selectedPod])), m.wrap)
+			return
+		}
+		if m.selectingPods() {
+			m.selectedPod = moveCursor(key, m.selectedPod, len(m.shownPods(m.getNodes()[m.selectedNode])), m.GetBoxesPerRow(m.nodeStyleOf(m.getNodes()[m.selectedNode], m.nodeBoxStyle(), true), m.podBoxStyle(m.namedPods(m.getNodes()[m.selectedNode], true))), m.wrap)
+			return
+		}
+		m.selectedNode = moveInRows(key, m.selectedNode, m.nodeLayout(), m.wrap)
+	}
+}
+
+// nodeLayout returns the node indexes of each row of the grid as last rendered, which wraps
+// rows by width, starts each pool on a new row and gives an expanded node a row of its own.
+// Until the grid is rendered with the current nodes, rows are assumed to be uniform.
+func (m *Model) nodeLayout() [][]int {
+	total := len(m.getNodes())
+	if rows := m.nodeRows; len(rows) > 0 {
+		last := rows[len(rows)-1]
+		if len(last) > 0 && last[len(last)-1] == total-1 {
+			return rows
+		}
+	}
+	return lo.Chunk(lo.Range(total), lo.Max([]int{m.GetBoxesPerRow(canvasStyle, m.nodeBoxStyle()), 1}))
+}
+
+// moveInRows returns the new cursor position in a grid laid out in the given rows of box
+// indexes. Like moveCursor, left and right stay on the row, up and down keep the column
+// as far as the next row is long, and moving past an edge continues on the opposite side
+// if wrap is set and stops at the edge otherwise.
+func moveInRows(key tea.KeyMsg, cursor int, rows [][]int, wrap bool) int {
+	row, col := -1, -1
+	for r := range rows {
+		for c, index := range rows[r] {
+			if index == cursor {
+				row, col = r, c
+			}
+		}
+	}
+	if row < 0 {
+		return cursor
+	}
+	boxes := rows[row]
+	vertical := func(next int) int {
+		if next < 0 || next >= len(rows) {
+			if !wrap {
+				return cursor
+			}
+			next = mod(next, len(rows))
+		}
+		return rows[next][lo.Min([]int{col, len(rows[next]) - 1})]
+	}
+	switch key.String() {
+	case "home":
+		return rows[0][0]
+	case "end":
+		last := rows[len(rows)-1]
+		return last[len(last)-1]
+	case "ctrl+left":
+		return boxes[0]
+	case "ctrl+right":
+		return boxes[len(boxes)-1]
+	case "right":
+		if col+1 < len(boxes) {
+			return boxes[col+1]
+		}
+		if wrap {
+			return boxes[0]
+		}
+	case "left":
+		if col > 0 {
+			return boxes[col-1]
+		}
+		if wrap {
+			return boxes[len(boxes)-1]
+		}
+	case "up":
+		return vertical(row - 1)
+	case "down":
+		return vertical(row + 1)
+	}
+	return cursor
+}
+
+// moveCursor returns the new cursor position in a grid of totalObjects boxes laid out perRow to a row.
+// Moving past an edge continues on the opposite side if wrap is set and stops at the edge otherwise.
+func moveCursor(key tea.KeyMsg, cursor int, totalObjects int, perRow int, wrap bool) int {
+	if totalObjects == 0 {
+		return 0
+	}
+	rowStart := cursor - cursor%perRow
+	switch key.String() {
+	case "home":
+		return 0
+	case "end":
+		return totalObjects - 1
+	case "ctrl+left":
+		return rowStart
+	case "ctrl+right":
+		return lo.Min([]int{rowStart + perRow, totalObjects}) - 1
+	}
+	if !wrap {
+		switch key.String() {
+		case "right":
+			if cursor%perRow == perRow-1 || cursor+1 >= totalObjects {
+				return cursor
+			}
+		case "left":
+			if cursor%perRow == 0 {
+				return cursor
+			}
+		case "up":
+			if cursor < perRow {
+				return cursor
+			}
+		case "down":
+			if cursor+perRow >= totalObjects {
+				return cursor
+			}
+		}
+	}
 	switch key.String() {
 	case "right":
-		rowNum := m.selectedNode / perRow
-		index := m.selectedNode + 1
+		rowNum := cursor / perRow
+		index := cursor + 1
 		if index >= totalObjects {
 			return index - index%perRow
 		}
 		return rowNum*perRow + index%perRow
 	case "left":
-		rowNum := m.selectedNode / perRow
-		index := rowNum*perRow + mod((m.selectedNode-1), perRow)
+		rowNum := cursor / perRow
+		index := rowNum*perRow + mod((cursor-1), perRow)
 		if index >= totalObjects {
 			return totalObjects - 1
 		}
 		return index
 	case "up":
-		index := m.selectedNode - perRow
+		index := cursor - perRow
 		col := mod(index, perRow)
 		bottomRow := totalObjects / perRow
 		if index < 0 {
@@ -207,7 +945,7 @@ func (m *Model) moveCursor(key tea.KeyMsg) int {
 		}
 		return index
 	case "down":
-		index := m.selectedNode + perRow
+		index := cursor + perRow
 		if index >= totalObjects {
 			return index % perRow
 		}
@@ -216,112 +954,415 @@ func (m *Model) moveCursor(key tea.KeyMsg) int {
 	return 0
 }
 
+// recompute refreshes state derived from the informer caches after a change
+func (m *Model) recompute() {
+	m.preemptions = m.preemptionVictims()
+	m.nominated = m.nominatedPods()
+	m.trackReschedule()
+	m.trackServiceHighlight()
+	m.trackNetworkPolicy()
+	m.certs = m.getCertificates()
+	m.interrupted = m.interruptions()
+	m.scheduledLatency, m.readyLatency = m.schedulingLatencies()
+	m.recordProvisioning()
+	if m.upgradeMode {
+		m.versions = m.kubeletVersions()
+	}
+	if m.showPacking {
+		m.packing = m.packingOf()
+	}
+	if m.consolidation != nil {
+		m.consolidation = m.consolidationCandidates()
+	}
+}
+
+// clampSelection keeps the cursors on existing objects when the rendered sets shrink
+func (m *Model) clampSelection() {
+	if total := len(m.getNodes()); m.selectedNode >= total {
+		m.selectedNode = lo.Max([]int{total - 1, 0})
+	}
+	if total := len(m.getNodes()); total == 0 {
+		m.focusStack = []focusLevel{focusCluster}
+	} else if pods := len(m.shownPods(m.getNodes()[m.selectedNode])); m.selectedPod >= pods {
+		m.selectedPod = lo.Max([]int{pods - 1, 0})
+	}
+	if total := len(m.getNamespaces()); m.selectedNamespace >= total {
+		m.selectedNamespace = lo.Max([]int{total - 1, 0})
+	}
+	if total := len(m.getRoutes()); m.selectedRoute >= total {
+		m.selectedRoute = lo.Max([]int{total - 1, 0})
+	}
+	if total := len(m.getServices()); m.selectedService >= total {
+		m.selectedService = lo.Max([]int{total - 1, 0})
+	}
+	if total := len(m.getPendingPods()); m.selectedPending >= total {
+		m.selectedPending = lo.Max([]int{total - 1, 0})
+	}
+	if total := len(m.tasks); m.selectedTask >= total {
+		m.selectedTask = lo.Max([]int{total - 1, 0})
+	}
+	if total := len(m.getImages()); m.selectedImage >= total {
+		m.selectedImage = lo.Max([]int{total - 1, 0})
+	}
+	if !m.hasSelection() {
+		m.unfocus(focusDetails)
+	} else if m.view == viewNodes && m.selectingPods() {
+		if containers := len(focusedContainers(m.shownPods(m.getNodes()[m.selectedNode])[m.selectedPod])); m.selectedContainer >= containers {
+			m.selectedContainer = lo.Max([]int{containers - 1, 0})
+		}
+	}
+}
+
 // mod perform the modulus calculation
 // in go, the % operator is the remainder rather than the modulus
 func mod(a, b int) int {
 	return (a%b + b) % b
 }
 
-func (m *Model) View() string {
-	physicalWidth, physicalHeight, _ := term.GetSize(int(os.Stdout.Fd()))
-	if m.details {
-		m.viewport.Height = physicalHeight
-		m.viewport.Width = physicalWidth
+func (m *Model) render() string {
+	_, span := tracer.Start(context.Background(), "render", trace.WithAttributes(attribute.Int("view", int(m.view))))
+	defer span.End()
+	defer m.perf.recordFrame(time.Now())
+	physicalWidth, physicalHeight := m.terminalSize()
+	if m.showingDetails() && !m.split {
+		return m.detailsPane(physicalWidth, physicalHeight)
+	}
+	gridWidth := physicalWidth
+	if m.splitActive() {
+		gridWidth = int(float64(physicalWidth) * m.splitRatio)
+	}
+	canvasStyle = canvasStyle.MaxWidth(gridWidth).Width(gridWidth)
+	canvas := m.canvas(physicalHeight - canvasStyle.GetVerticalPadding() - 1 - lipgloss.Height(m.help.View(m.keys)))
+	spaceToBottom := physicalHeight - strings.Count(canvas, "\n")
+	if spaceToBottom < 0 {
+		// views that do not scroll can be taller than the terminal
+		spaceToBottom = 0
+	}
+	body := canvasStyle.Render(canvas + strings.Repeat("\n", spaceToBottom))
+	if m.splitActive() {
+		body = m.layoutSplit(body, physicalWidth-gridWidth, lipgloss.Height(body))
+	}
+	return body + "\n" + m.statusLine() + "\n" + m.help.View(m.keys)
+}
 
-		out, err := yaml.Marshal(m.getNodes()[m.selectedNode].Spec)
-		if err == nil {
-			m.viewport.SetContent(string(out))
-		}
-		if err != nil {
-			panic(err)
+// canvas renders the active view for the grid area, scrolling the node grid and the
+// tables to fit height lines
+func (m *Model) canvas(height int) string {
+	var canvas strings.Builder
+	if banner := m.essentialsBanner(); banner != "" {
+		canvas.WriteString(banner + "\n")
+	}
+	if overlay := m.tourOverlay(canvasStyle.GetWidth()); overlay != "" {
+		canvas.WriteString(overlay + "\n")
+	}
+	if hud := m.hud(); hud != "" {
+		canvas.WriteString(hud + "\n")
+	}
+	height -= strings.Count(canvas.String(), "\n")
+	switch m.view {
+	case viewAudit:
+		canvas.WriteString(m.auditPanel())
+	case viewTopology:
+		canvas.WriteString(m.topologySpread())
+	case viewServices:
+		canvas.WriteString(m.services())
+	case viewTraffic:
+		canvas.WriteString(m.traffic())
+	case viewCertificates:
+		canvas.WriteString(m.certificates())
+	case viewControlPlane:
+		canvas.WriteString(m.controlPlane())
+	case viewChurn:
+		canvas.WriteString(m.churnPanel())
+	case viewTasks:
+		canvas.WriteString(m.tasksPanel())
+	case viewFragmentation:
+		canvas.WriteString(m.fragmentation())
+	case viewDashboard:
+		canvas.WriteString(m.dashboard())
+	case viewCompare:
+		canvas.WriteString(m.comparison())
+	case viewPending:
+		canvas.WriteString(m.scrollTable(m.pending(), m.selectedPending, height))
+	case viewImages:
+		canvas.WriteString(m.scrollTable(m.images(), m.selectedImage, height))
+	case viewNamespaces:
+		canvas.WriteString(m.namespaces(height))
+	default:
+		canvas.WriteString(m.nodes(height))
+	}
+	return canvas.String()
+}
+
+// hasSelection returns true if the active view has an object under the cursor
+func (m *Model) hasSelection() bool {
+	switch m.view {
+	case viewPending:
+		return len(m.getPendingPods()) > 0
+	case viewImages:
+		return len(m.getImages()) > 0
+	case viewNamespaces:
+		return len(m.getNamespaces()) > 0
+	case viewNodes:
+		if m.selectingPods() {
+			return len(m.getNodes()) > 0 && len(m.shownPods(m.getNodes()[m.selectedNode])) > 0
 		}
-		return m.viewport.View()
+		return len(m.getNodes()) > 0
+	}
+	return false
+}
+
+// detailContent renders the details of the selected object in the active view
+func (m *Model) detailContent() string {
+	switch m.view {
+	case viewPending:
+		return m.schedulingExplanation(m.getPendingPods()[m.selectedPending])
+	case viewImages:
+		return imageDetails(m.getImages()[m.selectedImage])
+	case viewNamespaces:
+		return m.namespaceDetails(m.getNamespaces()[m.selectedNamespace].Name)
+	default:
+		return m.tabContent()
 	}
-	canvasStyle = canvasStyle.MaxWidth(physicalWidth).Width(physicalWidth)
-	var canvas strings.Builder
-	canvas.WriteString(m.nodes())
-	spaceToBottom := physicalHeight - strings.Count(canvas.String(), "\n")
-	return canvasStyle.Render(canvas.String()+strings.Repeat("\n", spaceToBottom)) + "\n" + m.help.View(keyMappings)
+}
+
+// loadDetails starts any background lookups the details of the selection need
+func (m *Model) loadDetails() tea.Cmd {
+	switch m.view {
+	case viewPending:
+		return m.fetchPodEvents(m.getPendingPods()[m.selectedPending])
+	case viewNodes:
+		return m.loadTab()
+	}
+	return nil
 }
 
 func (m *Model) GetBoxesPerRow(container lipgloss.Style, subContainer lipgloss.Style) int {
 	boxSize := subContainer.GetWidth() + subContainer.GetHorizontalMargins() + subContainer.GetHorizontalBorderSize()
-	return int(float64(container.GetWidth()-container.GetHorizontalPadding()) / float64(boxSize))
+	return lo.Max([]int{int(float64(container.GetWidth()-container.GetHorizontalPadding()) / float64(boxSize)), 1})
 }
 
-func (m *Model) nodes() string {
-	var boxRows [][]string
-	row := -1
-	perRow := m.GetBoxesPerRow(canvasStyle, nodeStyle)
-	for i, node := range m.getNodes() {
-		color := nodeStyle.GetBorderBottomBackground()
-		if i == m.selectedNode {
-			color = selectedNodeBorder
+// nodes renders the node grid scrolled to fit height lines, the pool heading of the top
+// row pinned when the nodes are grouped by pool. Only the rows in sight are rendered.
+func (m *Model) nodes(height int) string {
+	boxStyle := m.nodeBoxStyle()
+	nodes := m.getNodes()
+	grid := m.nodeGrid(nodes, boxStyle)
+	// layout holds the node indexes of each row that is not a heading, for moving between them
+	var layout [][]int
+	// headings are the pool heading row of each row, selectedRow the row of the selected node
+	headings := make([]int, len(grid))
+	heading, selectedRow := -1, -1
+	for r, row := range grid {
+		if row.heading {
+			heading = r
+		} else {
+			layout = append(layout, row.nodes)
+			if lo.Contains(row.nodes, m.selectedNode) {
+				selectedRow = r
+			}
 		}
-		box := nodeStyle.Copy().BorderBackground(color).Render(
-			lipgloss.JoinVertical(lipgloss.Left,
-				node.Name,
-				m.pods(node, nodeStyle),
-			),
-		)
-		if i%int(perRow) == 0 {
-			row++
-			boxRows = append(boxRows, []string{})
+		headings[r] = heading
+	}
+	m.nodeRows = layout
+	return m.scrollRenderedRows(len(grid), func(r int) string {
+		row := grid[r]
+		if row.heading {
+			return m.poolHeader(row.pool, row.provider, nodes)
+		}
+		boxes := lo.Map(row.nodes, func(i int, _ int) string { return m.nodeBox(nodes[i], i, boxStyle) })
+		return lipgloss.JoinHorizontal(lipgloss.Top, boxes...)
+	}, headings, selectedRow, height)
+}
+
+// nodeGridRow is a row of the node grid, either the heading of a pool or the indexes of nodes
+type nodeGridRow struct {
+	heading        bool
+	pool, provider string
+	nodes          []int
+}
+
+// nodeGrid lays the nodes out in rows from the widths of their box styles, without rendering
+// them. Rows wrap by width, as zoomed, shrunk and expanded boxes differ in size, each pool
+// starts on a new row under its heading when grouped by pool and an expanded node takes a
+// row of its own.
+func (m *Model) nodeGrid(nodes []*corev1.Node, boxStyle lipgloss.Style) []nodeGridRow {
+	var grid []nodeGridRow
+	rowWidth, maxRowWidth := 0, canvasStyle.GetWidth()-canvasStyle.GetHorizontalPadding()
+	lastPool := ""
+	for i, node := range nodes {
+		if pool, provider := nodePool(node); m.groupByPool && (i == 0 || pool != lastPool) {
+			grid = append(grid, nodeGridRow{heading: true, pool: pool, provider: provider})
+			rowWidth = 0
+			lastPool = pool
+		}
+		style := m.nodeStyleOf(node, boxStyle, i == m.selectedNode)
+		boxWidth := style.GetWidth() + style.GetHorizontalMargins() + style.GetHorizontalBorderSize()
+		expanded := node.Name == m.expandedNode && !m.zoomed
+		if rowWidth == 0 || rowWidth+boxWidth > maxRowWidth || expanded {
+			grid = append(grid, nodeGridRow{})
+			rowWidth = 0
+		}
+		grid[len(grid)-1].nodes = append(grid[len(grid)-1].nodes, i)
+		rowWidth += boxWidth
+		if expanded {
+			rowWidth = maxRowWidth
 		}
-		boxRows[row] = append(boxRows[row], box)
 	}
-	rows := lo.Map(boxRows, func(row []string, _ int) string {
+	return grid
+}
+
+// nodeBox renders the box of the node at index i of the grid
+func (m *Model) nodeBox(node *corev1.Node, i int, boxStyle lipgloss.Style) string {
+	color := boxStyle.GetBorderBottomBackground()
+	selectedPod := -1
+	if m.whatIf != nil && m.whatIf.placed[node.Name] > 0 {
+		color = whatIfBorder
+	}
+	color = m.upgradeBorder(node.Status.NodeInfo.KubeletVersion, color)
+	color = m.consolidationBorderOf(node.Name, color)
+	color = m.interruptionBorder(node.Name, color)
+	color = m.simulationBorder(node.Name, color)
+	if m.landedOn(node.Name) {
+		color = rescheduleBorder
+	}
+	podsStyle := m.nodeStyleOf(node, boxStyle, i == m.selectedNode)
+	style := podsStyle.Copy()
+	if i == m.selectedNode {
+		color = selectedNodeBorder
+		style = m.selectedStyle(style)
+		if m.selectingPods() {
+			selectedPod = m.selectedPod
+		}
+	}
+	var lines []string
+	if m.zoomed && i != m.selectedNode {
+		lines = m.shrunkNode(node, podsStyle)
+	} else {
+		lines = append(m.fitNames(m.nodeHeader(node), podsStyle), m.nodeBadges(node)...)
+		named := m.namedPods(node, i == m.selectedNode)
+		pods, hidden := m.visiblePods(node, podsStyle, len(lines), i == m.selectedNode, named)
+		lines = append(lines, m.pods(pods, podsStyle, named, selectedPod))
+		if hidden > 0 {
+			lines = append(lines, fmt.Sprintf("+%d more", hidden))
+		}
+	}
+	return style.BorderBackground(color).Render(lipgloss.JoinVertical(lipgloss.Left, lines...))
+}
+
+// nodeBadges returns the extra status lines rendered under the node name
+func (m *Model) nodeBadges(node *corev1.Node) []string {
+	var badges []string
+	if m.selectedNodes[node.Name] {
+		badges = append(badges, "✔ selected")
+	}
+	if selected := lo.CountBy(m.getPods(node), func(p *corev1.Pod) bool { return m.selectedPods[p.UID] }); selected > 0 {
+		badges = append(badges, fmt.Sprintf("✔ %d pods selected", selected))
+	}
+	if m.whatIf != nil && m.whatIf.placed[node.Name] > 0 {
+		badges = append(badges, fmt.Sprintf("+%d what-if pods", m.whatIf.placed[node.Name]))
+	}
+	badges = append(badges, m.simulationBadges(node.Name)...)
+	if m.upgradeMode {
+		badges = append(badges, "kubelet "+node.Status.NodeInfo.KubeletVersion)
+	}
+	badges = append(badges, m.provisioningBadges(node.Name)...)
+	badges = append(badges, m.initializingBadges(node)...)
+	badges = append(badges, m.stuckTerminatingBadges(node)...)
+	badges = append(badges, m.interruptionBadges(node.Name)...)
+	badges = append(badges, m.preemptionBadges(node.Name)...)
+	if m.landedOn(node.Name) {
+		badges = append(badges, "↳ "+m.rescheduling.replacement.Name)
+	}
+	badges = append(badges, m.serviceBadges(node)...)
+	if m.diagnostics != nil {
+		badges = append(badges, m.diagnostics.gaps[node.Name]...)
+	}
+	badges = append(badges, m.certificateBadges(node.Name)...)
+	badges = append(badges, m.packingBadges(node.Name)...)
+	badges = append(badges, m.consolidationBadges(node.Name)...)
+	badges = append(badges, m.rebalanceBadges(node.Name)...)
+	return badges
+}
+
+// joinRows lays out each row of boxes top-aligned
+func joinRows(boxRows [][]string) []string {
+	return lo.Map(boxRows, func(row []string, _ int) string {
 		return lipgloss.JoinHorizontal(lipgloss.Top, row...)
 	})
-	return lipgloss.JoinVertical(lipgloss.Left, rows...)
 }
 
 func (m *Model) getNodes() []*corev1.Node {
-	nodes := m.nodeInformer.GetStore().List()
-	sort.SliceStable(nodes, func(i, j int) bool {
-		iCreated := nodes[i].(*corev1.Node).CreationTimestamp.Unix()
-		jCreated := nodes[j].(*corev1.Node).CreationTimestamp.Unix()
-		if iCreated == jCreated {
-			return string(nodes[i].(*corev1.Node).UID) < string(nodes[j].(*corev1.Node).UID)
-		}
-		return iCreated < jCreated
-	})
-	var typedNodes []*corev1.Node
-	for _, n := range nodes {
-		typedNodes = append(typedNodes, n.(*corev1.Node))
+	var nodes []*corev1.Node
+	for _, n := range m.listNodes() {
+		if m.nodeFilter.matches(n, m.taintKey) {
+			nodes = append(nodes, n)
+		}
+	}
+	if m.groupByPool {
+		sortByPool(nodes)
+	}
+	return nodes
+}
+
+// getPods returns the rendered pods bound to the node, oldest first
+func (m *Model) getPods(node *corev1.Node) []*corev1.Pod {
+	return append([]*corev1.Pod(nil), m.clusterState().PodsOn(node.Name)...)
+}
+
+// podBorderColor picks the border color of a pod box from the pod's state
+func (m *Model) podBorderColor(pod *corev1.Pod, color lipgloss.TerminalColor) lipgloss.TerminalColor {
+	for _, o := range pod.OwnerReferences {
+		if o.Kind == "DaemonSet" {
+			// color = yellow
+		}
+	}
+	if m.isPreemptionVictim(pod) {
+		color = preemptionVictimBorder
+	}
+	color = m.rightsizingBorder(pod, color)
+	color = m.networkPolicyBorder(pod, color)
+	switch m.endpointState(pod) {
+	case endpointServing:
+		color = servingBorder
+	case endpointNotReady:
+		color = notServingBorder
+	}
+	if m.isReplacement(pod) {
+		color = rescheduleBorder
 	}
-	return typedNodes
+	if _, stuck := m.stuckTerminating(pod); stuck {
+		color = stuckTerminatingBorder
+	}
+	return color
 }
 
-func (m *Model) pods(node *corev1.Node, nodeStyle lipgloss.Style) string {
+// pods renders the pod boxes of a node, by name if named, highlighting the pod at index selected (-1 for none)
+func (m *Model) pods(pods []*corev1.Pod, nodeStyle lipgloss.Style, named bool, selected int) string {
 	var boxRows [][]string
-	pods := lo.Filter(m.podInformer.GetStore().List(), func(obj interface{}, _ int) bool {
-		pod := obj.(*corev1.Pod)
-		return pod.Spec.NodeName == node.Name
-	})
+	podStyle := m.podBoxStyle(named)
 	perRow := m.GetBoxesPerRow(nodeStyle, podStyle)
-	sort.SliceStable(pods, func(i, j int) bool {
-		iCreated := pods[i].(*corev1.Pod).CreationTimestamp.Unix()
-		jCreated := pods[j].(*corev1.Pod).CreationTimestamp.Unix()
-		if iCreated == jCreated {
-			return string(pods[i].(*corev1.Pod).UID) < string(pods[j].(*corev1.Pod).UID)
-		}
-		return iCreated < jCreated
-	})
 	row := -1
-	for i, obj := range pods {
+	for i, pod := range pods {
 		color := podStyle.GetBorderBottomForeground()
 		if i%perRow == 0 {
 			boxRows = append(boxRows, []string{})
 			row++
 		}
-		pod := obj.(*corev1.Pod)
-		for _, o := range pod.OwnerReferences {
-			if o.Kind == "DaemonSet" {
-				// color = yellow
-			}
+		color = m.podColor(pod, color)
+		if m.selectedPods[pod.UID] {
+			color = comparedPodBorder
+		}
+		style := podStyle.Copy()
+		if i == selected {
+			color = selectedNodeBorder
+			style = m.selectedStyle(style)
 		}
-		boxRows[row] = append(boxRows[row], podStyle.Copy().BorderForeground(color).Render(""))
+		content := m.podContent(pod)
+		if named {
+			content = m.podLabel(pod)
+		}
+		boxRows[row] = append(boxRows[row], style.BorderForeground(color).Render(content))
 	}
 	rows := lo.Map(boxRows, func(row []string, _ int) string {
 		return lipgloss.JoinHorizontal(lipgloss.Bottom, row...)
@@ -329,10 +1370,200 @@ func (m *Model) pods(node *corev1.Node, nodeStyle lipgloss.Style) string {
 	return lipgloss.JoinVertical(lipgloss.Left, rows...)
 }
 
+// programName returns how the binary was invoked, as "kubectl demo" when run as a kubectl plugin
+func programName() string {
+	name := filepath.Base(os.Args[0])
+	if strings.HasPrefix(name, "kubectl-") {
+		return "kubectl " + strings.TrimPrefix(name, "kubectl-")
+	}
+	return name
+}
+
+// openLog opens a file for appending, creating it if needed
+func openLog(path string) *os.File {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		log.Fatalf("could not open %s: %v", path, err)
+	}
+	return f
+}
+
 func main() {
-	p := tea.NewProgram(New())
+	if len(os.Args) > 1 && os.Args[1] == "release-manifest" {
+		if err := runReleaseManifest(os.Args[2:]); err != nil {
+			fmt.Printf("release-manifest: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "cleanup" {
+		if err := runCleanup(os.Args[2:]); err != nil {
+			fmt.Printf("cleanup: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+	args := os.Args[1:]
+	if len(args) > 0 && args[0] == "sandbox" {
+		kubeconfig, rest, err := runSandbox(args[1:])
+		if err != nil {
+			fmt.Printf("sandbox: %v\n", err)
+			os.Exit(1)
+		}
+		args = append([]string{"--kubeconfig", kubeconfig}, rest...)
+	}
+	opts := Options{ConfigFlags: genericclioptions.NewConfigFlags(true)}
+	flags := pflag.NewFlagSet(programName(), pflag.ExitOnError)
+	opts.ConfigFlags.AddFlags(flags)
+	flags.StringVarP(&opts.LabelSelector, "selector", "l", "", "label selector restricting which pods are shown")
+	flags.DurationVar(&opts.RefreshInterval, "refresh", time.Second, "interval between periodic re-renders (0 disables)")
+	flags.DurationVar(&opts.HistoryWindow, "history", 5*time.Minute, "how far back state snapshots are kept for scrubbing")
+	flags.StringVar(&opts.TaintKey, "taint-key", "", "taint key matched by the tainted node filter (empty matches any taint)")
+	flags.StringSliceVar(&opts.ChaosNamespaces, "chaos-namespaces", nil, "namespaces the chaos menu may delete pods in (chaos is disabled when empty)")
+	flags.BoolVar(&opts.ConfirmActions, "confirm", false, "dry-run mutating actions server-side and ask before applying them")
+	flags.Float64Var(&opts.UnderuseRatio, "underuse-ratio", 0.2, "usage/requests ratio below which the rightsizing mode flags a pod as over-provisioned")
+	flags.Float64Var(&opts.OveruseRatio, "overuse-ratio", 1.2, "usage/requests ratio above which the rightsizing mode flags a pod as under-provisioned")
+	plain := flags.Bool("plain", false, "print a plain text summary whenever it changes instead of the interactive view, for screen readers and files")
+	locale := flags.String("locale", format.LocaleFromEnv(), "language numbers and relative times are formatted in, such as de or fr_FR.UTF-8 (defaults to LC_ALL, LC_NUMERIC or LANG)")
+	asciiBorders := flags.Bool("ascii-borders", false, "draw borders with plain ASCII for terminals or fonts without box drawing characters")
+	flags.StringVar(&opts.ColorLabel, "color-label", "app.kubernetes.io/name", "pod label whose values pods are colored by in the color by label mode")
+	flags.BoolVar(&opts.Wrap, "wrap", true, "move the cursor past the edge of a grid or list to the opposite side, instead of stopping at the edge")
+	flags.BoolVar(&opts.Monochrome, "monochrome", false, "render without color, showing every state as a symbol or label")
+	flags.Float32Var(&opts.QPS, "qps", 20, "maximum sustained queries per second to the API server")
+	flags.IntVar(&opts.Burst, "burst", 50, "maximum burst of queries to the API server above --qps")
+	flags.StringVar(&opts.ExportFile, "export", "", "file session metrics are written to on exit, as CSV if it ends in .csv and JSON otherwise")
+	otlpEndpoint := flags.String("otlp-endpoint", "", "host:port of an OTLP/HTTP collector to export traces of informer sync, rendering and actions to")
+	otlpInsecure := flags.Bool("otlp-insecure", false, "export traces over plain HTTP")
+	kubectlLog := flags.String("kubectl-log", "", "file the kubectl equivalent of every action is appended to")
+	auditLog := flags.String("audit-log", "", "file every mutating action and its result is appended to")
+	flags.StringVar(&opts.ConfigFile, "config", defaultConfigPath(), "file saved profiles and the node template are read from")
+	flags.StringVar(&opts.Profile, "profile", "", "saved profile to start in")
+	resume := flags.Bool("resume", true, "resume the view, filters and selection of the previous session on the same context, unless --restore or --profile is given")
+	restore := flags.String("restore", "", "state file exported with Y to open the same view, focus and cluster as its author")
+	flags.StringSliceVar(&opts.FromFiles, "from-file", nil, "kubectl get -o yaml or -o json dumps to show instead of a live cluster (- reads stdin)")
+	simulate := flags.StringSlice("simulate", nil, "pod or workload manifests to simulate scheduling onto the cluster with r, without creating them")
+	flags.StringVar(&opts.Session, "session", newSessionID(), "id the labels, taints and workloads added in this session are labeled with, removed with Q or the cleanup subcommand")
+	apiListen := flags.String("api-listen", "", "address such as localhost:9090 to serve the gRPC State API on, streaming the nodes and pods shown to companion tools")
+	alerts := flags.StringArray("alert", nil, "run without the TUI, reporting when a condition holds: node-not-ready[:duration], pending>N[:duration] or event=Reason (repeatable)")
+	flags.StringVar(&opts.NotifyWebhook, "notify-webhook", "", "URL milestones such as a completed scale-up or drain and all pods ready are posted to as JSON, in a format Slack incoming webhooks accept")
+	alertWebhook := flags.String("alert-webhook", "", "URL alerts are posted to as JSON in addition to stdout")
+	flags.BoolVar(&opts.Light, "light", false, "cache only the metadata, node and phase of pods, cutting memory and network on very large clusters at the cost of requests, usage and pod details")
+	tourFile := flags.String("tour", "", "workshop file of steps whose instructions are shown and advance once the cluster reaches the state each expects")
+	flags.StringVar(&opts.FocusNode, "focus-node", "", "node to open the details of on startup")
+	flags.StringVar(&opts.FocusWorkload, "focus-workload", "", "namespace/name of a workload to open the details of one of its pods on startup")
+	_ = flags.Parse(args)
+	if opts.FocusWorkload != "" {
+		if _, _, err := parseWorkloadTarget(opts.FocusWorkload); err != nil {
+			log.Fatalf("invalid --focus-workload: %v", err)
+		}
+	}
+	var rules []alertRule
+	for _, text := range *alerts {
+		rule, err := parseAlertRule(text)
+		if err != nil {
+			log.Fatalf("invalid --alert: %v", err)
+		}
+		rules = append(rules, rule)
+	}
+	if *tourFile != "" {
+		t, err := loadTour(*tourFile)
+		if err != nil {
+			log.Fatalf("could not load --tour: %v", err)
+		}
+		opts.Tour = t
+	}
+	if len(*simulate) > 0 {
+		pods, err := loadSimulatedPods(*simulate)
+		if err != nil {
+			log.Fatalf("could not load --simulate: %v", err)
+		}
+		opts.SimulatePods = pods
+	}
+	if *restore != "" {
+		state, err := loadState(*restore)
+		if err != nil {
+			log.Fatalf("could not restore state: %v", err)
+		}
+		restoreOptions(&opts, state)
+	} else if *resume && len(opts.FromFiles) == 0 && opts.Profile == "" {
+		if state := loadSessionState(opts); state != nil {
+			restoreOptions(&opts, state)
+		}
+	}
+	cfg, err := loadConfig(opts.ConfigFile)
+	if err != nil {
+		log.Fatalf("could not load config: %v", err)
+	}
+	opts.Profiles = cfg.Profiles
+	if opts.NodeTemplate, err = parseNodeTemplate(cfg.NodeTemplate); err != nil {
+		log.Fatalf("invalid nodeTemplate in %s: %v", opts.ConfigFile, err)
+	}
+	if _, ok := findProfile(opts.Profiles, opts.Profile); opts.Profile != "" && !ok {
+		log.Fatalf("no profile %q in %s", opts.Profile, opts.ConfigFile)
+	}
+	if *otlpEndpoint != "" {
+		stopTracing, err := setupTracing(*otlpEndpoint, *otlpInsecure)
+		if err != nil {
+			log.Fatalf("could not set up tracing: %v", err)
+		}
+		defer stopTracing()
+	}
+	if *kubectlLog != "" {
+		f := openLog(*kubectlLog)
+		defer f.Close()
+		opts.KubectlLog = f
+	}
+	if *auditLog != "" {
+		f := openLog(*auditLog)
+		defer f.Close()
+		opts.AuditLog = f
+	}
+	if opts.Monochrome {
+		lipgloss.SetColorProfile(termenv.Ascii)
+	}
+	if err := format.SetLocale(*locale); err != nil && flags.Changed("locale") {
+		log.Printf("--locale: %v", err)
+	}
+	if *asciiBorders {
+		useASCIIBorders()
+	}
+	silenceKlog()
+	model := New(opts)
+	if *apiListen != "" {
+		stopAPI, err := model.api.serve(*apiListen)
+		if err != nil {
+			log.Fatalf("could not serve the State API: %v", err)
+		}
+		defer stopAPI()
+	}
+	if len(rules) > 0 {
+		runAlerts(model, os.Stdout, rules, *alertWebhook, opts.RefreshInterval)
+		return
+	}
+	if *plain {
+		runPlain(model, os.Stdout, opts.RefreshInterval)
+		return
+	}
+	p := tea.NewProgram(model)
+	handleSignals(p)
+	handleInformerPanics(p)
 	if err := p.Start(); err != nil {
 		fmt.Printf("Alas, there's been an error: %v", err)
 		os.Exit(1)
 	}
+	// after a crash the state was saved when the error view opened, saving it again may panic again
+	if model.crash == nil {
+		if err := model.persistState(); err != nil {
+			fmt.Printf("could not save the view state: %v\n", err)
+		}
+	}
+	if opts.ExportFile != "" {
+		if err := model.writeExport(opts.ExportFile); err != nil {
+			fmt.Printf("could not export session metrics: %v\n", err)
+		}
+	}
+	if model.fatalErr != nil {
+		fmt.Printf("%s exited: %v\n", programName(), model.fatalErr)
+		os.Exit(1)
+	}
 }
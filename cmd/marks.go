@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// mark is a bookmarked node, and optionally a pod on it, like a vim mark
+type mark struct {
+	nodeName string
+	podUID   types.UID
+}
+
+// isMarkRegister returns true for the keys marks can be stored under
+func isMarkRegister(key string) bool {
+	return len(key) == 1 && ((key[0] >= 'a' && key[0] <= 'z') || (key[0] >= 'A' && key[0] <= 'Z'))
+}
+
+// updateMarkKey completes a pending "m<letter>" or "'<letter>" sequence
+func (m *Model) updateMarkKey(msg tea.KeyMsg) tea.Cmd {
+	pending := m.pendingKey
+	m.pendingKey = ""
+	register := msg.String()
+	if !isMarkRegister(register) {
+		return nil
+	}
+	if pending == "m" {
+		return m.setMark(register)
+	}
+	return m.jumpToMark(register)
+}
+
+// setMark bookmarks the selected node, and the selected pod when selecting pods
+func (m *Model) setMark(register string) tea.Cmd {
+	nodes := m.getNodes()
+	if m.view != viewNodes || len(nodes) == 0 {
+		return m.setStatus("marks can only be set in the node view")
+	}
+	node := nodes[m.selectedNode]
+	bookmark := mark{nodeName: node.Name}
+	if pods := m.shownPods(node); m.selectingPods() && len(pods) > 0 {
+		bookmark.podUID = pods[m.selectedPod].UID
+	}
+	m.marks[register] = bookmark
+	return m.setStatus(fmt.Sprintf("mark %s set on %s", register, node.Name))
+}
+
+// jumpToMark moves the cursor back to a bookmarked node or pod
+func (m *Model) jumpToMark(register string) tea.Cmd {
+	bookmark, ok := m.marks[register]
+	if !ok {
+		return m.setStatus(fmt.Sprintf("mark %s not set", register))
+	}
+	nodeFound, podFound := m.navigateTo(bookmark)
+	switch {
+	case !nodeFound:
+		return m.setStatus(fmt.Sprintf("mark %s: node %s is not shown", register, bookmark.nodeName))
+	case bookmark.podUID != "" && !podFound:
+		return m.setStatus(fmt.Sprintf("mark %s: pod is gone, jumped to its node", register))
+	}
+	return nil
+}
+
+// navigateTo moves the cursor to the node of the mark, and to its pod if it has one,
+// returning whether each was found
+func (m *Model) navigateTo(target mark) (nodeFound bool, podFound bool) {
+	for i, node := range m.getNodes() {
+		if node.Name != target.nodeName {
+			continue
+		}
+		m.view = viewNodes
+		m.focusStack = []focusLevel{focusCluster}
+		m.focus = paneGrid
+		m.selectedNode = i
+		if target.podUID == "" {
+			return true, false
+		}
+		for j, pod := range m.shownPods(node) {
+			if pod.UID == target.podUID {
+				m.pushFocus(focusNode)
+				m.selectedPod = j
+				return true, true
+			}
+		}
+		return true, false
+	}
+	return false, false
+}
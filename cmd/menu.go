@@ -0,0 +1,41 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// menuItem is a single choice in a menu, picked by pressing its key
+type menuItem struct {
+	key    string
+	label  string
+	choose func() tea.Cmd
+}
+
+// menu is a one-line list of choices shown in place of the status line
+type menu struct {
+	title string
+	items []menuItem
+}
+
+// updateMenu picks the item matching the pressed key; any other key closes the menu
+func (m *Model) updateMenu(msg tea.KeyMsg) tea.Cmd {
+	current := m.menu
+	m.menu = nil
+	for _, item := range current.items {
+		if item.key == msg.String() {
+			return item.choose()
+		}
+	}
+	return nil
+}
+
+func (mu *menu) View() string {
+	choices := make([]string, 0, len(mu.items))
+	for _, item := range mu.items {
+		choices = append(choices, fmt.Sprintf("[%s] %s", item.key, item.label))
+	}
+	return fmt.Sprintf("%s: %s  [esc] cancel", mu.title, strings.Join(choices, "  "))
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/samber/lo"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// metricsInterval is how often pod usage is sampled from metrics-server
+const metricsInterval = 15 * time.Second
+
+// metricsSamples is how many samples are kept per pod, five minutes at metricsInterval
+const metricsSamples = 20
+
+// sparkBars are the cells of a usage sparkline, lowest to highest
+var sparkBars = []rune("▁▂▃▄▅▆▇█")
+
+// usageSample is a pod's summed container usage at one point in time
+type usageSample struct {
+	cpuMillis   int64
+	memoryBytes int64
+}
+
+// metricsMsg delivers one round of pod usage keyed by namespace/name
+type metricsMsg struct {
+	usage map[string]usageSample
+	err   error
+}
+
+// sampleMetrics fetches pod usage from metrics-server after delay
+func (m *Model) sampleMetrics(delay time.Duration) tea.Cmd {
+	if m.metricsClient == nil {
+		return nil
+	}
+	client := m.metricsClient
+	namespace := m.namespace
+	return tea.Tick(delay, func(time.Time) tea.Msg {
+		ctx, cancel := m.callContext(metricsInterval)
+		defer cancel()
+		usage := map[string]usageSample{}
+		err := retry(ctx, func(ctx context.Context) error {
+			list, err := client.MetricsV1beta1().PodMetricses(namespace).List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return err
+			}
+			for _, pm := range list.Items {
+				var sample usageSample
+				for _, c := range pm.Containers {
+					sample.cpuMillis += c.Usage.Cpu().MilliValue()
+					sample.memoryBytes += c.Usage.Memory().Value()
+				}
+				usage[pm.Namespace+"/"+pm.Name] = sample
+			}
+			return nil
+		})
+		return metricsMsg{usage: usage, err: err}
+	})
+}
+
+// recordMetrics appends a round of samples, dropping pods that went away, and
+// schedules the next round. Sampling stops if metrics-server is not installed.
+func (m *Model) recordMetrics(msg metricsMsg) tea.Cmd {
+	if apierrors.IsNotFound(msg.err) {
+		m.metricsClient = nil
+		return m.setStatus("metrics-server not found, usage sampling disabled")
+	}
+	if msg.err != nil {
+		return tea.Batch(m.setStatus(fmt.Sprintf("metrics: %v", msg.err)), m.sampleMetrics(metricsInterval))
+	}
+	for key := range m.podUsage {
+		if _, ok := msg.usage[key]; !ok {
+			delete(m.podUsage, key)
+		}
+	}
+	for key, sample := range msg.usage {
+		samples := append(m.podUsage[key], sample)
+		if len(samples) > metricsSamples {
+			samples = samples[len(samples)-metricsSamples:]
+		}
+		m.podUsage[key] = samples
+	}
+	return m.sampleMetrics(metricsInterval)
+}
+
+// usageSparkline scales the values to the highest one, one cell per sample
+func usageSparkline(values []int64) string {
+	highest := lo.Max(values)
+	var line strings.Builder
+	for _, v := range values {
+		i := 0
+		if highest > 0 {
+			i = int(v * int64(len(sparkBars)-1) / highest)
+		}
+		line.WriteRune(sparkBars[i])
+	}
+	return line.String()
+}
+
+// usageSummary renders CPU and memory sparklines of the pod's recent usage for pod details
+func (m *Model) usageSummary(p *corev1.Pod) string {
+	samples := m.podUsage[p.Namespace+"/"+p.Name]
+	if len(samples) == 0 {
+		return ""
+	}
+	cpu := lo.Map(samples, func(s usageSample, _ int) int64 { return s.cpuMillis })
+	memory := lo.Map(samples, func(s usageSample, _ int) int64 { return s.memoryBytes })
+	window := time.Duration(len(samples)) * metricsInterval
+	return fmt.Sprintf("Usage (last %s):\n  CPU    [%s] %dm, peak %dm\n  Memory [%s] %dMi, peak %dMi\n", window,
+		usageSparkline(cpu), cpu[len(cpu)-1], lo.Max(cpu),
+		usageSparkline(memory), memory[len(memory)-1]>>20, lo.Max(memory)>>20)
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/samber/lo"
+
+	"github.com/bwagner5/kube-demo/pkg/node"
+	"github.com/bwagner5/kube-demo/pkg/pod"
+)
+
+// milestone is a notable transition posted to the --notify-webhook. Text makes it
+// render in Slack incoming webhooks, the other fields are for automation.
+type milestone struct {
+	Text string `json:"text"`
+	// Milestone is scale-up, drain or all-ready
+	Milestone string    `json:"milestone"`
+	Context   string    `json:"context,omitempty"`
+	Time      time.Time `json:"time"`
+}
+
+// notifyResultMsg reports a failure to post milestones to the webhook
+type notifyResultMsg struct {
+	err error
+}
+
+// milestoneTracker remembers enough of the previous state to notice transitions
+type milestoneTracker struct {
+	started bool
+	// nodes is the node count after the last completed scale-up, or at startup
+	nodes int
+	// allReady is whether every pod was ready when last checked
+	allReady bool
+	// draining are the nodes drained in this session whose pods have not all left yet
+	draining map[string]bool
+}
+
+func newMilestoneTracker() *milestoneTracker {
+	return &milestoneTracker{draining: map[string]bool{}}
+}
+
+// milestones returns the transitions reached since the last state change. The first call
+// records the starting point, so what was true at startup is not announced. They are read
+// from the live state, so pausing or filtering the view does not hold them back.
+func (m *Model) milestones() []milestone {
+	t := m.milestoneTracker
+	live := m.tracker.Current()
+	nodes := live.Nodes
+	allReady, pending, running := true, 0, 0
+	remaining := map[string]int{}
+	for _, p := range live.Pods {
+		if pod.IsTerminal(p) {
+			continue
+		}
+		running++
+		if !pod.IsReady(p) {
+			allReady = false
+		}
+		if p.Spec.NodeName == "" {
+			pending++
+		} else if drainable(p) {
+			remaining[p.Spec.NodeName]++
+		}
+	}
+	if !t.started {
+		t.started, t.nodes, t.allReady = true, len(nodes), allReady
+		return nil
+	}
+	var reached []milestone
+	if len(nodes) < t.nodes {
+		t.nodes = len(nodes)
+	}
+	if len(nodes) > t.nodes && lo.EveryBy(nodes, node.IsReady) && pending == 0 {
+		reached = append(reached, milestone{Milestone: "scale-up", Text: fmt.Sprintf("scale-up complete: %d nodes (+%d) ready and no pods pending", len(nodes), len(nodes)-t.nodes)})
+		t.nodes = len(nodes)
+	}
+	for name := range t.draining {
+		// a drained node that went away, as autoscalers remove them, has nothing left on it
+		if remaining[name] == 0 {
+			reached = append(reached, milestone{Milestone: "drain", Text: fmt.Sprintf("drain of node %s complete", name)})
+			delete(t.draining, name)
+		}
+	}
+	if allReady && !t.allReady {
+		reached = append(reached, milestone{Milestone: "all-ready", Text: fmt.Sprintf("all %d pods are ready", running)})
+	}
+	t.allReady = allReady
+	return reached
+}
+
+// notifyMilestones posts the transitions reached to the --notify-webhook in the background
+func (m *Model) notifyMilestones() tea.Cmd {
+	if m.notifyWebhook == "" {
+		return nil
+	}
+	reached := m.milestones()
+	if len(reached) == 0 {
+		return nil
+	}
+	url, context := m.notifyWebhook, m.identity
+	return func() tea.Msg {
+		for _, payload := range reached {
+			payload.Context, payload.Time = context, time.Now()
+			if context != "" {
+				payload.Text = fmt.Sprintf("[%s] %s", context, payload.Text)
+			}
+			if err := postWebhook(url, payload); err != nil {
+				return notifyResultMsg{err: err}
+			}
+		}
+		return notifyResultMsg{}
+	}
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"github.com/charmbracelet/lipgloss"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// podMarker returns the symbol standing in for the pod's border color in monochrome
+// mode, following the same precedence as podBorderColor
+func (m *Model) podMarker(pod *corev1.Pod) string {
+	marker := ""
+	if m.isPreemptionVictim(pod) {
+		marker = "v"
+	}
+	marker = m.rightsizingMarker(pod, marker)
+	marker = m.networkPolicyMarker(pod, marker)
+	switch m.endpointState(pod) {
+	case endpointServing:
+		marker = "s"
+	case endpointNotReady:
+		marker = "n"
+	}
+	if m.isReplacement(pod) {
+		marker = "↻"
+	}
+	if _, stuck := m.stuckTerminating(pod); stuck {
+		marker = "†"
+	}
+	return marker
+}
+
+// podContent is the single character shown inside a pod box. In monochrome mode the
+// pod's state marker takes precedence over its privilege badge.
+func (m *Model) podContent(pod *corev1.Pod) string {
+	if m.monochrome {
+		if marker := m.podMarker(pod); marker != "" {
+			return marker
+		}
+	}
+	return privilegeBadge(pod)
+}
+
+// selectedStyle gives the selected box a double border in monochrome mode, where its color does not show
+func (m *Model) selectedStyle(style lipgloss.Style) lipgloss.Style {
+	if !m.monochrome {
+		return style
+	}
+	return style.BorderStyle(selectedBorder)
+}
+
+// legend names a color in status summaries, or the symbol replacing it in monochrome mode
+func (m *Model) legend(color string, symbol string) string {
+	if m.monochrome {
+		return symbol
+	}
+	return color
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/samber/lo"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/bwagner5/kube-demo/pkg/node"
+)
+
+// toggleSelection adds the pod under the cursor to the pods to compare while selecting pods,
+// and the node under the cursor to the nodes batch actions apply to otherwise, or removes it
+func (m *Model) toggleSelection() tea.Cmd {
+	nodes := m.getNodes()
+	if m.view != viewNodes || len(nodes) == 0 {
+		return nil
+	}
+	if m.selectingPods() {
+		return m.togglePodMark()
+	}
+	name := nodes[m.selectedNode].Name
+	if m.selectedNodes[name] {
+		delete(m.selectedNodes, name)
+	} else {
+		m.selectedNodes[name] = true
+	}
+	if len(m.batchNodes()) == 0 {
+		return m.setStatus("no nodes selected")
+	}
+	return m.setStatus(fmt.Sprintf("%d nodes selected: c cordons and L labels all of them, esc clears", len(m.batchNodes())))
+}
+
+// batchNodes returns the selected nodes that are still shown, in grid order
+func (m *Model) batchNodes() []*corev1.Node {
+	if len(m.selectedNodes) == 0 {
+		return nil
+	}
+	var selected []*corev1.Node
+	for _, n := range m.getNodes() {
+		if m.selectedNodes[n.Name] {
+			selected = append(selected, n)
+		}
+	}
+	return selected
+}
+
+// togglePodMark adds the pod under the cursor to the pods to compare, or removes it
+func (m *Model) togglePodMark() tea.Cmd {
+	p := m.selectedPodOrNil()
+	if p == nil {
+		return nil
+	}
+	if m.selectedPods[p.UID] {
+		delete(m.selectedPods, p.UID)
+	} else {
+		m.selectedPods[p.UID] = true
+	}
+	if len(m.comparedPods()) == 0 {
+		return m.setStatus("no pods selected")
+	}
+	return m.setStatus(fmt.Sprintf("%d pods selected: = compares two of them, esc clears", len(m.comparedPods())))
+}
+
+// comparedPods returns the selected pods that still exist, oldest first
+func (m *Model) comparedPods() []*corev1.Pod {
+	if len(m.selectedPods) == 0 {
+		return nil
+	}
+	var selected []*corev1.Pod
+	for _, p := range m.listPods() {
+		if m.selectedPods[p.UID] {
+			selected = append(selected, p)
+		}
+	}
+	return selected
+}
+
+// clearSelection empties the selected nodes and pods, reporting whether there were any
+func (m *Model) clearSelection() bool {
+	if len(m.selectedNodes) == 0 && len(m.selectedPods) == 0 {
+		return false
+	}
+	m.selectedNodes, m.selectedPods = map[string]bool{}, map[types.UID]bool{}
+	return true
+}
+
+// confirmBatch asks once before applying actions to several nodes. With --confirm the
+// dry-run prompt is that confirmation.
+func (m *Model) confirmBatch(summary string, actions []action) tea.Cmd {
+	if len(actions) == 0 {
+		return nil
+	}
+	if m.confirmActions {
+		return m.runAction(actions...)
+	}
+	m.menu = &menu{title: summary + "?", items: []menuItem{
+		{key: "y", label: "apply", choose: func() tea.Cmd { return m.runConfirmedAction(actions...) }},
+		{key: "n", label: "cancel", choose: func() tea.Cmd { return m.setStatus("cancelled " + summary) }},
+	}}
+	return nil
+}
+
+// batchCordon cordons the selected nodes, or uncordons them if all are cordoned already
+func (m *Model) batchCordon() tea.Cmd {
+	nodes := m.batchNodes()
+	cordon := !lo.EveryBy(nodes, node.IsCordoned)
+	verb := "cordon"
+	if !cordon {
+		verb = "uncordon"
+	}
+	var actions []action
+	for _, n := range nodes {
+		if node.IsCordoned(n) == cordon {
+			continue
+		}
+		a, undo := setUnschedulable(n.Name, cordon), setUnschedulable(n.Name, !cordon)
+		a.undo = &undo
+		actions = append(actions, a)
+	}
+	return m.confirmBatch(fmt.Sprintf("%s %d selected nodes", verb, len(actions)), actions)
+}
+
+// batchMetadataMenu asks whether to edit a label or an annotation on the selected nodes
+func (m *Model) batchMetadataMenu() tea.Cmd {
+	count := len(m.batchNodes())
+	m.menu = &menu{title: fmt.Sprintf("edit %d selected nodes", count), items: []menuItem{
+		{key: "l", label: "label", choose: func() tea.Cmd { return m.promptBatchMetadata("label") }},
+		{key: "a", label: "annotation", choose: func() tea.Cmd { return m.promptBatchMetadata("annotate") }},
+	}}
+	return nil
+}
+
+// promptBatchMetadata reads a kubectl style key=value to set or key- to remove on every selected node
+func (m *Model) promptBatchMetadata(verb string) tea.Cmd {
+	nodes := m.batchNodes()
+	return m.openPrompt(fmt.Sprintf("%s %d nodes", verb, len(nodes)), "key=value or key-", func(value string) tea.Cmd {
+		value = strings.TrimSpace(value)
+		var actions []action
+		for _, n := range nodes {
+			a, err := setNodeMetadata(n.Name, verb, value)
+			if err != nil {
+				return m.setStatus(fmt.Sprintf("%s %d nodes: %v", verb, len(nodes), err))
+			}
+			m.undoMetadata(&a, n.Name, verb, value)
+			if key, _, set := strings.Cut(value, "="); set && verb == "label" {
+				a = m.tracked(a, n.Name, key, "")
+			}
+			actions = append(actions, a)
+		}
+		return m.confirmBatch(fmt.Sprintf("%s %d selected nodes %s", verb, len(nodes), value), actions)
+	})
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// middleTruncate shortens s to at most width cells by cutting out its middle, so that both the
+// prefix and the generated suffix of names like ip-10-0-1-23.us-west-2.compute.internal stay readable
+func middleTruncate(s string, width int) string {
+	if lipgloss.Width(s) <= width || width < 1 {
+		return s
+	}
+	runes := []rune(s)
+	if width == 1 {
+		return "…"
+	}
+	head := (width - 1) / 2
+	tail := width - 1 - head
+	return string(runes[:head]) + "…" + string(runes[len(runes)-tail:])
+}
+
+// contentWidth is the width a box style leaves for its content
+func contentWidth(style lipgloss.Style) int {
+	return style.GetWidth() - style.GetHorizontalPadding()
+}
+
+// fitNames middle-truncates the names to the content width of the box style,
+// unless full names are shown
+func (m *Model) fitNames(names []string, style lipgloss.Style) []string {
+	if m.fullNames {
+		return names
+	}
+	fitted := make([]string, 0, len(names))
+	for _, name := range names {
+		fitted = append(fitted, middleTruncate(name, contentWidth(style)))
+	}
+	return fitted
+}
+
+// nameStyle widens the box style to fit the longest of the names when full names are shown
+func (m *Model) nameStyle(style lipgloss.Style, names []string) lipgloss.Style {
+	if !m.fullNames {
+		return style
+	}
+	longest := contentWidth(style)
+	for _, name := range names {
+		if w := lipgloss.Width(name); w > longest {
+			longest = w
+		}
+	}
+	return style.Copy().Width(longest + style.GetHorizontalPadding())
+}
+
+// toggleFullNames switches between middle-truncated names and full names in wider boxes
+func (m *Model) toggleFullNames() tea.Cmd {
+	m.fullNames = !m.fullNames
+	if m.fullNames {
+		return m.setStatus("showing full names")
+	}
+	return m.setStatus("truncating long names")
+}
+
+// nodeBoxStyle is the style of the node boxes, double width on terminals wide enough to show pods
+// by name and wide enough for the longest header line when full names are shown
+func (m *Model) nodeBoxStyle() lipgloss.Style {
+	style := nodeStyle
+	if m.widePods() {
+		style = nodeStyle.Copy().Width(nodeStyle.GetWidth() * 2)
+	}
+	if !m.fullNames {
+		return style
+	}
+	var names []string
+	for _, n := range m.getNodes() {
+		names = append(names, m.nodeHeader(n)...)
+	}
+	return m.nameStyle(style, names)
+}
+
+// namespaceBoxStyle is the style of the namespace boxes, wide enough for the longest name when full names are shown
+func (m *Model) namespaceBoxStyle() lipgloss.Style {
+	if !m.fullNames {
+		return namespaceStyle
+	}
+	var names []string
+	for _, namespace := range m.getNamespaces() {
+		names = append(names, namespace.Name)
+	}
+	return m.nameStyle(namespaceStyle, names)
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/charmbracelet/lipgloss"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/bwagner5/kube-demo/pkg/format"
+	"github.com/bwagner5/kube-demo/pkg/pod"
+)
+
+var namespaceStyle = nodeStyle.Copy().Height(8)
+
+// podPhases is the order phases are listed in a namespace box
+var podPhases = []corev1.PodPhase{corev1.PodRunning, corev1.PodPending, corev1.PodSucceeded, corev1.PodFailed, corev1.PodUnknown}
+
+// namespaceSummary aggregates the pods of a single namespace
+type namespaceSummary struct {
+	Name     string
+	Phases   map[corev1.PodPhase]int
+	Requests corev1.ResourceList
+}
+
+// getNamespaces summarizes the rendered pods by namespace, sorted by name
+func (m *Model) getNamespaces() []*namespaceSummary {
+	byName := map[string]*namespaceSummary{}
+	for _, p := range m.listPods() {
+		summary, ok := byName[p.Namespace]
+		if !ok {
+			summary = &namespaceSummary{Name: p.Namespace, Phases: map[corev1.PodPhase]int{}, Requests: corev1.ResourceList{}}
+			byName[p.Namespace] = summary
+		}
+		summary.Phases[p.Status.Phase]++
+		pod.AddResources(summary.Requests, pod.Requests(p))
+	}
+	namespaces := make([]*namespaceSummary, 0, len(byName))
+	for _, summary := range byName {
+		namespaces = append(namespaces, summary)
+	}
+	sort.Slice(namespaces, func(i, j int) bool { return namespaces[i].Name < namespaces[j].Name })
+	return namespaces
+}
+
+// namespaces renders the namespace grid scrolled to fit height lines
+func (m *Model) namespaces(height int) string {
+	var boxRows [][]string
+	row := -1
+	boxStyle := m.namespaceBoxStyle()
+	perRow := m.GetBoxesPerRow(canvasStyle, boxStyle)
+	for i, namespace := range m.getNamespaces() {
+		color := boxStyle.GetBorderBottomBackground()
+		if i == m.selectedNamespace {
+			color = selectedNodeBorder
+		}
+		lines := append(m.fitNames([]string{namespace.Name}, boxStyle), "")
+		for _, phase := range podPhases {
+			lines = append(lines, fmt.Sprintf("%-10s %d", phase, namespace.Phases[phase]))
+		}
+		lines = append(lines,
+			fmt.Sprintf("cpu req    %s", format.CPU(*namespace.Requests.Cpu())),
+			fmt.Sprintf("mem req    %s", format.Bytes(*namespace.Requests.Memory())),
+		)
+		if name, ratio := m.quotaPressure(namespace.Name); name != "" {
+			lines = append(lines, fmt.Sprintf("quota      %.0f%% %s", ratio*100, name))
+		}
+		box := boxStyle.Copy().BorderBackground(color).Render(lipgloss.JoinVertical(lipgloss.Left, lines...))
+		if i%perRow == 0 {
+			row++
+			boxRows = append(boxRows, []string{})
+		}
+		boxRows[row] = append(boxRows[row], box)
+	}
+	headings := make([]int, len(boxRows))
+	for i := range headings {
+		headings[i] = -1
+	}
+	return m.scrollRows(joinRows(boxRows), headings, m.selectedNamespace/perRow, height)
+}
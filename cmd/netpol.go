@@ -0,0 +1,123 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/lipgloss"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/bwagner5/kube-demo/pkg/netpol"
+)
+
+var ingressAllowedBorder = green
+var egressAllowedBorder = teal
+var isolatedBorder = red
+
+// reachability is whether the NetworkPolicies let a pod and the inspected pod connect
+type reachability struct {
+	// ingress is true if the pod can connect to the inspected pod
+	ingress bool
+	// egress is true if the inspected pod can connect to the pod
+	egress bool
+}
+
+// toggleNetworkPolicy starts or stops showing NetworkPolicy reachability for the selected pod
+func (m *Model) toggleNetworkPolicy() {
+	if m.netpolPod != "" {
+		m.netpolPod = ""
+		m.reachable = nil
+		return
+	}
+	if p := m.selectedPodOrNil(); p != nil {
+		m.netpolPod = p.UID
+		m.trackNetworkPolicy()
+	}
+}
+
+// trackNetworkPolicy evaluates the connections between the inspected pod and every visible pod
+func (m *Model) trackNetworkPolicy() {
+	if m.netpolPod == "" {
+		return
+	}
+	var target *corev1.Pod
+	var pods []*corev1.Pod
+	for _, p := range m.listPods() {
+		pods = append(pods, p)
+		if p.UID == m.netpolPod {
+			target = p
+		}
+	}
+	if target == nil {
+		m.netpolPod = ""
+		m.reachable = nil
+		return
+	}
+	var policies []*networkingv1.NetworkPolicy
+	for _, obj := range m.store.NetworkPolicies.List() {
+		policies = append(policies, obj.(*networkingv1.NetworkPolicy))
+	}
+	namespaces := netpol.Namespaces{}
+	for _, obj := range m.store.Namespaces.List() {
+		ns := obj.(*corev1.Namespace)
+		namespaces[ns.Name] = labels.Set(ns.Labels)
+	}
+	m.reachable = map[types.UID]reachability{}
+	for _, p := range pods {
+		if p.UID == target.UID {
+			continue
+		}
+		m.reachable[p.UID] = reachability{
+			ingress: netpol.Allowed(policies, namespaces, p, target),
+			egress:  netpol.Allowed(policies, namespaces, target, p),
+		}
+	}
+	m.netpolTarget = fmt.Sprintf("%s/%s", target.Namespace, target.Name)
+}
+
+// networkPolicyBorder colors pods by whether they can reach, or be reached by, the inspected pod
+func (m *Model) networkPolicyBorder(p *corev1.Pod, color lipgloss.TerminalColor) lipgloss.TerminalColor {
+	r, ok := m.reachable[p.UID]
+	switch {
+	case !ok:
+		return color
+	case r.ingress:
+		return ingressAllowedBorder
+	case r.egress:
+		return egressAllowedBorder
+	default:
+		return isolatedBorder
+	}
+}
+
+// networkPolicyMarker is the monochrome stand-in for networkPolicyBorder
+func (m *Model) networkPolicyMarker(p *corev1.Pod, marker string) string {
+	r, ok := m.reachable[p.UID]
+	switch {
+	case !ok:
+		return marker
+	case r.ingress:
+		return "i"
+	case r.egress:
+		return "e"
+	default:
+		return "x"
+	}
+}
+
+// networkPolicySummary counts the pods the inspected pod can talk to in each direction
+func (m *Model) networkPolicySummary() string {
+	in, out := 0, 0
+	for _, r := range m.reachable {
+		if r.ingress {
+			in++
+		}
+		if r.egress {
+			out++
+		}
+	}
+	return fmt.Sprintf("network policy of %s: %d of %d pods can connect in (%s), %d reachable out (%s), %s is isolated",
+		m.netpolTarget, in, len(m.reachable), m.legend("green", "i"), out, m.legend("teal", "e"), m.legend("red", "x"))
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/bwagner5/kube-demo/pkg/node"
+)
+
+func setUnschedulable(name string, unschedulable bool) action {
+	verb := "cordon"
+	if !unschedulable {
+		verb = "uncordon"
+	}
+	return action{
+		description: fmt.Sprintf("%s node %s", verb, name),
+		kubectl:     fmt.Sprintf("kubectl %s %s", verb, name),
+		requires:    []string{"patch nodes"},
+		run: func(ctx context.Context, client kubernetes.Interface, dryRun []string) error {
+			patch := fmt.Sprintf(`{"spec":{"unschedulable":%t}}`, unschedulable)
+			return retry(ctx, func(ctx context.Context) error {
+				_, err := client.CoreV1().Nodes().Patch(ctx, name, types.StrategicMergePatchType, []byte(patch), metav1.PatchOptions{DryRun: dryRun})
+				return err
+			})
+		},
+	}
+}
+
+// toggleCordon cordons the selected node, or uncordons it if already cordoned. With nodes
+// selected for batch actions it toggles them instead.
+func (m *Model) toggleCordon() tea.Cmd {
+	nodes := m.getNodes()
+	if len(nodes) == 0 {
+		return nil
+	}
+	if len(m.batchNodes()) > 0 {
+		return m.batchCordon()
+	}
+	n := nodes[m.selectedNode]
+	a, undo := setUnschedulable(n.Name, !node.IsCordoned(n)), setUnschedulable(n.Name, node.IsCordoned(n))
+	a.undo = &undo
+	return m.runAction(a)
+}
+
+// drainable returns true for pods kubectl drain would evict with --ignore-daemonsets:
+// everything except DaemonSet pods, mirror pods and finished pods
+func drainable(p *corev1.Pod) bool {
+	if _, mirror := p.Annotations[corev1.MirrorPodAnnotationKey]; mirror {
+		return false
+	}
+	if owner := metav1.GetControllerOf(p); owner != nil && owner.Kind == "DaemonSet" {
+		return false
+	}
+	return p.Status.Phase != corev1.PodSucceeded && p.Status.Phase != corev1.PodFailed
+}
+
+// drain cordons the selected node and evicts its pods through the eviction API so PodDisruptionBudgets apply
+func (m *Model) drain() tea.Cmd {
+	nodes := m.getNodes()
+	if len(nodes) == 0 {
+		return nil
+	}
+	n := nodes[m.selectedNode]
+	var evictions []*policyv1.Eviction
+	for _, p := range m.getPods(n) {
+		if drainable(p) {
+			evictions = append(evictions, &policyv1.Eviction{ObjectMeta: metav1.ObjectMeta{Name: p.Name, Namespace: p.Namespace}})
+		}
+	}
+	name := n.Name
+	cordon := setUnschedulable(name, true)
+	return m.runAction(action{
+		description: fmt.Sprintf("drain node %s (%d pods)", name, len(evictions)),
+		kubectl:     fmt.Sprintf("kubectl drain %s --ignore-daemonsets --delete-emptydir-data", name),
+		requires:    []string{"patch nodes", "create pods/eviction"},
+		run: func(ctx context.Context, client kubernetes.Interface, dryRun []string) error {
+			if err := cordon.run(ctx, client, dryRun); err != nil {
+				return err
+			}
+			for i, eviction := range evictions {
+				reportProgress(ctx, i, len(evictions))
+				eviction := eviction.DeepCopy()
+				eviction.DeleteOptions = &metav1.DeleteOptions{DryRun: dryRun}
+				err := retry(ctx, func(ctx context.Context) error {
+					return client.PolicyV1().Evictions(eviction.Namespace).Evict(ctx, eviction)
+				})
+				if err != nil {
+					return fmt.Errorf("evicting %s/%s: %w", eviction.Namespace, eviction.Name, err)
+				}
+			}
+			reportProgress(ctx, len(evictions), len(evictions))
+			return nil
+		},
+		onSuccess: func() { m.milestoneTracker.draining[name] = true },
+	})
+}
+
+// selectedNodeOrNil returns the node under the cursor, nil if the cursor is past the nodes
+func (m *Model) selectedNodeOrNil() *corev1.Node {
+	nodes := m.getNodes()
+	if m.selectedNode >= len(nodes) {
+		return nil
+	}
+	return nodes[m.selectedNode]
+}
+
+// selectedPodOrNil returns the pod under the cursor while selecting pods
+func (m *Model) selectedPodOrNil() *corev1.Pod {
+	n := m.selectedNodeOrNil()
+	if m.view != viewNodes || !m.selectingPods() || n == nil {
+		return nil
+	}
+	pods := m.shownPods(n)
+	if m.selectedPod >= len(pods) {
+		return nil
+	}
+	return pods[m.selectedPod]
+}
+
+// deleteSelectedPod deletes the pod under the cursor, offering a force delete if it is stuck terminating
+func (m *Model) deleteSelectedPod() tea.Cmd {
+	p := m.selectedPodOrNil()
+	if p == nil {
+		return m.setStatus("select a pod with tab first")
+	}
+	if reason, stuck := m.stuckTerminating(p); stuck {
+		return m.confirmForceDelete(p, reason)
+	}
+	return m.runAction(deletePod(p))
+}
+
+// promptScale asks for a replica count for the selected pod's Deployment or StatefulSet
+func (m *Model) promptScale() tea.Cmd {
+	p := m.selectedPodOrNil()
+	if p == nil {
+		return m.setStatus("select a pod with tab first")
+	}
+	workload := m.workloadOf(p)
+	if workload.Kind != "Deployment" && workload.Kind != "StatefulSet" {
+		return m.setStatus(fmt.Sprintf("cannot scale a %s", workload.Kind))
+	}
+	return m.openPrompt(fmt.Sprintf("scale %s replicas", workload), "3", func(value string) tea.Cmd {
+		replicas, err := strconv.Atoi(value)
+		if err != nil || replicas < 0 {
+			return m.setStatus(fmt.Sprintf("invalid replica count %q", value))
+		}
+		a := scaleWorkload(workload, int32(replicas))
+		a.undo = m.undoScale(workload)
+		return m.runAction(a)
+	})
+}
+
+func scaleWorkload(workload workloadRef, replicas int32) action {
+	return action{
+		description: fmt.Sprintf("scale %s to %d", workload, replicas),
+		kubectl:     fmt.Sprintf("kubectl scale %s %s -n %s --replicas=%d", strings.ToLower(workload.Kind), workload.Name, workload.Namespace, replicas),
+		requires:    []string{fmt.Sprintf("patch %ss/scale", strings.ToLower(workload.Kind))},
+		run: func(ctx context.Context, client kubernetes.Interface, dryRun []string) error {
+			patch := []byte(fmt.Sprintf(`{"spec":{"replicas":%d}}`, replicas))
+			return retry(ctx, func(ctx context.Context) (err error) {
+				switch workload.Kind {
+				case "Deployment":
+					_, err = client.AppsV1().Deployments(workload.Namespace).Patch(ctx, workload.Name, types.MergePatchType, patch, metav1.PatchOptions{DryRun: dryRun}, "scale")
+				case "StatefulSet":
+					_, err = client.AppsV1().StatefulSets(workload.Namespace).Patch(ctx, workload.Name, types.MergePatchType, patch, metav1.PatchOptions{DryRun: dryRun}, "scale")
+				default:
+					err = fmt.Errorf("cannot scale a %s", workload.Kind)
+				}
+				return err
+			})
+		},
+	}
+}
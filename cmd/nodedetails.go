@@ -0,0 +1,15 @@
+package main
+
+import (
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// nodeDetails renders the node's readiness history and the session's provisioning latency
+func (m *Model) nodeDetails(n *corev1.Node) string {
+	var out strings.Builder
+	out.WriteString(m.readiness.readinessSummary(n.Name))
+	out.WriteString(m.provisioningHistogram())
+	return out.String()
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/kubernetes"
+)
+
+// metadataMenu asks whether to edit a label or an annotation on the selected node, or on
+// the nodes selected for batch actions
+func (m *Model) metadataMenu() tea.Cmd {
+	nodes := m.getNodes()
+	if m.view != viewNodes || len(nodes) == 0 {
+		return nil
+	}
+	if len(m.batchNodes()) > 0 {
+		return m.batchMetadataMenu()
+	}
+	name := nodes[m.selectedNode].Name
+	m.menu = &menu{title: "edit node " + name, items: []menuItem{
+		{key: "l", label: "label", choose: func() tea.Cmd { return m.promptMetadata(name, "label") }},
+		{key: "a", label: "annotation", choose: func() tea.Cmd { return m.promptMetadata(name, "annotate") }},
+	}}
+	return nil
+}
+
+// promptMetadata reads a kubectl style key=value to set or key- to remove
+func (m *Model) promptMetadata(name string, verb string) tea.Cmd {
+	return m.openPrompt(fmt.Sprintf("%s node %s", verb, name), "key=value or key-", func(value string) tea.Cmd {
+		a, err := setNodeMetadata(name, verb, strings.TrimSpace(value))
+		if err != nil {
+			return m.setStatus(fmt.Sprintf("%s node %s: %v", verb, name, err))
+		}
+		m.undoMetadata(&a, name, verb, strings.TrimSpace(value))
+		if key, _, set := strings.Cut(strings.TrimSpace(value), "="); set && verb == "label" {
+			a = m.tracked(a, name, key, "")
+		}
+		return m.runAction(a)
+	})
+}
+
+// setNodeMetadata parses a kubectl label/annotate argument into an action
+// merge patching the node's labels or annotations
+func setNodeMetadata(name string, verb string, arg string) (action, error) {
+	field := "labels"
+	if verb == "annotate" {
+		field = "annotations"
+	}
+	var key string
+	var value *string
+	if k, v, set := strings.Cut(arg, "="); set {
+		key, value = k, &v
+	} else if strings.HasSuffix(arg, "-") {
+		key = strings.TrimSuffix(arg, "-")
+	} else {
+		return action{}, fmt.Errorf("expected key=value or key-, got %q", arg)
+	}
+	if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+		return action{}, fmt.Errorf("invalid key %q: %s", key, strings.Join(errs, "; "))
+	}
+	if value != nil && field == "labels" {
+		if errs := validation.IsValidLabelValue(*value); len(errs) > 0 {
+			return action{}, fmt.Errorf("invalid value %q: %s", *value, strings.Join(errs, "; "))
+		}
+	}
+	// a null value removes the key in a merge patch
+	patch, err := json.Marshal(map[string]interface{}{"metadata": map[string]interface{}{field: map[string]*string{key: value}}})
+	if err != nil {
+		return action{}, err
+	}
+	description := fmt.Sprintf("%s node %s %s", verb, name, arg)
+	kubectl := fmt.Sprintf("kubectl %s node %s %s", verb, name, arg)
+	if value != nil {
+		kubectl += " --overwrite"
+	}
+	return action{
+		description: description,
+		kubectl:     kubectl,
+		requires:    []string{"patch nodes"},
+		run: func(ctx context.Context, client kubernetes.Interface, dryRun []string) error {
+			return retry(ctx, func(ctx context.Context) error {
+				_, err := client.CoreV1().Nodes().Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{DryRun: dryRun})
+				return err
+			})
+		},
+	}, nil
+}
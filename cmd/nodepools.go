@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/bwagner5/kube-demo/pkg/node"
+)
+
+// nodePoolLabels are the well-known labels naming the group a node was provisioned by, in priority order
+var nodePoolLabels = []struct {
+	label    string
+	provider string
+}{
+	{"karpenter.sh/nodepool", "Karpenter"},
+	{"karpenter.sh/provisioner-name", "Karpenter"},
+	{"eks.amazonaws.com/nodegroup", "EKS"},
+	{"alpha.eksctl.io/nodegroup-name", "eksctl"},
+	{"cloud.google.com/gke-nodepool", "GKE"},
+	{"kubernetes.azure.com/agentpool", "AKS"},
+	{"agentpool", "AKS"},
+}
+
+// autoscalerStatusTimeout bounds fetching the cluster-autoscaler status ConfigMap
+const autoscalerStatusTimeout = 10 * time.Second
+
+// autoscalerGroupPattern matches a node group in the cluster-autoscaler status ConfigMap
+var autoscalerGroupPattern = regexp.MustCompile(`Name:\s+(\S+)[\s\S]*?cloudProviderTarget=(\d+) \(minSize=(\d+), maxSize=(\d+)\)`)
+
+// nodeGroupSize is the size of a node group as reported by cluster-autoscaler
+type nodeGroupSize struct {
+	desired, min, max int
+}
+
+// autoscalerStatusMsg delivers the node group sizes from cluster-autoscaler
+type autoscalerStatusMsg struct {
+	sizes map[string]nodeGroupSize
+}
+
+// nodePool returns the group and provisioner the node belongs to
+func nodePool(n *corev1.Node) (string, string) {
+	for _, l := range nodePoolLabels {
+		if value, ok := n.Labels[l.label]; ok {
+			return value, l.provider
+		}
+	}
+	return "", ""
+}
+
+// toggleNodePools groups the node grid by node pool, looking up group sizes in the background
+func (m *Model) toggleNodePools() tea.Cmd {
+	m.groupByPool = !m.groupByPool
+	m.clampSelection()
+	if !m.groupByPool || m.kubeClient == nil {
+		return nil
+	}
+	client := m.kubeClient
+	return func() tea.Msg {
+		ctx, cancel := m.callContext(autoscalerStatusTimeout)
+		defer cancel()
+		var status *corev1.ConfigMap
+		err := retry(ctx, func(ctx context.Context) (err error) {
+			status, err = client.CoreV1().ConfigMaps(metav1.NamespaceSystem).Get(ctx, "cluster-autoscaler-status", metav1.GetOptions{})
+			return err
+		})
+		if err != nil {
+			// without cluster-autoscaler the groups are shown without sizes
+			return autoscalerStatusMsg{}
+		}
+		return autoscalerStatusMsg{sizes: parseAutoscalerStatus(status.Data["status"])}
+	}
+}
+
+// parseAutoscalerStatus reads node group sizes from the cluster-autoscaler status text
+func parseAutoscalerStatus(status string) map[string]nodeGroupSize {
+	sizes := map[string]nodeGroupSize{}
+	for _, match := range autoscalerGroupPattern.FindAllStringSubmatch(status, -1) {
+		desired, _ := strconv.Atoi(match[2])
+		min, _ := strconv.Atoi(match[3])
+		max, _ := strconv.Atoi(match[4])
+		sizes[match[1]] = nodeGroupSize{desired: desired, min: min, max: max}
+	}
+	return sizes
+}
+
+// groupSize finds the autoscaler node group of a pool. Autoscaler groups are
+// named after the cloud resource, e.g. eks-<nodegroup>-<id> for EKS ASGs.
+func (m *Model) groupSize(pool string) (nodeGroupSize, bool) {
+	names := make([]string, 0, len(m.groupSizes))
+	for name := range m.groupSizes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if name == pool || strings.Contains(name, "-"+pool+"-") {
+			return m.groupSizes[name], true
+		}
+	}
+	return nodeGroupSize{}, false
+}
+
+// sortByPool orders nodes by pool, keeping the existing order within a pool
+func sortByPool(nodes []*corev1.Node) {
+	sort.SliceStable(nodes, func(i, j int) bool {
+		pi, _ := nodePool(nodes[i])
+		pj, _ := nodePool(nodes[j])
+		return pi < pj
+	})
+}
+
+// poolHeader renders the heading of a node pool section in the grid
+func (m *Model) poolHeader(pool string, provider string, nodes []*corev1.Node) string {
+	ready := 0
+	for _, n := range nodes {
+		if p, _ := nodePool(n); p == pool && node.IsReady(n) {
+			ready++
+		}
+	}
+	total := 0
+	for _, n := range nodes {
+		if p, _ := nodePool(n); p == pool {
+			total++
+		}
+	}
+	if pool == "" {
+		return fmt.Sprintf("ungrouped: %d/%d ready", ready, total)
+	}
+	header := fmt.Sprintf("%s (%s): %d/%d ready", pool, provider, ready, total)
+	if size, ok := m.groupSize(pool); ok {
+		header += fmt.Sprintf(", desired %d (min %d, max %d)", size.desired, size.min, size.max)
+	}
+	return header
+}
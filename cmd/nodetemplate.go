@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/bwagner5/kube-demo/pkg/binpack"
+	"github.com/bwagner5/kube-demo/pkg/format"
+)
+
+// nodeBarWidth is the width of the request bars a node template can show
+const nodeBarWidth = 10
+
+// nodeFields are the fields a node template can show, for example
+//
+//	{{.Name}}
+//	{{.InstanceType}} {{.Zone}}
+//	pods {{.Pods}}/{{.MaxPods}}
+//	cpu {{.CPUBar}}
+type nodeFields struct {
+	Name           string
+	InstanceType   string
+	Zone           string
+	Pool           string
+	KubeletVersion string
+	Pods           int
+	MaxPods        int64
+	// CPU and Memory are the requests of the node's pods, such as "1.5/4"
+	CPU    string
+	Memory string
+	// CPUBar and MemoryBar chart the share of allocatable the pods request
+	CPUBar    string
+	MemoryBar string
+	Labels    map[string]string
+}
+
+// parseNodeTemplate parses the node box template of the config, nil when it has none
+func parseNodeTemplate(text string) (*template.Template, error) {
+	if strings.TrimSpace(text) == "" {
+		return nil, nil
+	}
+	return template.New("nodeTemplate").Option("missingkey=zero").Parse(text)
+}
+
+// nodeFieldsOf collects the template fields of a node
+func (m *Model) nodeFieldsOf(n *corev1.Node) nodeFields {
+	pods := m.getPods(n)
+	free := binpack.NewBin(n, pods).Free
+	allocatable := n.Status.Allocatable
+	requestedCPU, requestedMemory := allocatable.Cpu().DeepCopy(), allocatable.Memory().DeepCopy()
+	requestedCPU.Sub(*free.Cpu())
+	requestedMemory.Sub(*free.Memory())
+	pool, _ := nodePool(n)
+	return nodeFields{
+		Name:           n.Name,
+		InstanceType:   n.Labels[corev1.LabelInstanceTypeStable],
+		Zone:           n.Labels[corev1.LabelTopologyZone],
+		Pool:           pool,
+		KubeletVersion: n.Status.NodeInfo.KubeletVersion,
+		Pods:           len(pods),
+		MaxPods:        allocatable.Pods().Value(),
+		CPU:            format.CPU(requestedCPU) + "/" + format.CPU(*allocatable.Cpu()),
+		Memory:         format.Bytes(requestedMemory) + "/" + format.Bytes(*allocatable.Memory()),
+		CPUBar:         requestBar(requestedCPU.MilliValue(), allocatable.Cpu().MilliValue()),
+		MemoryBar:      requestBar(requestedMemory.Value(), allocatable.Memory().Value()),
+		Labels:         n.Labels,
+	}
+}
+
+// requestBar draws the requested part of a node's allocatable capacity followed by its percentage
+func requestBar(requested int64, allocatable int64) string {
+	filled, percent := 0, int64(0)
+	if allocatable > 0 {
+		filled = int(requested * nodeBarWidth / allocatable)
+		percent = requested * 100 / allocatable
+	}
+	if filled > nodeBarWidth {
+		filled = nodeBarWidth
+	}
+	if filled < 0 {
+		filled = 0
+	}
+	return fmt.Sprintf("%s%s %d%%", strings.Repeat("█", filled), strings.Repeat("░", nodeBarWidth-filled), percent)
+}
+
+// nodeHeader returns the lines at the top of a node box, the node name unless the config has a node template.
+// Blank lines are dropped so conditional fields leave no gaps.
+func (m *Model) nodeHeader(n *corev1.Node) []string {
+	if m.nodeTemplate == nil {
+		return []string{n.Name}
+	}
+	var out strings.Builder
+	if err := m.nodeTemplate.Execute(&out, m.nodeFieldsOf(n)); err != nil {
+		return []string{n.Name, "template error: " + err.Error()}
+	}
+	var lines []string
+	for _, line := range strings.Split(out.String(), "\n") {
+		if line = strings.TrimRight(line, " \t"); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
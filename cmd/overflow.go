@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// podCapacity returns how many pod boxes, named or not, fit in a node box of the style under its header lines.
+// reserve is the lines kept free below the pods, for the overflow indicator.
+func (m *Model) podCapacity(style lipgloss.Style, headerLines int, reserve int, named bool) int {
+	podHeight := 1 + podStyle.GetVerticalBorderSize()
+	rows := (style.GetHeight() - style.GetVerticalPadding() - headerLines - reserve) / podHeight
+	if rows < 1 {
+		rows = 1
+	}
+	return rows * m.GetBoxesPerRow(style, m.podBoxStyle(named))
+}
+
+// visiblePods returns the pods of the node that fit in its box and how many more are hidden.
+// An expanded or zoomed node, or the node whose pods are being selected, shows all of them.
+func (m *Model) visiblePods(node *corev1.Node, style lipgloss.Style, headerLines int, selected bool, named bool) ([]*corev1.Pod, int) {
+	pods := m.shownPods(node)
+	if node.Name == m.expandedNode || (selected && (m.zoomed || m.selectingPods())) || len(pods) <= m.podCapacity(style, headerLines, 0, named) {
+		return pods, 0
+	}
+	capacity := m.podCapacity(style, headerLines, 1, named)
+	return pods[:capacity], len(pods) - capacity
+}
+
+// expandedStyle widens the node box style to the full width of the grid
+func expandedStyle(style lipgloss.Style) lipgloss.Style {
+	width := canvasStyle.GetWidth() - canvasStyle.GetHorizontalPadding() - style.GetHorizontalMargins() - style.GetHorizontalBorderSize()
+	if width <= style.GetWidth() {
+		return style
+	}
+	return style.Copy().Width(width)
+}
+
+// toggleExpandedNode shows all pods of the selected node in a full-width row, or collapses it again
+func (m *Model) toggleExpandedNode() tea.Cmd {
+	nodes := m.getNodes()
+	if len(nodes) == 0 {
+		return nil
+	}
+	name := nodes[m.selectedNode].Name
+	if m.expandedNode == name {
+		m.expandedNode = ""
+		return m.setStatus(fmt.Sprintf("collapsed %s", name))
+	}
+	m.expandedNode = name
+	return m.setStatus(fmt.Sprintf("expanded %s to show all its pods", name))
+}
+
+// nodeStyleOf is the box style of the node: zoomed if it is the selected one and shrunk
+// otherwise while zoomed in, and full width when it is expanded
+func (m *Model) nodeStyleOf(node *corev1.Node, style lipgloss.Style, selected bool) lipgloss.Style {
+	switch {
+	case m.zoomed && selected:
+		return zoomedStyle(style)
+	case m.zoomed:
+		return shrunkStyle(style)
+	case node.Name == m.expandedNode:
+		return expandedStyle(style)
+	}
+	return style
+}
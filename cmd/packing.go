@@ -0,0 +1,63 @@
+package main
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/bwagner5/kube-demo/pkg/binpack"
+	"github.com/bwagner5/kube-demo/pkg/format"
+)
+
+// packingOf computes the packing of every rendered node
+func (m *Model) packingOf() map[string]binpack.Packing {
+	packing := map[string]binpack.Packing{}
+	for _, n := range m.getNodes() {
+		packing[n.Name] = binpack.NewPacking(n, binpack.NewBin(n, m.getPods(n)))
+	}
+	return packing
+}
+
+// packingBadges shows the node's packing score while the packing overlay is on
+func (m *Model) packingBadges(nodeName string) []string {
+	p, ok := m.packing[nodeName]
+	if !m.showPacking || !ok {
+		return nil
+	}
+	badges := []string{fmt.Sprintf("packed %.0f%% (cpu %.0f%%, mem %.0f%%)", p.Score()*100, p.CPU*100, p.Memory*100)}
+	if !p.StrandedCPU.IsZero() || !p.StrandedMemory.IsZero() {
+		badges = append(badges, fmt.Sprintf("stranded cpu %s, mem %s", format.CPU(p.StrandedCPU), format.Bytes(p.StrandedMemory)))
+	}
+	return badges
+}
+
+// packingSummary renders the cluster wide packing score and stranded resources
+func (m *Model) packingSummary() string {
+	var cpu, memory float64
+	var strandedCPU, strandedMemory resource.Quantity
+	stranded := 0
+	for _, n := range m.getNodes() {
+		p := m.packing[n.Name]
+		// weight each node by its size so the cluster score is requested/allocatable overall
+		cpu += p.CPU * float64(n.Status.Allocatable.Cpu().MilliValue())
+		memory += p.Memory * float64(n.Status.Allocatable.Memory().Value())
+		strandedCPU.Add(p.StrandedCPU)
+		strandedMemory.Add(p.StrandedMemory)
+		if !p.StrandedCPU.IsZero() || !p.StrandedMemory.IsZero() {
+			stranded++
+		}
+	}
+	var totalCPU, totalMemory float64
+	for _, n := range m.getNodes() {
+		totalCPU += float64(n.Status.Allocatable.Cpu().MilliValue())
+		totalMemory += float64(n.Status.Allocatable.Memory().Value())
+	}
+	if totalCPU > 0 {
+		cpu /= totalCPU
+	}
+	if totalMemory > 0 {
+		memory /= totalMemory
+	}
+	return fmt.Sprintf("packing: cluster %.0f%% (cpu %.0f%%, mem %.0f%%), stranded cpu %s, mem %s on %d nodes",
+		(cpu+memory)/2*100, cpu*100, memory*100, format.CPU(strandedCPU), format.Bytes(strandedMemory), stranded)
+}
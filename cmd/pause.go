@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/bwagner5/kube-demo/pkg/state"
+)
+
+// snapshot is the cluster state the grid renders from, as of when it was paused or recorded
+type snapshot struct {
+	takenAt time.Time
+	state   *state.ClusterState
+}
+
+func (m *Model) takeSnapshot() *snapshot {
+	return &snapshot{takenAt: time.Now(), state: m.tracker.Current()}
+}
+
+// clusterState returns the state to render, the frozen snapshot while paused
+func (m *Model) clusterState() *state.ClusterState {
+	if m.frozen != nil {
+		return m.frozen.state
+	}
+	return m.tracker.Current()
+}
+
+// listNodes returns the nodes to render, oldest first. The slice must not be modified.
+func (m *Model) listNodes() []*corev1.Node {
+	return m.clusterState().Nodes
+}
+
+// listPods returns the pods to render, oldest first. The slice must not be modified.
+func (m *Model) listPods() []*corev1.Pod {
+	return m.clusterState().Pods
+}
+
+// togglePause freezes the display on the current state or, if already frozen,
+// resumes live rendering and reports how many changes were missed
+func (m *Model) togglePause() tea.Cmd {
+	if m.frozen == nil {
+		m.frozen = m.takeSnapshot()
+		m.pausedChanges = 0
+		return nil
+	}
+	m.frozen = nil
+	m.scrubbing = false
+	m.clampSelection()
+	return m.setStatus(fmt.Sprintf("%d changes while paused", m.pausedChanges))
+}
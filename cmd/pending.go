@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/bwagner5/kube-demo/pkg/format"
+	"github.com/bwagner5/kube-demo/pkg/pod"
+	"github.com/bwagner5/kube-demo/pkg/predicates"
+)
+
+// eventsTimeout bounds how long an event lookup may take
+const eventsTimeout = 10 * time.Second
+
+// eventsMsg delivers the events recorded for a pod or node
+type eventsMsg struct {
+	uid    types.UID
+	events []corev1.Event
+	err    error
+}
+
+// getPendingPods returns the rendered pods not yet bound to a node, oldest first
+func (m *Model) getPendingPods() []*corev1.Pod {
+	return append([]*corev1.Pod(nil), m.clusterState().Pending()...)
+}
+
+// fetchPodEvents looks up the pod's events in the background
+func (m *Model) fetchPodEvents(p *corev1.Pod) tea.Cmd {
+	return m.fetchEvents(p.Namespace, p.UID, fields.OneTermEqualSelector("involvedObject.uid", string(p.UID)))
+}
+
+// fetchNodeEvents looks up the node's events in the background. The kubelet
+// records node events against the node name rather than its UID.
+func (m *Model) fetchNodeEvents(n *corev1.Node) tea.Cmd {
+	return m.fetchEvents(metav1.NamespaceAll, n.UID, fields.SelectorFromSet(fields.Set{
+		"involvedObject.kind": "Node",
+		"involvedObject.name": n.Name,
+	}))
+}
+
+// fetchEvents lists the events matching selector, delivering them keyed by uid
+func (m *Model) fetchEvents(namespace string, uid types.UID, selector fields.Selector) tea.Cmd {
+	if m.kubeClient == nil {
+		return nil
+	}
+	client := m.kubeClient
+	return func() tea.Msg {
+		ctx, cancel := m.callContext(eventsTimeout)
+		defer cancel()
+		var list *corev1.EventList
+		err := retry(ctx, func(ctx context.Context) (err error) {
+			list, err = client.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{FieldSelector: selector.String()})
+			return err
+		})
+		if err != nil {
+			return eventsMsg{uid: uid, err: err}
+		}
+		return eventsMsg{uid: uid, events: list.Items}
+	}
+}
+
+// pending renders the list of pods waiting to be scheduled
+func (m *Model) pending() string {
+	pods := m.getPendingPods()
+	if len(pods) == 0 {
+		return "No pending pods"
+	}
+	var table strings.Builder
+	w := tabwriter.NewWriter(&table, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "POD\tAGE\tREQUESTS\tFITS ON\t")
+	nodes := m.getNodes()
+	for _, p := range pods {
+		fits := 0
+		for _, n := range nodes {
+			if len(predicates.Explain(p, n, m.getPods(n))) == 0 {
+				fits++
+			}
+		}
+		fmt.Fprintf(w, "%s/%s\t%s\t%s\t%d/%d nodes\t\n", p.Namespace, p.Name,
+			format.Precise(time.Since(p.CreationTimestamp.Time)), formatResources(pod.Requests(p)), fits, len(nodes))
+	}
+	w.Flush()
+	lines := strings.Split(strings.TrimRight(table.String(), "\n"), "\n")
+	lines[m.selectedPending+1] = selectedRowStyle.Render(lines[m.selectedPending+1])
+	return strings.Join(lines, "\n")
+}
+
+// schedulingExplanation renders a per-node "why not here" grid for a pending pod
+// followed by its scheduling events
+func (m *Model) schedulingExplanation(p *corev1.Pod) string {
+	var out strings.Builder
+	fmt.Fprintf(&out, "Pod: %s/%s\nRequests: %s\n\n", p.Namespace, p.Name, formatResources(pod.Requests(p)))
+	w := tabwriter.NewWriter(&out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NODE\tFITS\tWHY NOT HERE\t")
+	for _, n := range m.getNodes() {
+		reasons := predicates.Explain(p, n, m.getPods(n))
+		if len(reasons) == 0 {
+			fmt.Fprintf(w, "%s\tyes\t\t\n", n.Name)
+			continue
+		}
+		fmt.Fprintf(w, "%s\tno\t%s\t\n", n.Name, strings.Join(reasons, "; "))
+	}
+	w.Flush()
+	out.WriteString("\nEvents:\n")
+	out.WriteString(m.eventsSummary(p.UID))
+	return out.String()
+}
+
+// eventsSummary renders the fetched events of an object, or why there are none
+func (m *Model) eventsSummary(uid types.UID) string {
+	events, ok := m.objectEvents[uid]
+	switch {
+	case m.kubeClient == nil:
+		return "  <unavailable offline>\n"
+	case !ok:
+		return "  loading...\n"
+	case len(events) == 0:
+		return "  <none>\n"
+	}
+	var out strings.Builder
+	for _, event := range events {
+		fmt.Fprintf(&out, "  %s\t%s\t%s\n", event.Type, event.Reason, event.Message)
+	}
+	return out.String()
+}
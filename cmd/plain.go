@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/bwagner5/kube-demo/pkg/binpack"
+	"github.com/bwagner5/kube-demo/pkg/node"
+)
+
+// plainTop is how many pending pods and warnings the plain summary lists
+const plainTop = 10
+
+// runPlain writes a plain text summary of the cluster to out, and again whenever it changes,
+// checking every interval until interrupted. With no interval it writes a single summary.
+// Nothing is animated or redrawn in place, so the output suits screen readers and files.
+func runPlain(m *Model, out io.Writer, interval time.Duration) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
+	defer stop()
+	defer m.shutdown()
+	if m.informerFactory != nil {
+		m.informerFactory.WaitForCacheSync(m.stopCh)
+	}
+	last := ""
+	for {
+		if summary := m.plainSummary(); summary != last {
+			fmt.Fprintf(out, "%s\n%s\n", time.Now().Format(time.RFC1123), summary)
+			last = summary
+		}
+		if interval <= 0 {
+			return
+		}
+		timer := time.NewTimer(interval)
+	wait:
+		for {
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-m.k8sStateUpdate:
+				// drained so informer handlers do not back up, the summary is recomputed on the timer
+			case <-timer.C:
+				break wait
+			}
+		}
+	}
+}
+
+// plainSummary describes the cluster in sentences without color, symbols or layout
+func (m *Model) plainSummary() string {
+	var out strings.Builder
+	if m.identity != "" {
+		fmt.Fprintf(&out, "Context %s.\n", m.identity)
+	}
+	nodes := m.getNodes()
+	ready, cordoned, notReady := m.nodeStatusCounts()
+	fmt.Fprintf(&out, "%d nodes: %d ready, %d cordoned, %d not ready.\n", len(nodes), ready, cordoned, notReady)
+	phases := m.podPhaseCounts()
+	counts := make([]string, 0, len(podPhases))
+	for _, phase := range podPhases {
+		counts = append(counts, fmt.Sprintf("%d %s", phases[phase], strings.ToLower(string(phase))))
+	}
+	fmt.Fprintf(&out, "Pods: %s.\n", strings.Join(counts, ", "))
+	for _, n := range nodes {
+		pods := m.getPods(n)
+		packing := binpack.NewPacking(n, binpack.NewBin(n, pods))
+		var states []string
+		if !node.IsReady(n) {
+			states = append(states, "not ready")
+		}
+		if node.IsCordoned(n) {
+			states = append(states, "cordoned")
+		}
+		if len(states) == 0 {
+			states = append(states, "ready")
+		}
+		fmt.Fprintf(&out, "Node %s: %s, %d pods, %.0f%% of cpu and %.0f%% of memory requested.\n",
+			n.Name, strings.Join(states, " and "), len(pods), packing.CPU*100, packing.Memory*100)
+	}
+	if pending := m.getPendingPods(); len(pending) > 0 {
+		fmt.Fprintf(&out, "%d pending pods:", len(pending))
+		for i, p := range pending {
+			if i == plainTop {
+				fmt.Fprintf(&out, " and %d more", len(pending)-plainTop)
+				break
+			}
+			fmt.Fprintf(&out, " %s/%s", p.Namespace, p.Name)
+		}
+		out.WriteString(".\n")
+	}
+	warnings := m.recentEvents(plainTop, func(event *corev1.Event) bool { return event.Type == corev1.EventTypeWarning })
+	for _, event := range warnings {
+		object := event.InvolvedObject.Name
+		if event.InvolvedObject.Namespace != "" {
+			object = event.InvolvedObject.Namespace + "/" + object
+		}
+		fmt.Fprintf(&out, "Warning on %s %s: %s: %s\n", strings.ToLower(event.InvolvedObject.Kind), object, event.Reason, strings.TrimSpace(event.Message))
+	}
+	return out.String()
+}
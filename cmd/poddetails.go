@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/bwagner5/kube-demo/pkg/format"
+)
+
+// writeContainerTable writes one row per container joined with its status by name
+func writeContainerTable(out *strings.Builder, containers []corev1.Container, statuses []corev1.ContainerStatus) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "  NAME\tIMAGE\tSTATE\tRESTARTS\tLAST TERMINATION\tREQUESTS\tLIMITS\t")
+	for _, container := range containers {
+		status := containerStatus(container.Name, statuses)
+		fmt.Fprintf(w, "  %s\t%s\t%s\t%d\t%s\t%s\t%s\t\n",
+			container.Name,
+			container.Image,
+			containerState(status.State),
+			status.RestartCount,
+			lastTermination(status.LastTerminationState),
+			formatResources(container.Resources.Requests),
+			formatResources(container.Resources.Limits),
+		)
+	}
+	w.Flush()
+}
+
+func containerStatus(name string, statuses []corev1.ContainerStatus) corev1.ContainerStatus {
+	for _, status := range statuses {
+		if status.Name == name {
+			return status
+		}
+	}
+	return corev1.ContainerStatus{Name: name}
+}
+
+// containerState summarizes a container state in a single word plus reason
+func containerState(state corev1.ContainerState) string {
+	switch {
+	case state.Running != nil:
+		return "Running"
+	case state.Waiting != nil:
+		return fmt.Sprintf("Waiting (%s)", state.Waiting.Reason)
+	case state.Terminated != nil:
+		return fmt.Sprintf("Terminated (%s, exit %d)", state.Terminated.Reason, state.Terminated.ExitCode)
+	}
+	return "Unknown"
+}
+
+func lastTermination(state corev1.ContainerState) string {
+	if state.Terminated == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%s (exit %d)", state.Terminated.Reason, state.Terminated.ExitCode)
+}
+
+// formatResources renders a resource list as name=quantity pairs in a stable order
+func formatResources(list corev1.ResourceList) string {
+	if len(list) == 0 {
+		return "-"
+	}
+	var pairs []string
+	for _, name := range sortedResourceNames(list) {
+		quantity := list[name]
+		pairs = append(pairs, fmt.Sprintf("%s=%s", name, format.Quantity(name, quantity)))
+	}
+	return strings.Join(pairs, ",")
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// widePodsColumns is how many double width node boxes the grid must fit before pods are shown by name
+const widePodsColumns = 3
+
+// suffixLength is how much of a generated pod name suffix is kept when abbreviating
+const suffixLength = 3
+
+// widePods reports whether the grid is wide enough to show every node at double width with its pods by name
+func (m *Model) widePods() bool {
+	wide := nodeStyle.GetWidth()*2 + nodeStyle.GetHorizontalMargins() + nodeStyle.GetHorizontalBorderSize()
+	return canvasStyle.GetWidth()-canvasStyle.GetHorizontalPadding() >= widePodsColumns*wide
+}
+
+// namedPods reports whether the pods of the node are shown by name: on wide terminals,
+// in an expanded node and in the zoomed node
+func (m *Model) namedPods(node *corev1.Node, selected bool) bool {
+	return m.widePods() || (m.zoomed && selected) || (!m.zoomed && node.Name == m.expandedNode)
+}
+
+// abbreviatePodName shortens the pod's name to at most width cells, applying in order until it fits:
+//   - drop the pod-template-hash of a ReplicaSet pod, web-7d9f8b6c4-x2x9k becomes web-x2x9k
+//   - keep only the first characters of the generated suffix, web-x2x
+//   - shorten every word of the name but the last to its initial, payment-api-x2x becomes p-api-x2x
+//   - cut out the middle of what is left
+//
+// The same pod always gets the same abbreviation, so it can be found again after a re-render.
+func abbreviatePodName(p *corev1.Pod, width int) string {
+	if len([]rune(p.Name)) <= width {
+		return p.Name
+	}
+	base, suffix := p.Name, ""
+	if p.GenerateName != "" && strings.HasPrefix(p.Name, p.GenerateName) {
+		base, suffix = strings.TrimSuffix(p.GenerateName, "-"), p.Name[len(p.GenerateName):]
+	}
+	if hash, ok := p.Labels[appsv1.DefaultDeploymentUniqueLabelKey]; ok {
+		base = strings.TrimSuffix(base, "-"+hash)
+	}
+	candidates := []func() string{
+		func() string { return joinName(base, suffix) },
+		func() string {
+			if len(suffix) > suffixLength {
+				suffix = suffix[:suffixLength]
+			}
+			return joinName(base, suffix)
+		},
+		func() string {
+			words := strings.Split(base, "-")
+			for i := 0; i < len(words)-1; i++ {
+				if words[i] != "" {
+					words[i] = string([]rune(words[i])[:1])
+				}
+			}
+			base = strings.Join(words, "-")
+			return joinName(base, suffix)
+		},
+	}
+	name := p.Name
+	for _, candidate := range candidates {
+		if name = candidate(); len([]rune(name)) <= width {
+			return name
+		}
+	}
+	return middleTruncate(name, width)
+}
+
+func joinName(base string, suffix string) string {
+	if suffix == "" {
+		return base
+	}
+	return base + "-" + suffix
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+var orange = lipgloss.Color("#F4A259")
+var purple = lipgloss.Color("#B388EB")
+
+var preemptionVictimBorder = orange
+var preemptorBorder = purple
+
+// preemptionReasons are DisruptionTarget condition reasons set on pods evicted for a higher priority pod
+var preemptionReasons = map[string]bool{
+	"PreemptionByKubeScheduler": true,
+	"PreemptionByScheduler":     true,
+}
+
+// preemptionVictims maps the UIDs of pods the scheduler preempted to the
+// event message naming the preemptor
+func (m *Model) preemptionVictims() map[types.UID]string {
+	victims := map[types.UID]string{}
+	for _, obj := range m.store.Events.List() {
+		event := obj.(*corev1.Event)
+		if event.Reason == "Preempted" && event.InvolvedObject.Kind == "Pod" {
+			victims[event.InvolvedObject.UID] = event.Message
+		}
+	}
+	return victims
+}
+
+// isPreemptionVictim returns true if the pod is being evicted to make room for a higher priority pod
+func (m *Model) isPreemptionVictim(p *corev1.Pod) bool {
+	if _, ok := m.preemptions[p.UID]; ok {
+		return true
+	}
+	for _, condition := range p.Status.Conditions {
+		if condition.Type == "DisruptionTarget" && condition.Status == corev1.ConditionTrue && preemptionReasons[condition.Reason] {
+			return true
+		}
+	}
+	return false
+}
+
+// nominatedPods groups the pending pods the scheduler has nominated onto a node after
+// preempting its pods by the node's name
+func (m *Model) nominatedPods() map[string][]*corev1.Pod {
+	nominated := map[string][]*corev1.Pod{}
+	for _, p := range m.listPods() {
+		if p.Spec.NodeName == "" && p.Status.NominatedNodeName != "" {
+			nominated[p.Status.NominatedNodeName] = append(nominated[p.Status.NominatedNodeName], p)
+		}
+	}
+	return nominated
+}
+
+// preemptionBadges describes incoming preemptors on the node
+func (m *Model) preemptionBadges(nodeName string) []string {
+	var badges []string
+	for _, p := range m.nominated[nodeName] {
+		badges = append(badges, lipgloss.NewStyle().Foreground(preemptorBorder).Render(
+			fmt.Sprintf("⇐ %s (priority %d)", p.Name, priority(p))))
+	}
+	return badges
+}
+
+func priority(p *corev1.Pod) int32 {
+	if p.Spec.Priority == nil {
+		return 0
+	}
+	return *p.Spec.Priority
+}
+
+// preemptionDetails explains a victim's preemption for the pod detail view
+func (m *Model) preemptionDetails(p *corev1.Pod) string {
+	if !m.isPreemptionVictim(p) {
+		return ""
+	}
+	message := strings.TrimSpace(m.preemptions[p.UID])
+	if message == "" {
+		message = "evicted by the scheduler for a higher priority pod"
+	}
+	return fmt.Sprintf("Preempted: %s\n", message)
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+)
+
+// preloadPageSize is the number of nodes requested per page while the caches sync
+const preloadPageSize = 500
+
+// preloadTimeout bounds listing a single page of nodes
+const preloadTimeout = 30 * time.Second
+
+// cacheSyncedMsg reports that every informer has listed its objects
+type cacheSyncedMsg struct{}
+
+// waitForCacheSync delivers cacheSyncedMsg once the informers have synced
+func (m *Model) waitForCacheSync() tea.Msg {
+	if m.informerFactory != nil {
+		_, span := tracer.Start(context.Background(), "informer.sync")
+		m.informerFactory.WaitForCacheSync(m.stopCh)
+		span.End()
+	}
+	return cacheSyncedMsg{}
+}
+
+// waitForStateChange delivers the next change signalled by the informers
+func (m *Model) waitForStateChange() tea.Msg {
+	select {
+	case <-m.k8sStateUpdate:
+		return k8sStateChange{}
+	case <-m.stopCh:
+		return nil
+	}
+}
+
+// preloadNodes lists the nodes in pages ahead of the node informer into the preloaded store,
+// which the snapshots merge, so the grid fills in as pages arrive. The informer lists every
+// node in one response from the API server's watch cache, which takes many seconds on
+// clusters with thousands of nodes. The informer's own cache is never written, so its copies
+// win and its handlers see each node added once. Preloading stops once it has synced, and the
+// preloaded store is emptied then: from that point the informer alone holds the nodes.
+func (m *Model) preloadNodes(client kubernetes.Interface, informer cache.SharedIndexInformer) {
+	defer m.dropPreloaded(informer)
+	tracker := m.tracker.Handler()
+	// without a resource version the API server honors the limit instead of serving the watch cache
+	options := metav1.ListOptions{Limit: preloadPageSize}
+	for !informer.HasSynced() {
+		ctx, cancel := m.callContext(preloadTimeout)
+		var list *corev1.NodeList
+		err := retry(ctx, func(ctx context.Context) (err error) {
+			list, err = client.CoreV1().Nodes().List(ctx, options)
+			return err
+		})
+		cancel()
+		if err != nil {
+			// the informer still delivers every node once its own list completes
+			return
+		}
+		for i := range list.Items {
+			obj, _ := trimObject(&list.Items[i])
+			_ = m.preloaded.Add(obj)
+			tracker.OnAdd(obj)
+		}
+		select {
+		case m.k8sStateUpdate <- struct{}{}:
+		case <-m.stopCh:
+			return
+		}
+		if list.Continue == "" {
+			return
+		}
+		options.Continue = list.Continue
+	}
+}
+
+// dropPreloaded empties the preloaded store once the node informer has synced, so nodes
+// deleted since they were listed do not linger
+func (m *Model) dropPreloaded(informer cache.SharedIndexInformer) {
+	if !cache.WaitForCacheSync(m.stopCh, informer.HasSynced) {
+		return
+	}
+	tracker := m.tracker.Handler()
+	preloaded := m.preloaded.List()
+	for _, obj := range preloaded {
+		_ = m.preloaded.Delete(obj)
+		tracker.OnDelete(obj)
+	}
+	if len(preloaded) == 0 {
+		return
+	}
+	select {
+	case m.k8sStateUpdate <- struct{}{}:
+	case <-m.stopCh:
+	}
+}
+
+// loadingStatus reports the progress of the initial listing, and its completion once the
+// caches have synced if progress was shown
+func (m *Model) loadingStatus() tea.Cmd {
+	live := m.tracker.Current()
+	if m.synced {
+		if !strings.HasPrefix(m.status, "loading:") {
+			return nil
+		}
+		return m.setStatus(fmt.Sprintf("loaded %d nodes and %d pods", len(live.Nodes), len(live.Pods)))
+	}
+	if len(live.Nodes) == 0 {
+		return nil
+	}
+	return m.setStatus(fmt.Sprintf("loading: %d nodes and %d pods so far", len(live.Nodes), len(live.Pods)))
+}
@@ -0,0 +1,221 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"sigs.k8s.io/yaml"
+)
+
+// maxProfileChoices is how many profiles the profiles menu can number
+const maxProfileChoices = 9
+
+// profile is a named combination of view, filters, grouping, layout and overlays
+type profile struct {
+	Name           string  `json:"name"`
+	View           string  `json:"view,omitempty"`
+	Filter         string  `json:"filter,omitempty"`
+	TaintKey       string  `json:"taintKey,omitempty"`
+	PrivilegedOnly bool    `json:"privilegedOnly,omitempty"`
+	GroupByPool    bool    `json:"groupByPool,omitempty"`
+	Split          bool    `json:"split,omitempty"`
+	SplitRatio     float64 `json:"splitRatio,omitempty"`
+	DetailTab      string  `json:"detailTab,omitempty"`
+	Packing        bool    `json:"packing,omitempty"`
+	Rightsizing    bool    `json:"rightsizing,omitempty"`
+	Upgrade        bool    `json:"upgrade,omitempty"`
+	ColorBy        string  `json:"colorBy,omitempty"`
+}
+
+// config is the file profiles are saved in
+type config struct {
+	Profiles []profile `json:"profiles"`
+	// NodeTemplate is a text/template of the lines at the top of a node box, see nodeFields
+	NodeTemplate string `json:"nodeTemplate,omitempty"`
+}
+
+// defaultConfigPath returns the config file in the user's config directory
+func defaultConfigPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "kube-demo", "config.yaml")
+}
+
+// loadConfig reads the config file, treating a missing file as empty
+func loadConfig(path string) (config, error) {
+	var cfg config
+	if path == "" {
+		return cfg, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// saveConfig writes the config file, creating its directory if needed
+func saveConfig(path string, cfg config) error {
+	if path == "" {
+		return errors.New("no config directory, pass --config")
+	}
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// findProfile returns the profile with the given name
+func findProfile(profiles []profile, name string) (profile, bool) {
+	for _, p := range profiles {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return profile{}, false
+}
+
+// currentProfile captures the current view, filters, grouping, layout and overlays under name
+func (m *Model) currentProfile(name string) profile {
+	return profile{
+		Name:           name,
+		View:           m.view.String(),
+		Filter:         m.nodeFilter.String(),
+		TaintKey:       m.taintKey,
+		PrivilegedOnly: m.privilegedOnly,
+		GroupByPool:    m.groupByPool,
+		Split:          m.split,
+		SplitRatio:     m.splitRatio,
+		DetailTab:      detailTabNames[m.detailTab],
+		Packing:        m.showPacking,
+		Rightsizing:    m.rightsizing,
+		Upgrade:        m.upgradeMode,
+		ColorBy:        m.colorMode.String(),
+	}
+}
+
+// applyProfile switches to the profile's settings. Unknown view, filter, coloring or tab names keep the current one.
+func (m *Model) applyProfile(p profile) tea.Cmd {
+	for v := viewMode(0); int(v) < len(viewNames); v++ {
+		if v.String() == p.View {
+			m.view = v
+			m.unfocus(focusDetails)
+		}
+	}
+	for f := filterNone; f < filterCount; f++ {
+		if f.String() == p.Filter {
+			m.nodeFilter = f
+		}
+	}
+	for c := colorByState; c < colorModeCount; c++ {
+		if c.String() == p.ColorBy {
+			m.colorMode = c
+		}
+	}
+	for i, name := range detailTabNames {
+		if name == p.DetailTab {
+			m.detailTab = detailTab(i)
+		}
+	}
+	m.taintKey = p.TaintKey
+	m.privilegedOnly = p.PrivilegedOnly
+	m.split = p.Split
+	if p.SplitRatio != 0 {
+		m.splitRatio = p.SplitRatio
+		m.resizeSplit(0)
+	}
+	m.showPacking = p.Packing
+	m.rightsizing = p.Rightsizing
+	m.upgradeMode = p.Upgrade
+	if m.showPacking {
+		m.packing = m.packingOf()
+	}
+	if m.upgradeMode {
+		m.versions = m.kubeletVersions()
+	}
+	m.selectedNode, m.selectedPod = 0, 0
+	m.clampSelection()
+	var cmd tea.Cmd
+	if p.GroupByPool != m.groupByPool {
+		cmd = m.toggleNodePools()
+	}
+	m.profile = p.Name
+	return tea.Batch(cmd, m.setStatus(fmt.Sprintf("profile %q applied", p.Name)))
+}
+
+// applyStartProfile applies the settings of a restored state or the --profile the session was started with
+func (m *Model) applyStartProfile() tea.Cmd {
+	if m.restored != nil {
+		return m.applyProfile(*m.restored)
+	}
+	if p, ok := findProfile(m.profiles, m.profile); ok {
+		return m.applyProfile(p)
+	}
+	return nil
+}
+
+// profilesMenu offers to save the current settings as a profile or to switch to a saved one
+func (m *Model) profilesMenu() tea.Cmd {
+	items := []menuItem{{key: "s", label: "save current as...", choose: m.promptSaveProfile}}
+	for i, p := range m.profiles {
+		if i == maxProfileChoices {
+			break
+		}
+		p := p
+		label := p.Name
+		if p.Name == m.profile {
+			label += " (active)"
+		}
+		items = append(items, menuItem{key: fmt.Sprint(i + 1), label: label, choose: func() tea.Cmd { return m.applyProfile(p) }})
+	}
+	m.menu = &menu{title: "profiles", items: items}
+	return nil
+}
+
+// promptSaveProfile asks for a name and saves the current settings under it, replacing a profile of the same name
+func (m *Model) promptSaveProfile() tea.Cmd {
+	return m.openPrompt("save profile as", "capacity demo", func(value string) tea.Cmd {
+		name := strings.TrimSpace(value)
+		if name == "" {
+			return m.setStatus("profile name cannot be empty")
+		}
+		saved := m.currentProfile(name)
+		profiles := make([]profile, 0, len(m.profiles)+1)
+		replaced := false
+		for _, p := range m.profiles {
+			if p.Name == name {
+				p, replaced = saved, true
+			}
+			profiles = append(profiles, p)
+		}
+		if !replaced {
+			profiles = append(profiles, saved)
+		}
+		cfg, err := loadConfig(m.configFile)
+		if err != nil {
+			return m.setStatus(fmt.Sprintf("could not save profile %q: %v", name, err))
+		}
+		cfg.Profiles = profiles
+		if err := saveConfig(m.configFile, cfg); err != nil {
+			return m.setStatus(fmt.Sprintf("could not save profile %q: %v", name, err))
+		}
+		m.profiles, m.profile = profiles, name
+		return m.setStatus(fmt.Sprintf("profile %q saved to %s", name, m.configFile))
+	})
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// prompt is a single line text input shown in place of the status line.
+// Submitting it hands the entered value to onSubmit.
+type prompt struct {
+	input    textinput.Model
+	onSubmit func(value string) tea.Cmd
+}
+
+// openPrompt focuses a text input with the given label
+func (m *Model) openPrompt(label string, placeholder string, onSubmit func(value string) tea.Cmd) tea.Cmd {
+	input := textinput.New()
+	input.Prompt = label + ": "
+	input.Placeholder = placeholder
+	m.prompt = &prompt{input: input, onSubmit: onSubmit}
+	return m.prompt.input.Focus()
+}
+
+// updatePrompt routes key presses to the open prompt; enter submits and esc cancels
+func (m *Model) updatePrompt(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "enter":
+		p := m.prompt
+		m.prompt = nil
+		return p.onSubmit(p.input.Value())
+	case "esc", "ctrl+c":
+		m.prompt = nil
+		return nil
+	}
+	var cmd tea.Cmd
+	m.prompt.input, cmd = m.prompt.input.Update(msg)
+	return cmd
+}
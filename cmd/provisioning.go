@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// provisionedBadgeDuration is how long a new node shows how fast it became Ready
+const provisionedBadgeDuration = 5 * time.Minute
+
+// provisioningBuckets are the upper bounds of the provisioning latency histogram
+var provisioningBuckets = []time.Duration{30 * time.Second, time.Minute, 2 * time.Minute, 5 * time.Minute}
+
+// histogramWidth is the length of the longest histogram bar
+const histogramWidth = 30
+
+// provisioning is how long a node that joined during the session took to become Ready
+type provisioning struct {
+	readyAt time.Time
+	latency time.Duration
+}
+
+// recordProvisioning notes the latency of nodes created during the session the
+// first time they are seen Ready. Entries are kept after the node goes away so
+// the histogram covers the whole session.
+func (m *Model) recordProvisioning() {
+	for _, obj := range m.store.Nodes.List() {
+		n := obj.(*corev1.Node)
+		if _, ok := m.provisioned[n.Name]; ok || n.CreationTimestamp.Time.Before(m.readiness.start) {
+			continue
+		}
+		for _, condition := range n.Status.Conditions {
+			if condition.Type == corev1.NodeReady && condition.Status == corev1.ConditionTrue {
+				m.provisioned[n.Name] = provisioning{
+					readyAt: condition.LastTransitionTime.Time,
+					latency: condition.LastTransitionTime.Sub(n.CreationTimestamp.Time),
+				}
+			}
+		}
+	}
+}
+
+// provisioningBadges shows how fast a recently joined node became Ready
+func (m *Model) provisioningBadges(nodeName string) []string {
+	p, ok := m.provisioned[nodeName]
+	if !ok || time.Since(p.readyAt) > provisionedBadgeDuration {
+		return nil
+	}
+	return []string{fmt.Sprintf("ready in %s", p.latency)}
+}
+
+// provisioningHistogram renders the provisioning latencies of the session bucketed for node details
+func (m *Model) provisioningHistogram() string {
+	if len(m.provisioned) == 0 {
+		return "Provisioning: no nodes joined this session\n"
+	}
+	counts := make([]int, len(provisioningBuckets)+1)
+	highest := 0
+	for _, p := range m.provisioned {
+		bucket := len(provisioningBuckets)
+		for i, bound := range provisioningBuckets {
+			if p.latency <= bound {
+				bucket = i
+				break
+			}
+		}
+		counts[bucket]++
+		if counts[bucket] > highest {
+			highest = counts[bucket]
+		}
+	}
+	var out strings.Builder
+	fmt.Fprintf(&out, "Provisioning latency of %d nodes joined this session:\n", len(m.provisioned))
+	for i, count := range counts {
+		label := fmt.Sprintf("> %s", provisioningBuckets[len(provisioningBuckets)-1])
+		if i < len(provisioningBuckets) {
+			label = fmt.Sprintf("≤ %s", provisioningBuckets[i])
+		}
+		fmt.Fprintf(&out, "  %-8s %s %d\n", label, strings.Repeat("█", count*histogramWidth/highest), count)
+	}
+	return out.String()
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/samber/lo"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// pullSecretsTimeout bounds checking a pod's image pull secrets
+const pullSecretsTimeout = 10 * time.Second
+
+// pullFailure is a container that cannot start because its image cannot be pulled
+type pullFailure struct {
+	container string
+	image     string
+	reason    string
+	message   string
+}
+
+// pullErrorHints map fragments of container runtime pull errors to what to check next
+var pullErrorHints = []struct {
+	fragments []string
+	hint      string
+}{
+	{[]string{"not found", "manifest unknown", "does not exist"}, "the image or tag does not exist in the registry: check the name and tag for typos"},
+	{[]string{"unauthorized", "authentication required", "denied", "403 forbidden", "401"}, "the registry refused the pull: check the pull secret's credentials and that it grants access to this repository"},
+	{[]string{"toomanyrequests", "rate limit"}, "the registry is rate limiting pulls: authenticate the pulls or use a mirror"},
+	{[]string{"no such host", "i/o timeout", "connection refused", "network is unreachable", "tls handshake timeout"}, "the node cannot reach the registry: check DNS, egress rules and proxy settings on the node"},
+	{[]string{"x509", "certificate"}, "the registry's TLS certificate is not trusted by the node's container runtime"},
+	{[]string{"no matching manifest", "platform"}, "the image has no variant for the node's OS and architecture"},
+}
+
+// pullSecretState is the result of looking up a referenced image pull secret
+type pullSecretState struct {
+	name string
+	// from names where the secret is referenced, the pod or its ServiceAccount
+	from    string
+	missing bool
+	// secretType is set when the secret exists
+	secretType corev1.SecretType
+}
+
+// pullSecretsMsg delivers the image pull secrets a pod can use and whether they exist
+type pullSecretsMsg struct {
+	uid     types.UID
+	secrets []pullSecretState
+	err     error
+}
+
+// pullFailures returns the pod's containers waiting on an image pull
+func pullFailures(p *corev1.Pod) []pullFailure {
+	var failures []pullFailure
+	statuses := append(append([]corev1.ContainerStatus{}, p.Status.InitContainerStatuses...), p.Status.ContainerStatuses...)
+	for _, container := range focusedContainers(p) {
+		if waiting := containerStatus(container.Name, statuses).State.Waiting; waiting != nil && pullErrorReasons[waiting.Reason] {
+			failures = append(failures, pullFailure{container: container.Name, image: container.Image, reason: waiting.Reason, message: waiting.Message})
+		}
+	}
+	return failures
+}
+
+// fetchPullSecrets looks up the pull secrets referenced by the pod and its ServiceAccount in the background
+func (m *Model) fetchPullSecrets(p *corev1.Pod) tea.Cmd {
+	if m.kubeClient == nil || len(pullFailures(p)) == 0 {
+		return nil
+	}
+	client := m.kubeClient
+	namespace, uid, account := p.Namespace, p.UID, serviceAccountName(p)
+	var refs []pullSecretState
+	for _, ref := range p.Spec.ImagePullSecrets {
+		refs = append(refs, pullSecretState{name: ref.Name, from: "pod"})
+	}
+	return func() tea.Msg {
+		ctx, cancel := m.callContext(pullSecretsTimeout)
+		defer cancel()
+		var sa *corev1.ServiceAccount
+		err := retry(ctx, func(ctx context.Context) (err error) {
+			sa, err = client.CoreV1().ServiceAccounts(namespace).Get(ctx, account, metav1.GetOptions{})
+			return err
+		})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return pullSecretsMsg{uid: uid, err: err}
+		}
+		if err == nil {
+			for _, ref := range sa.ImagePullSecrets {
+				refs = append(refs, pullSecretState{name: ref.Name, from: "serviceaccount " + account})
+			}
+		}
+		for i := range refs {
+			var secret *corev1.Secret
+			err := retry(ctx, func(ctx context.Context) (err error) {
+				secret, err = client.CoreV1().Secrets(namespace).Get(ctx, refs[i].name, metav1.GetOptions{})
+				return err
+			})
+			switch {
+			case apierrors.IsNotFound(err):
+				refs[i].missing = true
+			case err != nil:
+				return pullSecretsMsg{uid: uid, err: err}
+			default:
+				refs[i].secretType = secret.Type
+			}
+		}
+		return pullSecretsMsg{uid: uid, secrets: refs}
+	}
+}
+
+// recordPullSecrets stores the pull secret lookup, or the reason it failed
+func (m *Model) recordPullSecrets(msg pullSecretsMsg) {
+	if msg.err != nil {
+		m.pullSecretErrs[msg.uid] = msg.err
+		return
+	}
+	delete(m.pullSecretErrs, msg.uid)
+	m.pullSecrets[msg.uid] = msg.secrets
+}
+
+// pullEventMessages returns the messages of the pod's events about failed pulls of image
+func (m *Model) pullEventMessages(uid types.UID, image string) []string {
+	var messages []string
+	for _, event := range m.objectEvents[uid] {
+		if event.Type == corev1.EventTypeWarning && strings.Contains(event.Message, image) && !lo.Contains(messages, event.Message) {
+			messages = append(messages, event.Message)
+		}
+	}
+	return messages
+}
+
+// pullHint returns what to check for a pull error message, or "" if it is not recognized
+func pullHint(failure pullFailure, messages []string) string {
+	if failure.reason == "InvalidImageName" {
+		return "the image reference is malformed: check for uppercase letters, spaces or a stray scheme"
+	}
+	text := strings.ToLower(failure.message + " " + strings.Join(messages, " "))
+	for _, h := range pullErrorHints {
+		for _, fragment := range h.fragments {
+			if strings.Contains(text, fragment) {
+				return h.hint
+			}
+		}
+	}
+	return ""
+}
+
+// pullDiagnosis walks through why the pod's images cannot be pulled for the pod details, or returns "" when they can
+func (m *Model) pullDiagnosis(p *corev1.Pod) string {
+	failures := pullFailures(p)
+	if len(failures) == 0 {
+		return ""
+	}
+	var out strings.Builder
+	out.WriteString("Image pull diagnosis:\n")
+	for _, failure := range failures {
+		fmt.Fprintf(&out, "  %s (%s) is in %s\n", failure.container, failure.image, failure.reason)
+		messages := m.pullEventMessages(p.UID, failure.image)
+		if len(messages) == 0 && failure.message != "" {
+			messages = []string{failure.message}
+		}
+		for _, message := range messages {
+			fmt.Fprintf(&out, "    error: %s\n", message)
+		}
+		if hint := pullHint(failure, messages); hint != "" {
+			fmt.Fprintf(&out, "    check: %s\n", hint)
+		}
+		fmt.Fprintf(&out, "    registry: %s\n", registry(failure.image))
+	}
+	out.WriteString(m.pullSecretsDiagnosis(p))
+	return out.String()
+}
+
+// pullSecretsDiagnosis reports missing or malformed pull secrets
+func (m *Model) pullSecretsDiagnosis(p *corev1.Pod) string {
+	secrets, ok := m.pullSecrets[p.UID]
+	switch {
+	case m.kubeClient == nil:
+		return "  pull secrets: <unavailable offline>\n"
+	case m.pullSecretErrs[p.UID] != nil:
+		return fmt.Sprintf("  pull secrets: could not check: %v\n", m.pullSecretErrs[p.UID])
+	case !ok:
+		return "  pull secrets: loading...\n"
+	case len(secrets) == 0:
+		return "  pull secrets: none referenced by the pod or its ServiceAccount, so private registries will refuse the pull\n"
+	}
+	var out strings.Builder
+	for _, secret := range secrets {
+		switch {
+		case secret.missing:
+			fmt.Fprintf(&out, "  pull secret %s (from %s): does not exist in namespace %s\n", secret.name, secret.from, p.Namespace)
+		case secret.secretType != corev1.SecretTypeDockerConfigJson && secret.secretType != corev1.SecretTypeDockercfg:
+			fmt.Fprintf(&out, "  pull secret %s (from %s): has type %s, the kubelet only reads %s secrets\n", secret.name, secret.from, secret.secretType, corev1.SecretTypeDockerConfigJson)
+		default:
+			fmt.Fprintf(&out, "  pull secret %s (from %s): exists\n", secret.name, secret.from)
+		}
+	}
+	return out.String()
+}
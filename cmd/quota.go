@@ -0,0 +1,120 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/bwagner5/kube-demo/pkg/format"
+)
+
+// namespaceQuotas returns the ResourceQuotas in the namespace sorted by name
+func (m *Model) namespaceQuotas(namespace string) []*corev1.ResourceQuota {
+	var quotas []*corev1.ResourceQuota
+	for _, obj := range m.store.ResourceQuotas.List() {
+		if quota := obj.(*corev1.ResourceQuota); quota.Namespace == namespace {
+			quotas = append(quotas, quota)
+		}
+	}
+	sort.Slice(quotas, func(i, j int) bool { return quotas[i].Name < quotas[j].Name })
+	return quotas
+}
+
+// namespaceLimitRanges returns the LimitRanges in the namespace sorted by name
+func (m *Model) namespaceLimitRanges(namespace string) []*corev1.LimitRange {
+	var limitRanges []*corev1.LimitRange
+	for _, obj := range m.store.LimitRanges.List() {
+		if limitRange := obj.(*corev1.LimitRange); limitRange.Namespace == namespace {
+			limitRanges = append(limitRanges, limitRange)
+		}
+	}
+	sort.Slice(limitRanges, func(i, j int) bool { return limitRanges[i].Name < limitRanges[j].Name })
+	return limitRanges
+}
+
+// quotaPressure returns the most exhausted quota resource in the namespace and
+// its used/hard ratio, or an empty name if the namespace has no quotas
+func (m *Model) quotaPressure(namespace string) (corev1.ResourceName, float64) {
+	var worst corev1.ResourceName
+	var worstRatio float64
+	for _, quota := range m.namespaceQuotas(namespace) {
+		for name, hard := range quota.Status.Hard {
+			used := quota.Status.Used[name]
+			ratio := 1.0
+			if !hard.IsZero() {
+				ratio = used.AsApproximateFloat64() / hard.AsApproximateFloat64()
+			}
+			if worst == "" || ratio > worstRatio {
+				worst, worstRatio = name, ratio
+			}
+		}
+	}
+	return worst, worstRatio
+}
+
+// namespaceDetails renders quota usage and limit ranges for a namespace
+func (m *Model) namespaceDetails(namespace string) string {
+	var out strings.Builder
+	fmt.Fprintf(&out, "Namespace: %s\n\nResourceQuotas:\n", namespace)
+	quotas := m.namespaceQuotas(namespace)
+	if len(quotas) == 0 {
+		out.WriteString("  <none>\n")
+	}
+	for _, quota := range quotas {
+		fmt.Fprintf(&out, "  %s\n", quota.Name)
+		w := tabwriter.NewWriter(&out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "    RESOURCE\tUSED\tHARD\t")
+		for _, name := range sortedResourceNames(quota.Status.Hard) {
+			used := quota.Status.Used[name]
+			hard := quota.Status.Hard[name]
+			fmt.Fprintf(w, "    %s\t%s\t%s\t\n", name, format.Quantity(name, used), format.Quantity(name, hard))
+		}
+		w.Flush()
+	}
+	out.WriteString("\nLimitRanges:\n")
+	limitRanges := m.namespaceLimitRanges(namespace)
+	if len(limitRanges) == 0 {
+		out.WriteString("  <none>\n")
+	}
+	for _, limitRange := range limitRanges {
+		fmt.Fprintf(&out, "  %s\n", limitRange.Name)
+		w := tabwriter.NewWriter(&out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "    TYPE\tRESOURCE\tMIN\tMAX\tDEFAULT REQUEST\tDEFAULT LIMIT\t")
+		for _, item := range limitRange.Spec.Limits {
+			names := map[corev1.ResourceName]resource.Quantity{}
+			for _, list := range []corev1.ResourceList{item.Min, item.Max, item.DefaultRequest, item.Default} {
+				for name, quantity := range list {
+					names[name] = quantity
+				}
+			}
+			for _, name := range sortedResourceNames(names) {
+				fmt.Fprintf(w, "    %s\t%s\t%s\t%s\t%s\t%s\t\n", item.Type, name,
+					quantityOrDash(item.Min, name), quantityOrDash(item.Max, name),
+					quantityOrDash(item.DefaultRequest, name), quantityOrDash(item.Default, name))
+			}
+		}
+		w.Flush()
+	}
+	return out.String()
+}
+
+// sortedResourceNames returns the keys of a resource list in a stable order
+func sortedResourceNames(list corev1.ResourceList) []corev1.ResourceName {
+	names := make([]corev1.ResourceName, 0, len(list))
+	for name := range list {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+	return names
+}
+
+func quantityOrDash(list corev1.ResourceList, name corev1.ResourceName) string {
+	if quantity, ok := list[name]; ok {
+		return format.Quantity(name, quantity)
+	}
+	return "-"
+}
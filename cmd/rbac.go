@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// accessReviewTimeout bounds the startup permission checks
+const accessReviewTimeout = 10 * time.Second
+
+// capability is a permission an action needs, checked once at startup
+type capability struct {
+	name string
+	// keys are the keyMappings entries annotated when the capability is missing
+	keys       []string
+	attributes authorizationv1.ResourceAttributes
+	// namespaced capabilities are checked in the --namespace, see checkCapabilities
+	namespaced bool
+}
+
+var capabilities = []capability{
+	{name: "patch nodes", keys: []string{"Cordon", "Drain", "Label", "Taint", "Cleanup", "Finalizers"}, attributes: authorizationv1.ResourceAttributes{Verb: "patch", Resource: "nodes"}},
+	{name: "patch nodes/status", keys: []string{"Chaos"}, attributes: authorizationv1.ResourceAttributes{Verb: "patch", Resource: "nodes", Subresource: "status"}},
+	{name: "patch pods", keys: []string{"Finalizers"}, attributes: authorizationv1.ResourceAttributes{Verb: "patch", Resource: "pods"}, namespaced: true},
+	{name: "delete pods", keys: []string{"Delete", "Chaos", "Reschedule"}, attributes: authorizationv1.ResourceAttributes{Verb: "delete", Resource: "pods"}, namespaced: true},
+	{name: "create pods/eviction", keys: []string{"Drain", "Rebalance"}, attributes: authorizationv1.ResourceAttributes{Verb: "create", Resource: "pods", Subresource: "eviction"}, namespaced: true},
+	{name: "patch deployments/scale", keys: []string{"Scale"}, attributes: authorizationv1.ResourceAttributes{Verb: "patch", Group: "apps", Resource: "deployments", Subresource: "scale"}, namespaced: true},
+	{name: "patch statefulsets/scale", keys: []string{"Scale"}, attributes: authorizationv1.ResourceAttributes{Verb: "patch", Group: "apps", Resource: "statefulsets", Subresource: "scale"}, namespaced: true},
+	{name: "delete deployments", keys: []string{"Cleanup"}, attributes: authorizationv1.ResourceAttributes{Verb: "delete", Group: "apps", Resource: "deployments"}, namespaced: true},
+	{name: "delete namespaces", keys: []string{"Cleanup"}, attributes: authorizationv1.ResourceAttributes{Verb: "delete", Resource: "namespaces"}},
+}
+
+// capabilitiesMsg reports which capabilities the current identity lacks
+type capabilitiesMsg struct {
+	denied map[string]bool
+	err    error
+}
+
+// checkCapabilities runs a SelfSubjectAccessReview for every capability in the background.
+// Namespaced capabilities are checked in the --namespace. Watching every namespace, they
+// are only checked cluster wide, and a denial is not recorded: access may still be granted
+// in the namespace an action targets, which the API server decides.
+func (m *Model) checkCapabilities() tea.Cmd {
+	if m.kubeClient == nil {
+		return nil
+	}
+	client, namespace := m.kubeClient, m.namespace
+	return func() tea.Msg {
+		ctx, cancel := m.callContext(accessReviewTimeout)
+		defer cancel()
+		denied := map[string]bool{}
+		for _, c := range capabilities {
+			attributes := c.attributes
+			if c.namespaced {
+				attributes.Namespace = namespace
+			}
+			allowed, err := canI(ctx, client, attributes)
+			if err != nil {
+				return capabilitiesMsg{err: err}
+			}
+			if !allowed && (!c.namespaced || namespace != "") {
+				denied[c.name] = true
+			}
+		}
+		return capabilitiesMsg{denied: denied}
+	}
+}
+
+func canI(ctx context.Context, client kubernetes.Interface, attributes authorizationv1.ResourceAttributes) (bool, error) {
+	var review *authorizationv1.SelfSubjectAccessReview
+	err := retry(ctx, func(ctx context.Context) (err error) {
+		review, err = client.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, &authorizationv1.SelfSubjectAccessReview{
+			Spec: authorizationv1.SelfSubjectAccessReviewSpec{ResourceAttributes: &attributes},
+		}, metav1.CreateOptions{})
+		return err
+	})
+	if err != nil {
+		return false, err
+	}
+	return review.Status.Allowed, nil
+}
+
+// applyCapabilities records denied capabilities and marks the affected key bindings in the help
+// view, once however many of their capabilities are denied
+func (m *Model) applyCapabilities(msg capabilitiesMsg) tea.Cmd {
+	if msg.err != nil {
+		return m.setStatus(fmt.Sprintf("could not check permissions: %v", msg.err))
+	}
+	m.denied = msg.denied
+	forbidden := map[string]bool{}
+	for _, c := range capabilities {
+		if m.denied[c.name] {
+			for _, name := range c.keys {
+				forbidden[name] = true
+			}
+		}
+	}
+	m.keys = make(keyMap, len(keyMappings))
+	for name, binding := range keyMappings {
+		if forbidden[name] {
+			help := binding.Help()
+			binding.SetHelp(help.Key, help.Desc+" (forbidden)")
+		}
+		m.keys[name] = binding
+	}
+	return nil
+}
+
+// permitted returns an error naming the first capability the action needs but the user lacks
+func (m *Model) permitted(a action) error {
+	for _, name := range a.requires {
+		if m.denied[name] {
+			return fmt.Errorf("forbidden: you cannot %s", name)
+		}
+	}
+	return nil
+}
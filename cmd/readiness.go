@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/samber/lo"
+
+	"github.com/bwagner5/kube-demo/pkg/node"
+)
+
+// sparklineWidth is the number of buckets in a readiness sparkline
+const sparklineWidth = 30
+
+// readyTransition records a node's Ready condition flipping
+type readyTransition struct {
+	at    time.Time
+	ready bool
+}
+
+// readinessTracker records Ready condition transitions per node for the session.
+// It is fed from informer goroutines, so access is guarded by a mutex.
+type readinessTracker struct {
+	mu          sync.Mutex
+	start       time.Time
+	transitions map[string][]readyTransition
+}
+
+func newReadinessTracker() *readinessTracker {
+	return &readinessTracker{start: time.Now(), transitions: map[string][]readyTransition{}}
+}
+
+// handler returns informer callbacks that observe node readiness
+func (r *readinessTracker) handler() cache.ResourceEventHandlerFuncs {
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { r.observe(obj.(*corev1.Node)) },
+		UpdateFunc: func(_, obj interface{}) { r.observe(obj.(*corev1.Node)) },
+	}
+}
+
+func (r *readinessTracker) observe(n *corev1.Node) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ready := node.IsReady(n)
+	history := r.transitions[n.Name]
+	if len(history) > 0 && history[len(history)-1].ready == ready {
+		return
+	}
+	r.transitions[n.Name] = append(history, readyTransition{at: time.Now(), ready: ready})
+}
+
+func (r *readinessTracker) get(name string) []readyTransition {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]readyTransition{}, r.transitions[name]...)
+}
+
+// sparkline renders the node's readiness across the session, one bucket per cell:
+// █ ready, ▁ not ready, ▄ flapped within the bucket, space before the node was seen
+func (r *readinessTracker) sparkline(name string, now time.Time) string {
+	transitions := r.get(name)
+	bucket := now.Sub(r.start) / sparklineWidth
+	var line strings.Builder
+	next := 0
+	for i := 1; i <= sparklineWidth; i++ {
+		end := r.start.Add(bucket * time.Duration(i))
+		flips := 0
+		for next < len(transitions) && !transitions[next].at.After(end) {
+			next++
+			flips++
+		}
+		switch {
+		case next == 0:
+			line.WriteString(" ")
+		case flips > 1:
+			line.WriteString("▄")
+		case transitions[next-1].ready:
+			line.WriteString("█")
+		default:
+			line.WriteString("▁")
+		}
+	}
+	return line.String()
+}
+
+// readinessSummary renders the sparkline and transition list for node details
+func (r *readinessTracker) readinessSummary(name string) string {
+	var out strings.Builder
+	transitions := r.get(name)
+	fmt.Fprintf(&out, "Ready history: [%s] %d transitions since %s\n", r.sparkline(name, time.Now()), lo.Max([]int{len(transitions) - 1, 0}), r.start.Format(time.Kitchen))
+	for _, transition := range transitions {
+		fmt.Fprintf(&out, "  %s ready=%t\n", transition.at.Format("15:04:05"), transition.ready)
+	}
+	return out.String()
+}
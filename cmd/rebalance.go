@@ -0,0 +1,203 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/samber/lo"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/bwagner5/kube-demo/pkg/binpack"
+	"github.com/bwagner5/kube-demo/pkg/node"
+	"github.com/bwagner5/kube-demo/pkg/pod"
+	"github.com/bwagner5/kube-demo/pkg/predicates"
+)
+
+// rebalanceSpread is the packing score gap between the fullest and emptiest
+// schedulable nodes below which the cluster counts as balanced
+const rebalanceSpread = 0.2
+
+// maxRebalanceMoves caps how many moves a single suggestion offers
+const maxRebalanceMoves = 5
+
+// podMove suggests evicting a pod from a busy node because it would fit on an idle one
+type podMove struct {
+	pod  *corev1.Pod
+	from string
+	to   string
+}
+
+func (mv podMove) String() string {
+	return fmt.Sprintf("evict %s/%s from %s; it would fit on %s", mv.pod.Namespace, mv.pod.Name, mv.from, mv.to)
+}
+
+// rebalancing is a suggestion being stepped through one move at a time, planned again
+// after each move from the pods evicted and skipped so far
+type rebalancing struct {
+	// moves is the current plan, the first of which is offered
+	moves []podMove
+	// evicted pods count as gone from their nodes until the informer catches up
+	evicted map[types.UID]bool
+	skipped map[types.UID]bool
+}
+
+// reviewed returns how many moves were carried out or skipped
+func (r *rebalancing) reviewed() int {
+	return len(r.evicted) + len(r.skipped)
+}
+
+// rebalanceMoves greedily plans moves from the fullest to the emptiest schedulable
+// node, by packing score, while a move narrows the gap between them. Only pods
+// with a controller to recreate them are moved, and only to nodes the scheduler
+// would place them on as far as taints, node selectors, affinity and free
+// resources go.
+func (m *Model) rebalanceMoves(r *rebalancing) []podMove {
+	var nodes []*corev1.Node
+	var nodePods [][]*corev1.Pod
+	var bins []*binpack.Bin
+	for _, n := range m.getNodes() {
+		if node.IsReady(n) && !node.IsCordoned(n) {
+			pods := lo.Reject(m.getPods(n), func(p *corev1.Pod, _ int) bool { return r.evicted[p.UID] })
+			nodes = append(nodes, n)
+			nodePods = append(nodePods, pods)
+			bins = append(bins, binpack.NewBin(n, pods))
+		}
+	}
+	if len(nodes) < 2 {
+		return nil
+	}
+	score := func(i int) float64 { return binpack.NewPacking(nodes[i], bins[i]).Score() }
+	moved := map[types.UID]bool{}
+	var moves []podMove
+	for len(moves) < maxRebalanceMoves-r.reviewed() {
+		busiest, idlest := 0, 0
+		for i := range nodes {
+			if score(i) > score(busiest) {
+				busiest = i
+			}
+			if score(i) < score(idlest) {
+				idlest = i
+			}
+		}
+		gap := score(busiest) - score(idlest)
+		if gap < rebalanceSpread {
+			break
+		}
+		var best *corev1.Pod
+		bestGap := gap
+		for _, p := range nodePods[busiest] {
+			if moved[p.UID] || r.skipped[p.UID] || p.DeletionTimestamp != nil || !drainable(p) || metav1.GetControllerOf(p) == nil {
+				continue
+			}
+			if len(predicates.Explain(p, nodes[idlest], nodePods[idlest])) > 0 {
+				continue
+			}
+			requests := binpack.WithPodSlot(pod.Requests(p))
+			pair := binpack.Copy([]*binpack.Bin{bins[busiest], bins[idlest]})
+			pod.AddResources(pair[0].Free, requests)
+			binpack.Subtract(pair[1].Free, requests)
+			newGap := math.Abs(binpack.NewPacking(nodes[busiest], pair[0]).Score() - binpack.NewPacking(nodes[idlest], pair[1]).Score())
+			if newGap < bestGap {
+				best, bestGap = p, newGap
+			}
+		}
+		if best == nil {
+			break
+		}
+		requests := binpack.WithPodSlot(pod.Requests(best))
+		pod.AddResources(bins[busiest].Free, requests)
+		binpack.Subtract(bins[idlest].Free, requests)
+		nodePods[busiest] = lo.Without(nodePods[busiest], best)
+		nodePods[idlest] = append(nodePods[idlest], best)
+		moved[best.UID] = true
+		moves = append(moves, podMove{pod: best, from: nodes[busiest].Name, to: nodes[idlest].Name})
+	}
+	return moves
+}
+
+// evictPod evicts the pod through the eviction API so PodDisruptionBudgets apply
+func evictPod(p *corev1.Pod) action {
+	eviction := &policyv1.Eviction{ObjectMeta: metav1.ObjectMeta{Name: p.Name, Namespace: p.Namespace}}
+	return action{
+		description: fmt.Sprintf("evict pod %s/%s", p.Namespace, p.Name),
+		kubectl: fmt.Sprintf(`kubectl create --raw /api/v1/namespaces/%s/pods/%s/eviction -f - <<< '{"apiVersion":"policy/v1","kind":"Eviction","metadata":{"name":%q,"namespace":%q}}'`,
+			p.Namespace, p.Name, p.Name, p.Namespace),
+		requires: []string{"create pods/eviction"},
+		run: func(ctx context.Context, client kubernetes.Interface, dryRun []string) error {
+			eviction := eviction.DeepCopy()
+			eviction.DeleteOptions = &metav1.DeleteOptions{DryRun: dryRun}
+			return retry(ctx, func(ctx context.Context) error {
+				return client.PolicyV1().Evictions(eviction.Namespace).Evict(ctx, eviction)
+			})
+		},
+	}
+}
+
+// toggleRebalance plans rebalancing moves and steps through them, or hides the plan
+func (m *Model) toggleRebalance() tea.Cmd {
+	if m.rebalance != nil {
+		m.rebalance = nil
+		return nil
+	}
+	r := &rebalancing{evicted: map[types.UID]bool{}, skipped: map[types.UID]bool{}}
+	if r.moves = m.rebalanceMoves(r); len(r.moves) == 0 {
+		return m.setStatus("rebalance: no move would narrow the packing gap between nodes")
+	}
+	m.rebalance = r
+	return m.confirmMove()
+}
+
+// confirmMove asks whether to carry out the first planned move. The next one is only offered
+// once the eviction has finished, planned again from the nodes as they are then.
+func (m *Model) confirmMove() tea.Cmd {
+	r := m.rebalance
+	if len(r.moves) == 0 || r.reviewed() >= maxRebalanceMoves {
+		m.rebalance = nil
+		return m.setStatus(fmt.Sprintf("rebalance: %d moves evicted, %d skipped", len(r.evicted), len(r.skipped)))
+	}
+	mv := r.moves[0]
+	next := func(done map[types.UID]bool) tea.Cmd {
+		// the suggestion may have been hidden or replaced meanwhile
+		if m.rebalance != r {
+			return nil
+		}
+		done[mv.pod.UID] = true
+		r.moves = m.rebalanceMoves(r)
+		return m.confirmMove()
+	}
+	evict := evictPod(mv.pod)
+	evict.then = func(err error) tea.Cmd {
+		if err != nil {
+			return next(r.skipped)
+		}
+		return next(r.evicted)
+	}
+	m.menu = &menu{title: fmt.Sprintf("move %d: %s", r.reviewed()+1, mv), items: []menuItem{
+		{key: "y", label: "evict", choose: func() tea.Cmd { return m.runConfirmedAction(evict) }},
+		{key: "s", label: "skip", choose: func() tea.Cmd { return next(r.skipped) }},
+	}}
+	return nil
+}
+
+// rebalanceBadges marks the pods planned to leave or arrive at the node
+func (m *Model) rebalanceBadges(nodeName string) []string {
+	if m.rebalance == nil {
+		return nil
+	}
+	var badges []string
+	for _, mv := range m.rebalance.moves {
+		switch nodeName {
+		case mv.from:
+			badges = append(badges, fmt.Sprintf("→ %s to %s", mv.pod.Name, mv.to))
+		case mv.to:
+			badges = append(badges, fmt.Sprintf("← %s from %s", mv.pod.Name, mv.from))
+		}
+	}
+	return badges
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"sigs.k8s.io/yaml"
+)
+
+// persistInterval is how often the view state is saved for resuming after a crash or restart
+const persistInterval = 5 * time.Second
+
+// crashStackLines is how much of the stack the error view shows, the rest is printed on quit
+const crashStackLines = 12
+
+var crashStyle = lipgloss.NewStyle().Border(lipgloss.DoubleBorder()).BorderForeground(pink).Padding(0, 1)
+
+// persistTick schedules the next save of the view state
+type persistTick struct{}
+
+// sessionStatePath returns the file the view state is saved to in the user's cache directory
+func sessionStatePath() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "kube-demo", "session.yaml")
+}
+
+// loadSessionState reads the view state saved by the previous session, or nil if there is
+// none or it was looking at a different cluster than the current context
+func loadSessionState(opts Options) *viewState {
+	path := sessionStatePath()
+	if path == "" {
+		return nil
+	}
+	state, err := loadState(path)
+	if err != nil || state.Context != contextName(opts.ConfigFlags) {
+		return nil
+	}
+	state.Settings.Name = "resumed"
+	return state
+}
+
+// persist schedules the next save of the view state, only live sessions are saved
+func (m *Model) persist() tea.Cmd {
+	if m.kubeClient == nil {
+		return nil
+	}
+	return tea.Tick(persistInterval, func(time.Time) tea.Msg { return persistTick{} })
+}
+
+// persistState saves the view state if it changed since the last save. It is written to a
+// temporary file first, so a crash while writing leaves the previous state intact.
+func (m *Model) persistState() error {
+	path := sessionStatePath()
+	if path == "" || m.kubeClient == nil {
+		return nil
+	}
+	data, err := yaml.Marshal(m.currentState())
+	if err != nil || bytes.Equal(data, m.persisted) {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return err
+	}
+	m.persisted = data
+	return nil
+}
+
+// recoverCrash switches to the error view after a panic in Update or View. The view state
+// is saved first, so quitting and starting again resumes where the session was.
+func (m *Model) recoverCrash(where string, r interface{}, stack []byte) {
+	m.crash = fmt.Errorf("panic in %s: %v\n\n%s", where, r, stack)
+	// the state may be what panicked, the last periodic save is kept then
+	func() {
+		defer func() { _ = recover() }()
+		_ = m.persistState()
+	}()
+}
+
+// updateCrashView handles keys while the error view is shown
+func (m *Model) updateCrashView(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "ctrl+c", "q":
+		m.fatalErr = m.crash
+		m.shutdown()
+		return tea.Quit
+	case "c", "esc":
+		m.crash = nil
+		m.menu, m.prompt = nil, nil
+		return m.setStatus("recovered from an internal error, q quits if it happens again")
+	}
+	return nil
+}
+
+// resume returns the command that keeps delivering messages like msg, which its update
+// would have returned had it not panicked. Without it the loop stops for good: the UI no
+// longer refreshes and the informer handlers block on the state change channel.
+func (m *Model) resume(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case k8sStateChange:
+		return m.waitForStateChange
+	case refreshTick:
+		if msg.id == m.refreshID {
+			return m.tick()
+		}
+	case persistTick:
+		return m.persist()
+	case metricsMsg:
+		if m.metricsClient != nil {
+			return m.sampleMetrics(metricsInterval)
+		}
+	case retryMsg:
+		return m.waitForRetry
+	case watchErrorMsg:
+		return m.waitForWatchError
+	case throttledMsg:
+		return m.waitForThrottle
+	case taskProgressMsg:
+		return m.waitForTaskProgress
+	case spinner.TickMsg:
+		// the next task started restarts the spinner
+		m.spinning = false
+	}
+	return nil
+}
+
+// crashView renders the error and how to get out of it
+func (m *Model) crashView() string {
+	lines := strings.Split(m.crash.Error(), "\n")
+	if len(lines) > crashStackLines {
+		lines = append(lines[:crashStackLines], "...")
+	}
+	saved := "the view state could not be saved"
+	if path := sessionStatePath(); path != "" && m.persisted != nil {
+		saved = "the view state is saved to " + path + " and restored on the next start"
+	}
+	style := crashStyle
+	if width, _ := m.terminalSize(); width > crashStyle.GetHorizontalBorderSize() {
+		style = style.Width(width - crashStyle.GetHorizontalBorderSize())
+	}
+	return style.Render(strings.Join([]string{
+		pullErrorStyle.Render(programName() + " hit an internal error"),
+		"",
+		strings.Join(lines, "\n"),
+		"",
+		saved,
+		"c continue  q quit and print the full error",
+	}, "\n"))
+}
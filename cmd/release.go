@@ -0,0 +1,151 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/yaml"
+)
+
+// archivePattern matches release archives named kubectl-demo_<os>_<arch>.tar.gz
+var archivePattern = regexp.MustCompile(`^kubectl-demo_([a-z0-9]+)_([a-z0-9]+)\.tar\.gz$`)
+
+// krewPlugin is the subset of the krew v1alpha2 Plugin manifest we generate
+type krewPlugin struct {
+	APIVersion string         `json:"apiVersion"`
+	Kind       string         `json:"kind"`
+	Metadata   krewMetadata   `json:"metadata"`
+	Spec       krewPluginSpec `json:"spec"`
+}
+
+type krewMetadata struct {
+	Name string `json:"name"`
+}
+
+type krewPluginSpec struct {
+	Version          string         `json:"version"`
+	Homepage         string         `json:"homepage"`
+	ShortDescription string         `json:"shortDescription"`
+	Description      string         `json:"description"`
+	Platforms        []krewPlatform `json:"platforms"`
+}
+
+type krewPlatform struct {
+	Selector krewSelector `json:"selector"`
+	URI      string       `json:"uri"`
+	Sha256   string       `json:"sha256"`
+	Bin      string       `json:"bin"`
+}
+
+type krewSelector struct {
+	MatchLabels map[string]string `json:"matchLabels"`
+}
+
+// releaseArtifact is a built archive for a single platform
+type releaseArtifact struct {
+	file   string
+	os     string
+	arch   string
+	sha256 string
+}
+
+// runReleaseManifest implements the release-manifest subcommand: it checksums
+// the archives in the dist directory and writes checksums.txt and a krew manifest next to them
+func runReleaseManifest(args []string) error {
+	flags := pflag.NewFlagSet("release-manifest", pflag.ExitOnError)
+	dist := flags.String("dist", "dist", "directory containing kubectl-demo_<os>_<arch>.tar.gz archives")
+	version := flags.String("version", "", "release version, e.g. v0.1.0 (required)")
+	baseURL := flags.String("base-url", "https://github.com/bwagner5/kube-demo/releases/download", "URL prefix the archives are published under; the version is appended")
+	_ = flags.Parse(args)
+	if *version == "" {
+		return fmt.Errorf("--version is required")
+	}
+	artifacts, err := findArtifacts(*dist)
+	if err != nil {
+		return err
+	}
+	if len(artifacts) == 0 {
+		return fmt.Errorf("no release archives found in %s", *dist)
+	}
+
+	var checksums strings.Builder
+	plugin := krewPlugin{
+		APIVersion: "krew.googlecontainertools.github.com/v1alpha2",
+		Kind:       "Plugin",
+		Metadata:   krewMetadata{Name: "demo"},
+		Spec: krewPluginSpec{
+			Version:          *version,
+			Homepage:         "https://github.com/bwagner5/kube-demo",
+			ShortDescription: "Visualize nodes and pods in a terminal UI",
+			Description:      "Renders cluster nodes as boxes filled with their pods, updating live from informers. Built for demos and troubleshooting.",
+		},
+	}
+	for _, artifact := range artifacts {
+		fmt.Fprintf(&checksums, "%s  %s\n", artifact.sha256, artifact.file)
+		bin := "kubectl-demo"
+		if artifact.os == "windows" {
+			bin += ".exe"
+		}
+		plugin.Spec.Platforms = append(plugin.Spec.Platforms, krewPlatform{
+			Selector: krewSelector{MatchLabels: map[string]string{"os": artifact.os, "arch": artifact.arch}},
+			URI:      fmt.Sprintf("%s/%s/%s", strings.TrimSuffix(*baseURL, "/"), *version, artifact.file),
+			Sha256:   artifact.sha256,
+			Bin:      bin,
+		})
+	}
+	if err := os.WriteFile(filepath.Join(*dist, "checksums.txt"), []byte(checksums.String()), 0644); err != nil {
+		return fmt.Errorf("writing checksums: %w", err)
+	}
+	manifest, err := yaml.Marshal(plugin)
+	if err != nil {
+		return fmt.Errorf("marshaling krew manifest: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(*dist, "demo.yaml"), manifest, 0644); err != nil {
+		return fmt.Errorf("writing krew manifest: %w", err)
+	}
+	fmt.Printf("wrote %d platforms to %s\n", len(artifacts), filepath.Join(*dist, "demo.yaml"))
+	return nil
+}
+
+// findArtifacts checksums every release archive in dir, sorted by file name
+func findArtifacts(dir string) ([]releaseArtifact, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", dir, err)
+	}
+	var artifacts []releaseArtifact
+	for _, entry := range entries {
+		match := archivePattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+		sum, err := sha256File(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		artifacts = append(artifacts, releaseArtifact{file: entry.Name(), os: match[1], arch: match[2], sha256: sum})
+	}
+	sort.Slice(artifacts, func(i, j int) bool { return artifacts[i].file < artifacts[j].file })
+	return artifacts, nil
+}
+
+func sha256File(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	hash := sha256.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return "", fmt.Errorf("hashing %s: %w", path, err)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const benchPodsPerNode = 8
+
+// syntheticCluster builds a store with the given number of Ready nodes, each
+// running benchPodsPerNode pods spread across a handful of namespaces
+func syntheticCluster(b *testing.B, nodes int) ClusterStore {
+	b.Helper()
+	store := NewClusterStore()
+	created := time.Date(2022, 9, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < nodes; i++ {
+		node := &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              fmt.Sprintf("node-%05d", i),
+				UID:               types.UID(fmt.Sprintf("node-uid-%05d", i)),
+				CreationTimestamp: metav1.NewTime(created.Add(time.Duration(i) * time.Second)),
+			},
+			Status: corev1.NodeStatus{
+				Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}},
+				Allocatable: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("4"),
+					corev1.ResourceMemory: resource.MustParse("16Gi"),
+				},
+			},
+		}
+		if err := store.Nodes.Add(node); err != nil {
+			b.Fatal(err)
+		}
+		for j := 0; j < benchPodsPerNode; j++ {
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              fmt.Sprintf("pod-%05d-%d", i, j),
+					Namespace:         fmt.Sprintf("team-%d", j%4),
+					UID:               types.UID(fmt.Sprintf("pod-uid-%05d-%d", i, j)),
+					CreationTimestamp: metav1.NewTime(created.Add(time.Duration(i*benchPodsPerNode+j) * time.Second)),
+				},
+				Spec: corev1.PodSpec{
+					NodeName: node.Name,
+					Containers: []corev1.Container{{
+						Name:  "app",
+						Image: fmt.Sprintf("registry.example.com/app-%d:v1", j),
+						Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{
+							corev1.ResourceCPU:    resource.MustParse("250m"),
+							corev1.ResourceMemory: resource.MustParse("512Mi"),
+						}},
+					}},
+				},
+				Status: corev1.PodStatus{Phase: corev1.PodRunning},
+			}
+			if err := store.Pods.Add(pod); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+	return store
+}
+
+func benchmarkView(b *testing.B, nodes int, view viewMode) {
+	m := newModel(syntheticCluster(b, nodes), Options{HistoryWindow: time.Minute})
+	m.width, m.height = 240, 60
+	m.view = view
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m.View()
+	}
+}
+
+func BenchmarkViewNodes100(b *testing.B)  { benchmarkView(b, 100, viewNodes) }
+func BenchmarkViewNodes1000(b *testing.B) { benchmarkView(b, 1000, viewNodes) }
+func BenchmarkViewNodes5000(b *testing.B) { benchmarkView(b, 5000, viewNodes) }
+
+func BenchmarkViewNamespaces100(b *testing.B)  { benchmarkView(b, 100, viewNamespaces) }
+func BenchmarkViewNamespaces1000(b *testing.B) { benchmarkView(b, 1000, viewNamespaces) }
+func BenchmarkViewNamespaces5000(b *testing.B) { benchmarkView(b, 5000, viewNamespaces) }
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/bwagner5/kube-demo/pkg/format"
+)
+
+var yellow = lipgloss.Color("#FFD23F")
+
+var rescheduleBorder = yellow
+
+// rescheduleHighlight is how long the replacement stays highlighted once it is running
+const rescheduleHighlight = 15 * time.Second
+
+// reschedule tracks the replacement of a deleted pod through its controller
+type reschedule struct {
+	pod       string
+	fromNode  string
+	owner     types.UID
+	ownerName string
+	// siblings are the owner's pods that existed before the delete, so they are not taken for the replacement
+	siblings    map[types.UID]bool
+	deletedAt   time.Time
+	replacement *corev1.Pod
+	landedAt    time.Time
+}
+
+// rescheduleSelectedPod deletes the selected pod and follows its replacement from Pending to Running
+func (m *Model) rescheduleSelectedPod() tea.Cmd {
+	p := m.selectedPodOrNil()
+	if p == nil {
+		return m.setStatus("select a pod with tab first")
+	}
+	owner := metav1.GetControllerOf(p)
+	if owner == nil {
+		return m.setStatus(fmt.Sprintf("%s/%s has no controller to replace it", p.Namespace, p.Name))
+	}
+	r := &reschedule{
+		pod:       fmt.Sprintf("%s/%s", p.Namespace, p.Name),
+		fromNode:  p.Spec.NodeName,
+		owner:     owner.UID,
+		ownerName: fmt.Sprintf("%s/%s", owner.Kind, owner.Name),
+		siblings:  map[types.UID]bool{},
+	}
+	for _, obj := range m.store.Pods.List() {
+		if sibling := obj.(*corev1.Pod); controlledBy(sibling, r.owner) {
+			r.siblings[sibling.UID] = true
+		}
+	}
+	a := deletePod(p)
+	a.description = "reschedule pod " + r.pod
+	a.onSuccess = func() {
+		r.deletedAt = time.Now()
+		m.rescheduling = r
+		m.recompute()
+	}
+	return m.runAction(a)
+}
+
+func controlledBy(p *corev1.Pod, owner types.UID) bool {
+	ref := metav1.GetControllerOf(p)
+	return ref != nil && ref.UID == owner
+}
+
+// trackReschedule picks up the newest pod created by the owner since the delete
+// and notes when it is running, dropping the tracker once the highlight expires
+func (m *Model) trackReschedule() {
+	r := m.rescheduling
+	if r == nil {
+		return
+	}
+	if !r.landedAt.IsZero() && time.Since(r.landedAt) > rescheduleHighlight {
+		m.rescheduling = nil
+		return
+	}
+	for _, obj := range m.store.Pods.List() {
+		p := obj.(*corev1.Pod)
+		if r.siblings[p.UID] || !controlledBy(p, r.owner) {
+			continue
+		}
+		if r.replacement == nil || r.replacement.UID == p.UID || p.CreationTimestamp.After(r.replacement.CreationTimestamp.Time) {
+			r.replacement = p
+		}
+	}
+	if r.replacement != nil && r.landedAt.IsZero() && r.replacement.Status.Phase == corev1.PodRunning {
+		r.landedAt = time.Now()
+	}
+}
+
+// isReplacement returns true for the pod replacing a rescheduled pod
+func (m *Model) isReplacement(p *corev1.Pod) bool {
+	return m.rescheduling != nil && m.rescheduling.replacement != nil && m.rescheduling.replacement.UID == p.UID
+}
+
+// landedOn returns true if the replacement of a rescheduled pod is bound to the node
+func (m *Model) landedOn(nodeName string) bool {
+	return m.rescheduling != nil && m.rescheduling.replacement != nil && m.rescheduling.replacement.Spec.NodeName == nodeName
+}
+
+func (r *reschedule) summary() string {
+	line := fmt.Sprintf("rescheduling %s from %s: ", r.pod, r.fromNode)
+	switch {
+	case r.replacement == nil:
+		return line + fmt.Sprintf("waiting for %s to create a replacement", r.ownerName)
+	case !r.landedAt.IsZero():
+		return line + fmt.Sprintf("%s running on %s after %s", r.replacement.Name, r.replacement.Spec.NodeName, format.Precise(r.landedAt.Sub(r.deletedAt)))
+	case r.replacement.Spec.NodeName == "":
+		return line + fmt.Sprintf("%s pending for %s", r.replacement.Name, format.Precise(time.Since(r.deletedAt)))
+	default:
+		return line + fmt.Sprintf("%s %s on %s", r.replacement.Name, r.replacement.Status.Phase, r.replacement.Spec.NodeName)
+	}
+}
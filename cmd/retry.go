@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// callTimeout bounds a single attempt of a direct API call
+const callTimeout = 10 * time.Second
+
+// retryBackoff spaces out attempts at a transiently failing API call
+var retryBackoff = wait.Backoff{Duration: 500 * time.Millisecond, Factor: 2, Jitter: 0.1, Steps: 5}
+
+// retryMsg reports a transient API failure that is being retried
+type retryMsg struct {
+	attempt int
+	err     error
+}
+
+type retryNoticesKey struct{}
+
+// callContext returns a context for background API calls whose retries are
+// reported to the event loop as status toasts
+func (m *Model) callContext(timeout time.Duration) (context.Context, context.CancelFunc) {
+	ctx := context.WithValue(context.Background(), retryNoticesKey{}, m.retryCh)
+	return context.WithTimeout(ctx, timeout)
+}
+
+// waitForRetry delivers retry notices to the event loop one at a time
+func (m *Model) waitForRetry() tea.Msg {
+	select {
+	case msg := <-m.retryCh:
+		return msg
+	case <-m.stopCh:
+		return nil
+	}
+}
+
+// retry calls fn with a per-attempt timeout, retrying with exponential backoff
+// while it fails with a transient error and ctx has not expired
+func retry(ctx context.Context, fn func(ctx context.Context) error) error {
+	backoff := retryBackoff
+	for attempt := 1; ; attempt++ {
+		callCtx, cancel := context.WithTimeout(ctx, callTimeout)
+		err := fn(callCtx)
+		cancel()
+		if err == nil || !isTransient(err) || backoff.Steps <= 1 || ctx.Err() != nil {
+			return err
+		}
+		if notices, ok := ctx.Value(retryNoticesKey{}).(chan retryMsg); ok {
+			select {
+			case notices <- retryMsg{attempt: attempt, err: err}:
+			default:
+			}
+		}
+		select {
+		case <-time.After(backoff.Step()):
+		case <-ctx.Done():
+			return err
+		}
+	}
+}
+
+// isTransient returns true for errors worth retrying: throttling, server
+// unavailability, timeouts and dropped connections
+func isTransient(err error) bool {
+	var netErr net.Error
+	var responseErr *responseError
+	switch {
+	case apierrors.IsTooManyRequests(err), apierrors.IsServerTimeout(err), apierrors.IsTimeout(err),
+		apierrors.IsServiceUnavailable(err), apierrors.IsInternalError(err):
+		return true
+	case errors.Is(err, context.DeadlineExceeded):
+		return true
+	case utilnet.IsConnectionReset(err), utilnet.IsConnectionRefused(err), utilnet.IsProbableEOF(err):
+		return true
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return true
+	case errors.As(err, &responseErr):
+		return responseErr.transient()
+	}
+	return false
+}
+
+func (msg retryMsg) String() string {
+	return fmt.Sprintf("retrying (attempt %d failed: %v)", msg.attempt, msg.err)
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/lipgloss"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/bwagner5/kube-demo/pkg/pod"
+)
+
+var blue = lipgloss.Color("#5DA9E9")
+
+var underRequestsBorder = blue
+var overRequestsBorder = red
+
+// usageFit compares a pod's latest usage with its requests
+type usageFit int
+
+const (
+	usageUnknown usageFit = iota
+	usageWithinRequests
+	// usageUnderRequests means both CPU and memory are far below requests: over-provisioned
+	usageUnderRequests
+	// usageOverRequests means CPU or memory is above requests
+	usageOverRequests
+)
+
+// usageFitOf classifies the pod's latest usage sample against its requests
+// using the --underuse-ratio and --overuse-ratio thresholds
+func (m *Model) usageFitOf(p *corev1.Pod) usageFit {
+	samples := m.podUsage[p.Namespace+"/"+p.Name]
+	if len(samples) == 0 {
+		return usageUnknown
+	}
+	latest := samples[len(samples)-1]
+	requests := pod.Requests(p)
+	cpu := ratio(latest.cpuMillis, requests.Cpu().MilliValue())
+	memory := ratio(latest.memoryBytes, requests.Memory().Value())
+	switch {
+	case cpu < 0 && memory < 0:
+		return usageUnknown
+	case cpu > m.overuseRatio || memory > m.overuseRatio:
+		return usageOverRequests
+	case cpu < m.underuseRatio && memory < m.underuseRatio:
+		return usageUnderRequests
+	}
+	return usageWithinRequests
+}
+
+// ratio returns usage/request, or -1 when nothing is requested
+func ratio(usage int64, request int64) float64 {
+	if request == 0 {
+		return -1
+	}
+	return float64(usage) / float64(request)
+}
+
+// rightsizingBorder colors pods by how their usage compares to their requests while the mode is on
+func (m *Model) rightsizingBorder(p *corev1.Pod, color lipgloss.TerminalColor) lipgloss.TerminalColor {
+	if !m.rightsizing {
+		return color
+	}
+	switch m.usageFitOf(p) {
+	case usageUnderRequests:
+		return underRequestsBorder
+	case usageOverRequests:
+		return overRequestsBorder
+	}
+	return color
+}
+
+// rightsizingMarker is the monochrome stand-in for rightsizingBorder
+func (m *Model) rightsizingMarker(p *corev1.Pod, marker string) string {
+	if !m.rightsizing {
+		return marker
+	}
+	switch m.usageFitOf(p) {
+	case usageUnderRequests:
+		return "↓"
+	case usageOverRequests:
+		return "↑"
+	}
+	return marker
+}
+
+// rightsizingSummary counts the flagged pods for the status line
+func (m *Model) rightsizingSummary() string {
+	if len(m.podUsage) == 0 {
+		return "rightsizing: waiting for metrics-server samples"
+	}
+	under, over := 0, 0
+	for _, p := range m.listPods() {
+		switch m.usageFitOf(p) {
+		case usageUnderRequests:
+			under++
+		case usageOverRequests:
+			over++
+		}
+	}
+	return fmt.Sprintf("rightsizing: %d pods use under %.0f%% of requests (%s), %d over %.0f%% (%s)",
+		under, m.underuseRatio*100, m.legend("blue", "↓"), over, m.overuseRatio*100, m.legend("red", "↑"))
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/samber/lo"
+	"github.com/spf13/pflag"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// sandboxNamespace holds the demo workload of a sandbox cluster
+const sandboxNamespace = "kube-demo"
+
+// sandboxSeedTimeout bounds seeding the demo workload once the cluster is up
+const sandboxSeedTimeout = 2 * time.Minute
+
+// kwokTaint is the taint kwok puts on its fake nodes
+const kwokTaint = "kwok.x-k8s.io/node"
+
+// sandboxWorkload is a deployment of the demo workload
+type sandboxWorkload struct {
+	name     string
+	replicas int32
+	cpu      string
+	memory   string
+}
+
+// sandboxWorkloads are sized so that the pods of a three node cluster are unevenly packed
+var sandboxWorkloads = []sandboxWorkload{
+	{"web", 6, "250m", "256Mi"},
+	{"api", 4, "500m", "512Mi"},
+	{"worker", 3, "1", "1Gi"},
+	{"cache", 2, "100m", "2Gi"},
+}
+
+// runSandbox implements the sandbox subcommand: it creates a local kind or kwok cluster, seeds
+// the demo workload and returns the kubeconfig to visualize it with, along with the arguments
+// after -- that are passed on to the visualizer
+func runSandbox(args []string) (string, []string, error) {
+	flags := pflag.NewFlagSet("sandbox", pflag.ExitOnError)
+	provider := flags.String("provider", "", "kind or kwok (defaults to kwok if kwokctl is installed, otherwise kind)")
+	name := flags.String("name", "kube-demo", "name of the sandbox cluster, reused if it already exists")
+	nodes := flags.Int("nodes", 3, "number of worker nodes")
+	_ = flags.Parse(args)
+	if *provider == "" {
+		*provider = "kind"
+		if _, err := exec.LookPath("kwokctl"); err == nil {
+			*provider = "kwok"
+		}
+	}
+	if *nodes < 1 {
+		return "", nil, fmt.Errorf("--nodes must be at least 1")
+	}
+	kubeconfig := filepath.Join(os.TempDir(), fmt.Sprintf("kube-demo-sandbox-%s-%s.kubeconfig", *provider, *name))
+	var err error
+	switch *provider {
+	case "kind":
+		err = createKindCluster(*name, *nodes, kubeconfig)
+	case "kwok":
+		err = createKwokCluster(*name, *nodes, kubeconfig)
+	default:
+		err = fmt.Errorf("unknown provider %q, expected kind or kwok", *provider)
+	}
+	if err != nil {
+		return "", nil, err
+	}
+	if err := seedSandbox(kubeconfig, "sandbox-"+*name); err != nil {
+		return "", nil, fmt.Errorf("seeding the demo workload: %w", err)
+	}
+	fmt.Printf("sandbox cluster %q is ready, delete it with: %s\n", *name, sandboxDeleteCommand(*provider, *name))
+	return kubeconfig, flags.Args(), nil
+}
+
+// runTool runs a command line tool, streaming its output so cluster creation progress shows
+func runTool(name string, args ...string) error {
+	if _, err := exec.LookPath(name); err != nil {
+		return fmt.Errorf("%s is not installed: %w", name, err)
+	}
+	cmd := exec.Command(name, args...)
+	cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s %s: %w", name, strings.Join(args, " "), err)
+	}
+	return nil
+}
+
+// createKindCluster creates a kind cluster with a control plane and the worker nodes, or
+// exports the kubeconfig of an existing one
+func createKindCluster(name string, nodes int, kubeconfig string) error {
+	if clusters, err := exec.Command("kind", "get", "clusters").Output(); err == nil {
+		for _, cluster := range strings.Fields(string(clusters)) {
+			if cluster == name {
+				return runTool("kind", "export", "kubeconfig", "--name", name, "--kubeconfig", kubeconfig)
+			}
+		}
+	}
+	config := "kind: Cluster\napiVersion: kind.x-k8s.io/v1alpha4\nnodes:\n- role: control-plane\n" + strings.Repeat("- role: worker\n", nodes)
+	configFile, err := os.CreateTemp("", "kube-demo-kind-*.yaml")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(configFile.Name())
+	if _, err := configFile.WriteString(config); err != nil {
+		return err
+	}
+	configFile.Close()
+	return runTool("kind", "create", "cluster", "--name", name, "--config", configFile.Name(), "--kubeconfig", kubeconfig, "--wait", "2m")
+}
+
+// createKwokCluster creates a kwok cluster, reusing an existing one, and scales it to the number of fake nodes
+func createKwokCluster(name string, nodes int, kubeconfig string) error {
+	if err := runTool("kwokctl", "create", "cluster", "--name", name, "--kubeconfig", kubeconfig); err != nil {
+		if _, statErr := os.Stat(kubeconfig); statErr != nil {
+			return err
+		}
+		fmt.Printf("reusing kwok cluster %q\n", name)
+	}
+	return runTool("kwokctl", "scale", "node", "--name", name, "--replicas", fmt.Sprint(nodes))
+}
+
+// sandboxDeleteCommand is how to delete the sandbox cluster when done
+func sandboxDeleteCommand(provider string, name string) string {
+	if provider == "kwok" {
+		return "kwokctl delete cluster --name " + name
+	}
+	return "kind delete cluster --name " + name
+}
+
+// seedSandbox creates the demo namespace and workload labeled with the session, leaving existing objects as they are
+func seedSandbox(kubeconfig string, session string) error {
+	config, err := clientcmd.BuildConfigFromFlags("", kubeconfig)
+	if err != nil {
+		return err
+	}
+	client, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), sandboxSeedTimeout)
+	defer cancel()
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: sandboxNamespace, Labels: map[string]string{sessionLabel: session}}}
+	if err := retry(ctx, func(ctx context.Context) error {
+		_, err := client.CoreV1().Namespaces().Create(ctx, namespace, metav1.CreateOptions{})
+		if apierrors.IsAlreadyExists(err) {
+			return nil
+		}
+		return err
+	}); err != nil {
+		return err
+	}
+	for _, workload := range sandboxWorkloads {
+		deployment := sandboxDeployment(workload)
+		deployment.Labels[sessionLabel] = session
+		if err := retry(ctx, func(ctx context.Context) error {
+			_, err := client.AppsV1().Deployments(sandboxNamespace).Create(ctx, deployment, metav1.CreateOptions{})
+			if apierrors.IsAlreadyExists(err) {
+				return nil
+			}
+			return err
+		}); err != nil {
+			return fmt.Errorf("creating deployment %s: %w", workload.name, err)
+		}
+	}
+	return nil
+}
+
+// sandboxDeployment returns the deployment of a demo workload. Its pods tolerate the
+// kwok taint so they land on fake nodes too.
+func sandboxDeployment(workload sandboxWorkload) *appsv1.Deployment {
+	labels := map[string]string{"app.kubernetes.io/name": workload.name, "app.kubernetes.io/part-of": "kube-demo"}
+	replicas := workload.replicas
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: workload.name, Namespace: sandboxNamespace, Labels: lo.Assign(labels)},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{
+						Name:  workload.name,
+						Image: "registry.k8s.io/pause:3.9",
+						Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{
+							corev1.ResourceCPU:    resource.MustParse(workload.cpu),
+							corev1.ResourceMemory: resource.MustParse(workload.memory),
+						}},
+					}},
+					Tolerations: []corev1.Toleration{{Key: kwokTaint, Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule}},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/samber/lo"
+)
+
+// scrollRows joins the rows of a grid or table into at most height lines, scrolled so the
+// selected row stays in sight. headings[i] is the index of the heading row i sits under, such
+// as its pool heading or the column headers of a table, or -1 if it has none. A heading that
+// scrolled out of sight is pinned on top. The offset of each view is kept between frames, so
+// the view only moves once the selection leaves it.
+func (m *Model) scrollRows(rows []string, headings []int, selected int, height int) string {
+	joined := lipgloss.JoinVertical(lipgloss.Left, rows...)
+	total := lipgloss.Height(joined)
+	if height <= 0 || total <= height || selected < 0 || selected >= len(rows) {
+		m.scrollOffsets[m.view] = 0
+		return joined
+	}
+	lines := strings.Split(joined, "\n")
+	starts := make([]int, len(rows)+1)
+	for i, row := range rows {
+		starts[i+1] = starts[i] + lipgloss.Height(row)
+	}
+	// the selection must stay in sight below the heading pinned above it
+	window := height
+	if h := headings[selected]; h >= 0 && starts[h+1]-starts[h] < height {
+		window -= starts[h+1] - starts[h]
+	}
+	offset := scrollOffset(m.scrollOffsets[m.view], total, window, starts[selected], starts[selected+1])
+	m.scrollOffsets[m.view] = offset
+	top := 0
+	for top+1 < len(rows) && starts[top+1] <= offset {
+		top++
+	}
+	var shown []string
+	if h := headings[top]; h >= 0 && starts[h] < offset {
+		shown = append(shown, lines[starts[h]:starts[h+1]]...)
+	}
+	end := offset + height - len(shown)
+	if end > total {
+		end = total
+	}
+	return strings.Join(append(shown, lines[offset:end]...), "\n")
+}
+
+// rowOffset is the first line shown of a view rendered row by row, as a row and a line of it
+type rowOffset struct {
+	row, line int
+}
+
+// scrollRenderedRows is scrollRows for grids too large to render whole: render renders row
+// r of the count rows on demand, and only the rows in sight are rendered, however far the
+// selection jumped. It scrolls line by line like scrollRows.
+func (m *Model) scrollRenderedRows(count int, render func(r int) string, headings []int, selected int, height int) string {
+	rendered := map[int][]string{}
+	lines := func(r int) []string {
+		if _, ok := rendered[r]; !ok {
+			rendered[r] = strings.Split(render(r), "\n")
+		}
+		return rendered[r]
+	}
+	// up moves the offset n lines up, stopping at the first line
+	up := func(offset rowOffset, n int) rowOffset {
+		for n > offset.line && offset.row > 0 {
+			n -= offset.line
+			offset.row--
+			offset.line = len(lines(offset.row))
+		}
+		return rowOffset{row: offset.row, line: lo.Max([]int{offset.line - n, 0})}
+	}
+	if height <= 0 || selected < 0 || selected >= count {
+		delete(m.rowOffsets, m.view)
+		var all []string
+		for r := 0; r < count; r++ {
+			all = append(all, lines(r)...)
+		}
+		return lipgloss.JoinVertical(lipgloss.Left, all...)
+	}
+	// the selection must stay in sight below the heading pinned above it
+	window := height
+	if h := headings[selected]; h >= 0 && len(lines(h)) < height {
+		window -= len(lines(h))
+	}
+	offset, selectedLines := m.rowOffsets[m.view], len(lines(selected))
+	if selectedLines >= window || selected < offset.row || (selected == offset.row && offset.line > 0) {
+		offset = rowOffset{row: selected}
+	} else {
+		// count the lines from the offset to the end of the selection only as far as the window
+		end, r := selectedLines, selected
+		for r > offset.row && end <= window+offset.line {
+			r--
+			end += len(lines(r))
+		}
+		if r > offset.row || end-offset.line > window {
+			offset = up(rowOffset{row: selected}, window-selectedLines)
+		}
+	}
+	// at the end of the grid, scroll up for the view to be full
+	shown := len(lines(offset.row)) - offset.line
+	for r := offset.row + 1; r < count && shown < window; r++ {
+		shown += len(lines(r))
+	}
+	if shown < window {
+		offset = up(offset, window-shown)
+	}
+	m.rowOffsets[m.view] = offset
+	var view []string
+	if h := headings[offset.row]; h >= 0 && (h < offset.row || offset.line > 0) {
+		view = append(view, lines(h)...)
+	}
+	view = append(view, lines(offset.row)[offset.line:]...)
+	for r := offset.row + 1; r < count && len(view) < height; r++ {
+		view = append(view, lines(r)...)
+	}
+	if len(view) > height {
+		view = view[:height]
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, view...)
+}
+
+// scrollOffset moves the first line shown of a view of height lines just enough for the
+// lines from start to end to be in sight, showing the top of them if they do not fit
+func scrollOffset(offset, total, height, start, end int) int {
+	switch {
+	case end-start >= height || start < offset:
+		offset = start
+	case end > offset+height:
+		offset = end - height
+	}
+	if offset > total-height {
+		offset = total - height
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return offset
+}
+
+// scrollTable scrolls a table rendered with a header line to keep the selected row in sight,
+// the header staying pinned on top
+func (m *Model) scrollTable(table string, selected int, height int) string {
+	rows := strings.Split(table, "\n")
+	headings := make([]int, len(rows))
+	return m.scrollRows(rows, headings, selected+1, height)
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"github.com/charmbracelet/lipgloss"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/bwagner5/kube-demo/pkg/pod"
+)
+
+var privilegeStyle = lipgloss.NewStyle().Foreground(red).Bold(true)
+
+// privilegeBadge marks a pod box with "!" for privileged containers, "H" for
+// host namespaces and "R" for running as root, most severe first
+func privilegeBadge(p *corev1.Pod) string {
+	privileges := pod.Privileges(p)
+	if len(privileges) == 0 {
+		return ""
+	}
+	switch privileges[0] {
+	case "privileged":
+		return privilegeStyle.Render("!")
+	case "root":
+		return privilegeStyle.Render("R")
+	}
+	return privilegeStyle.Render("H")
+}
+
+// shownPods returns the node's pods the grid shows and the cursor moves between,
+// only those with elevated privileges while the privileged filter is on
+func (m *Model) shownPods(n *corev1.Node) []*corev1.Pod {
+	pods := m.getPods(n)
+	if !m.privilegedOnly {
+		return pods
+	}
+	var shown []*corev1.Pod
+	for _, p := range pods {
+		if len(pod.Privileges(p)) > 0 {
+			shown = append(shown, p)
+		}
+	}
+	return shown
+}
+
+// togglePrivilegedOnly shows only pods with elevated privileges, or every pod again
+func (m *Model) togglePrivilegedOnly() {
+	m.privilegedOnly = !m.privilegedOnly
+	m.selectedPod = 0
+}
@@ -0,0 +1,248 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// rbacTimeout bounds resolving a ServiceAccount's bindings and roles
+const rbacTimeout = 15 * time.Second
+
+// grant is a role rule that applies to a ServiceAccount through a binding
+type grant struct {
+	binding string
+	role    string
+	rule    rbacv1.PolicyRule
+}
+
+// serviceAccountAccess is what a pod's ServiceAccount is allowed to do
+type serviceAccountAccess struct {
+	// mounted is false when the pod or its ServiceAccount opts out of the API token
+	mounted bool
+	missing bool
+	grants  []grant
+}
+
+// serviceAccountMsg delivers the resolved access of the ServiceAccount namespace/name
+type serviceAccountMsg struct {
+	key    string
+	access serviceAccountAccess
+	err    error
+}
+
+// fetchServiceAccountAccess resolves the RoleBindings and ClusterRoleBindings of the
+// pod's ServiceAccount into the rules they grant, in the background
+func (m *Model) fetchServiceAccountAccess(p *corev1.Pod) tea.Cmd {
+	if m.kubeClient == nil {
+		return nil
+	}
+	client := m.kubeClient
+	namespace, name := p.Namespace, serviceAccountName(p)
+	podAutomount := p.Spec.AutomountServiceAccountToken
+	return func() tea.Msg {
+		ctx, cancel := m.callContext(rbacTimeout)
+		defer cancel()
+		access, err := resolveServiceAccountAccess(ctx, client, namespace, name)
+		if podAutomount != nil {
+			access.mounted = *podAutomount
+		}
+		return serviceAccountMsg{key: namespace + "/" + name, access: access, err: err}
+	}
+}
+
+func resolveServiceAccountAccess(ctx context.Context, client kubernetes.Interface, namespace string, name string) (serviceAccountAccess, error) {
+	access := serviceAccountAccess{mounted: true}
+	var sa *corev1.ServiceAccount
+	err := retry(ctx, func(ctx context.Context) (err error) {
+		sa, err = client.CoreV1().ServiceAccounts(namespace).Get(ctx, name, metav1.GetOptions{})
+		return err
+	})
+	switch {
+	case apierrors.IsNotFound(err):
+		access.missing = true
+	case err != nil:
+		return access, err
+	case sa.AutomountServiceAccountToken != nil:
+		access.mounted = *sa.AutomountServiceAccountToken
+	}
+	var roleBindings *rbacv1.RoleBindingList
+	if err := retry(ctx, func(ctx context.Context) (err error) {
+		roleBindings, err = client.RbacV1().RoleBindings(namespace).List(ctx, metav1.ListOptions{})
+		return err
+	}); err != nil {
+		return access, err
+	}
+	var clusterRoleBindings *rbacv1.ClusterRoleBindingList
+	if err := retry(ctx, func(ctx context.Context) (err error) {
+		clusterRoleBindings, err = client.RbacV1().ClusterRoleBindings().List(ctx, metav1.ListOptions{})
+		return err
+	}); err != nil {
+		return access, err
+	}
+	for _, binding := range roleBindings.Items {
+		if !bindsServiceAccount(binding.Subjects, namespace, name) {
+			continue
+		}
+		rules, err := roleRules(ctx, client, namespace, binding.RoleRef)
+		if err != nil {
+			return access, err
+		}
+		for _, rule := range rules {
+			access.grants = append(access.grants, grant{binding: "RoleBinding/" + binding.Name, role: binding.RoleRef.Kind + "/" + binding.RoleRef.Name, rule: rule})
+		}
+	}
+	for _, binding := range clusterRoleBindings.Items {
+		if !bindsServiceAccount(binding.Subjects, namespace, name) {
+			continue
+		}
+		rules, err := roleRules(ctx, client, "", binding.RoleRef)
+		if err != nil {
+			return access, err
+		}
+		for _, rule := range rules {
+			access.grants = append(access.grants, grant{binding: "ClusterRoleBinding/" + binding.Name, role: "ClusterRole/" + binding.RoleRef.Name, rule: rule})
+		}
+	}
+	return access, nil
+}
+
+// bindsServiceAccount returns true if the subjects include the ServiceAccount or a group every ServiceAccount in its namespace belongs to
+func bindsServiceAccount(subjects []rbacv1.Subject, namespace string, name string) bool {
+	for _, subject := range subjects {
+		switch {
+		case subject.Kind == rbacv1.ServiceAccountKind && subject.Name == name && subject.Namespace == namespace:
+			return true
+		case subject.Kind == rbacv1.GroupKind && (subject.Name == "system:serviceaccounts" || subject.Name == "system:serviceaccounts:"+namespace || subject.Name == "system:authenticated"):
+			return true
+		}
+	}
+	return false
+}
+
+// roleRules looks up the rules of the Role or ClusterRole a binding refers to. A
+// missing role grants nothing, as in the authorizer.
+func roleRules(ctx context.Context, client kubernetes.Interface, namespace string, ref rbacv1.RoleRef) ([]rbacv1.PolicyRule, error) {
+	var rules []rbacv1.PolicyRule
+	err := retry(ctx, func(ctx context.Context) error {
+		if ref.Kind == "Role" {
+			role, err := client.RbacV1().Roles(namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+			if err == nil {
+				rules = role.Rules
+			}
+			return err
+		}
+		role, err := client.RbacV1().ClusterRoles().Get(ctx, ref.Name, metav1.GetOptions{})
+		if err == nil {
+			rules = role.Rules
+		}
+		return err
+	})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	return rules, err
+}
+
+func serviceAccountName(p *corev1.Pod) string {
+	if p.Spec.ServiceAccountName == "" {
+		return "default"
+	}
+	return p.Spec.ServiceAccountName
+}
+
+// recordServiceAccountAccess stores resolved access, or the reason it could not be resolved
+func (m *Model) recordServiceAccountAccess(msg serviceAccountMsg) {
+	if msg.err != nil {
+		m.serviceAccountErrs[msg.key] = msg.err
+		return
+	}
+	delete(m.serviceAccountErrs, msg.key)
+	m.serviceAccounts[msg.key] = msg.access
+}
+
+// serviceAccountSummary renders the pod's ServiceAccount and its effective permissions for pod details
+func (m *Model) serviceAccountSummary(p *corev1.Pod) string {
+	key := p.Namespace + "/" + serviceAccountName(p)
+	var out strings.Builder
+	fmt.Fprintf(&out, "Service Account %s:\n", key)
+	access, ok := m.serviceAccounts[key]
+	switch {
+	case m.kubeClient == nil:
+		out.WriteString("  <unavailable offline>\n")
+		return out.String()
+	case m.serviceAccountErrs[key] != nil:
+		fmt.Fprintf(&out, "  could not resolve permissions: %v\n", m.serviceAccountErrs[key])
+		return out.String()
+	case !ok:
+		out.WriteString("  loading...\n")
+		return out.String()
+	case access.missing:
+		out.WriteString("  ServiceAccount does not exist\n")
+	}
+	if !access.mounted {
+		out.WriteString("  API token not mounted, the permissions below are unused by the pod\n")
+	}
+	if len(access.grants) == 0 {
+		out.WriteString("  no RoleBindings or ClusterRoleBindings grant it permissions\n")
+		return out.String()
+	}
+	w := tabwriter.NewWriter(&out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "  VERBS\tRESOURCES\tVIA\t")
+	for _, g := range sortedGrants(access.grants) {
+		fmt.Fprintf(w, "  %s\t%s\t%s (%s)\t\n", strings.Join(g.rule.Verbs, ","), ruleResources(g.rule), g.binding, g.role)
+	}
+	w.Flush()
+	return out.String()
+}
+
+// ruleResources renders the resources a rule covers as resource.group[/name,...]
+func ruleResources(rule rbacv1.PolicyRule) string {
+	if len(rule.NonResourceURLs) > 0 {
+		return strings.Join(rule.NonResourceURLs, ",")
+	}
+	var resources []string
+	for _, group := range rule.APIGroups {
+		for _, resource := range rule.Resources {
+			if group != "" {
+				resource += "." + group
+			}
+			resources = append(resources, resource)
+		}
+	}
+	out := strings.Join(resources, ",")
+	if len(rule.ResourceNames) > 0 {
+		out += "/" + strings.Join(rule.ResourceNames, ",")
+	}
+	return out
+}
+
+// sortedGrants orders grants with wildcards first, since they matter most, then by resources
+func sortedGrants(grants []grant) []grant {
+	sorted := append([]grant(nil), grants...)
+	wildcard := func(g grant) bool {
+		for _, verb := range g.rule.Verbs {
+			if verb == rbacv1.VerbAll {
+				return true
+			}
+		}
+		return false
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if wildcard(sorted[i]) != wildcard(sorted[j]) {
+			return wildcard(sorted[i])
+		}
+		return ruleResources(sorted[i].rule) < ruleResources(sorted[j].rule)
+	})
+	return sorted
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/charmbracelet/lipgloss"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+var red = lipgloss.Color("#E84855")
+
+var servingBorder = green
+var notServingBorder = red
+
+// endpointState is a pod's membership in the highlighted Service's endpoints
+type endpointState int
+
+const (
+	notEndpoint endpointState = iota
+	endpointServing
+	endpointNotReady
+)
+
+// serviceEndpoints summarizes the EndpointSlices of a Service
+type serviceEndpoints struct {
+	service  *corev1.Service
+	ready    int
+	notReady int
+	// pods maps the UIDs of endpoint pods to whether they are ready
+	pods map[types.UID]bool
+}
+
+// getServices returns the Services with their endpoints, sorted by namespace and name
+func (m *Model) getServices() []serviceEndpoints {
+	byKey := map[string]*serviceEndpoints{}
+	var services []*serviceEndpoints
+	for _, obj := range m.store.Services.List() {
+		svc := obj.(*corev1.Service)
+		s := &serviceEndpoints{service: svc, pods: map[types.UID]bool{}}
+		byKey[svc.Namespace+"/"+svc.Name] = s
+		services = append(services, s)
+	}
+	for _, obj := range m.store.EndpointSlices.List() {
+		slice := obj.(*discoveryv1.EndpointSlice)
+		s, ok := byKey[slice.Namespace+"/"+slice.Labels[discoveryv1.LabelServiceName]]
+		if !ok {
+			continue
+		}
+		for _, endpoint := range slice.Endpoints {
+			// a nil ready condition means unknown, which consumers treat as ready
+			ready := endpoint.Conditions.Ready == nil || *endpoint.Conditions.Ready
+			if ready {
+				s.ready++
+			} else {
+				s.notReady++
+			}
+			if endpoint.TargetRef != nil && endpoint.TargetRef.Kind == "Pod" {
+				s.pods[endpoint.TargetRef.UID] = ready
+			}
+		}
+	}
+	sort.Slice(services, func(i, j int) bool {
+		if services[i].service.Namespace != services[j].service.Namespace {
+			return services[i].service.Namespace < services[j].service.Namespace
+		}
+		return services[i].service.Name < services[j].service.Name
+	})
+	result := make([]serviceEndpoints, 0, len(services))
+	for _, s := range services {
+		result = append(result, *s)
+	}
+	return result
+}
+
+// services renders the list of Services and their endpoint readiness
+func (m *Model) services() string {
+	services := m.getServices()
+	if len(services) == 0 {
+		return "No services"
+	}
+	var table strings.Builder
+	w := tabwriter.NewWriter(&table, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SERVICE\tTYPE\tREADY\tNOT READY\t")
+	for _, s := range services {
+		name := fmt.Sprintf("%s/%s", s.service.Namespace, s.service.Name)
+		if name == m.highlightService {
+			name = "* " + name
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t\n", name, s.service.Spec.Type, s.ready, s.notReady)
+	}
+	w.Flush()
+	lines := strings.Split(strings.TrimRight(table.String(), "\n"), "\n")
+	lines[m.selectedService+1] = selectedRowStyle.Render(lines[m.selectedService+1])
+	return strings.Join(lines, "\n")
+}
+
+// toggleServiceHighlight highlights the selected Service's endpoint pods on the
+// node grid, or clears the highlight if it is already shown
+func (m *Model) toggleServiceHighlight() {
+	services := m.getServices()
+	if len(services) == 0 {
+		return
+	}
+	s := services[m.selectedService].service
+	key := s.Namespace + "/" + s.Name
+	if m.highlightService == key {
+		m.highlightService = ""
+	} else {
+		m.highlightService = key
+		m.toggleView(viewServices)
+	}
+	m.trackServiceHighlight()
+}
+
+// endpointState returns whether the pod backs the highlighted Service and is ready
+func (m *Model) endpointState(p *corev1.Pod) endpointState {
+	if m.highlightService == "" {
+		return notEndpoint
+	}
+	ready, ok := m.highlighted.pods[p.UID]
+	switch {
+	case !ok:
+		return notEndpoint
+	case ready:
+		return endpointServing
+	default:
+		return endpointNotReady
+	}
+}
+
+// trackServiceHighlight refreshes the endpoints of the highlighted Service
+func (m *Model) trackServiceHighlight() {
+	m.highlighted = serviceEndpoints{}
+	for _, s := range m.getServices() {
+		if s.service.Namespace+"/"+s.service.Name == m.highlightService {
+			m.highlighted = s
+		}
+	}
+}
+
+// serviceBadges counts the highlighted Service's endpoints on the node
+func (m *Model) serviceBadges(node *corev1.Node) []string {
+	if m.highlightService == "" {
+		return nil
+	}
+	serving, notReady := 0, 0
+	for _, p := range m.getPods(node) {
+		switch m.endpointState(p) {
+		case endpointServing:
+			serving++
+		case endpointNotReady:
+			notReady++
+		}
+	}
+	if serving+notReady == 0 {
+		return nil
+	}
+	return []string{fmt.Sprintf("%s: %d serving, %d not ready", m.highlightService, serving, notReady)}
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"runtime/debug"
+	"syscall"
+
+	tea "github.com/charmbracelet/bubbletea"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+)
+
+// fatalMsg ends the program with a diagnostic printed after the terminal is restored
+type fatalMsg struct {
+	err error
+}
+
+// signalMsg is sent when the process receives a termination signal
+type signalMsg struct {
+	signal os.Signal
+}
+
+// watchErrorMsg reports an informer watch failure that the informer will retry
+type watchErrorMsg struct {
+	err error
+}
+
+// fail records a fatal error and asks the event loop to quit. It is safe to call from any goroutine.
+func (m *Model) fail(err error) {
+	select {
+	case m.fatalCh <- err:
+	default:
+	}
+}
+
+// waitForFatal delivers the first fatal error recorded by fail to the event loop
+func (m *Model) waitForFatal() tea.Msg {
+	select {
+	case err := <-m.fatalCh:
+		return fatalMsg{err: err}
+	case <-m.stopCh:
+		return nil
+	}
+}
+
+// waitForWatchError delivers informer watch errors to the event loop one at a time
+func (m *Model) waitForWatchError() tea.Msg {
+	select {
+	case err := <-m.watchErrCh:
+		return watchErrorMsg{err: err}
+	case <-m.stopCh:
+		return nil
+	}
+}
+
+// watchErrorHandler forwards reflector errors to the UI instead of klog, which would scribble over the alt screen
+func (m *Model) watchErrorHandler(_ *cache.Reflector, err error) {
+	select {
+	case m.watchErrCh <- err:
+	default:
+	}
+}
+
+// shutdown stops the informers exactly once
+func (m *Model) shutdown() {
+	m.stopOnce.Do(func() { close(m.stopCh) })
+}
+
+// Update recovers panics from update by switching to the error view and resuming the
+// loop the message came from. While it is shown keys only continue or quit, other
+// messages are still processed.
+func (m *Model) Update(msg tea.Msg) (model tea.Model, cmd tea.Cmd) {
+	defer func() {
+		if r := recover(); r != nil {
+			m.recoverCrash("Update", r, debug.Stack())
+			model, cmd = m, m.resume(msg)
+		}
+	}()
+	if key, ok := msg.(tea.KeyMsg); ok && m.crash != nil {
+		return m, m.updateCrashView(key)
+	}
+	return m.update(msg)
+}
+
+// View recovers panics from render by switching to the error view
+func (m *Model) View() (view string) {
+	defer func() {
+		if r := recover(); r != nil {
+			m.recoverCrash("View", r, debug.Stack())
+			view = m.crashView()
+		}
+	}()
+	if m.crash != nil {
+		return m.crashView()
+	}
+	return m.render()
+}
+
+// handleSignals quits the program cleanly on SIGTERM and SIGHUP
+func handleSignals(p *tea.Program) {
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, syscall.SIGTERM, syscall.SIGHUP)
+	go func() {
+		p.Send(signalMsg{signal: <-sig})
+	}()
+}
+
+// handleInformerPanics restores the terminal before a panic in an informer
+// goroutine crashes the process, so the crash output is readable
+func handleInformerPanics(p *tea.Program) {
+	utilruntime.PanicHandlers = append([]func(interface{}){func(r interface{}) {
+		_ = p.ReleaseTerminal()
+		fmt.Fprintf(os.Stderr, "informer goroutine panicked: %v\n", r)
+	}}, utilruntime.PanicHandlers...)
+}
+
+// silenceKlog keeps client-go logging from writing over the alt screen
+func silenceKlog() {
+	klog.LogToStderr(false)
+	klog.SetOutput(io.Discard)
+}
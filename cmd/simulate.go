@@ -0,0 +1,231 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/bwagner5/kube-demo/pkg/binpack"
+	"github.com/bwagner5/kube-demo/pkg/predicates"
+)
+
+// simulationStep is how long each simulated pod is shown landing before the next one
+const simulationStep = 500 * time.Millisecond
+
+var simulatedBorder = blue
+
+// simulationStepMsg advances the animation of a simulation by one pod. Steps of a
+// cleared or restarted simulation are ignored.
+type simulationStepMsg struct {
+	simulation *simulation
+}
+
+// placement is where a simulated pod would be scheduled, or why it would not be
+type placement struct {
+	pod  *corev1.Pod
+	node string
+	// reason summarizes why no node fits, empty when the pod was placed
+	reason string
+}
+
+// simulation is the result of scheduling the --simulate pods onto a snapshot of the cluster,
+// revealed one pod at a time
+type simulation struct {
+	placements []placement
+	shown      int
+}
+
+// loadSimulatedPods reads the pods to simulate from manifests. Deployments, ReplicaSets and
+// StatefulSets stand for their replicas, each a pod from the template.
+func loadSimulatedPods(paths []string) ([]*corev1.Pod, error) {
+	var pods []*corev1.Pod
+	err := decodeFiles(paths, func(obj runtime.Object) error {
+		switch obj := obj.(type) {
+		case *corev1.Pod:
+			pods = append(pods, obj)
+		case *appsv1.Deployment:
+			pods = append(pods, templatePods(obj.ObjectMeta, obj.Spec.Replicas, obj.Spec.Template)...)
+		case *appsv1.ReplicaSet:
+			pods = append(pods, templatePods(obj.ObjectMeta, obj.Spec.Replicas, obj.Spec.Template)...)
+		case *appsv1.StatefulSet:
+			pods = append(pods, templatePods(obj.ObjectMeta, obj.Spec.Replicas, obj.Spec.Template)...)
+		}
+		return nil
+	})
+	if err == nil && len(pods) == 0 {
+		err = fmt.Errorf("no pods or workloads in %s", strings.Join(paths, ", "))
+	}
+	return pods, err
+}
+
+// templatePods returns a pod per replica of the workload, named after it
+func templatePods(workload metav1.ObjectMeta, replicas *int32, template corev1.PodTemplateSpec) []*corev1.Pod {
+	count := 1
+	if replicas != nil {
+		count = int(*replicas)
+	}
+	pods := make([]*corev1.Pod, 0, count)
+	for i := 0; i < count; i++ {
+		p := &corev1.Pod{ObjectMeta: *template.ObjectMeta.DeepCopy(), Spec: *template.Spec.DeepCopy()}
+		p.Name = fmt.Sprintf("%s-%d", workload.Name, i)
+		p.Namespace = workload.Namespace
+		pods = append(pods, p)
+	}
+	return pods
+}
+
+// simulate schedules the pods in order onto the current nodes without creating anything.
+// Nodes are filtered with the same predicates the pending view explains and the one with
+// the most free CPU and memory left afterwards wins, like the scheduler's default scoring.
+func (m *Model) simulate(pods []*corev1.Pod) *simulation {
+	nodes := m.getNodes()
+	nodePods := map[string][]*corev1.Pod{}
+	for _, n := range nodes {
+		nodePods[n.Name] = m.getPods(n)
+	}
+	result := &simulation{}
+	for _, p := range pods {
+		best, bestScore := "", -1.0
+		rejections := map[string]int{}
+		for _, n := range nodes {
+			if reasons := predicates.Explain(p, n, nodePods[n.Name]); len(reasons) > 0 {
+				rejections[strings.SplitN(reasons[0], " (", 2)[0]]++
+				continue
+			}
+			if score := leastAllocatedScore(n, append(nodePods[n.Name], p)); score > bestScore {
+				best, bestScore = n.Name, score
+			}
+		}
+		if best == "" {
+			result.placements = append(result.placements, placement{pod: p, reason: rejectionSummary(len(nodes), rejections)})
+			continue
+		}
+		placed := p.DeepCopy()
+		placed.Spec.NodeName = best
+		nodePods[best] = append(nodePods[best], placed)
+		result.placements = append(result.placements, placement{pod: p, node: best})
+	}
+	return result
+}
+
+// leastAllocatedScore is the average share of allocatable CPU and memory the node has left with the pods on it
+func leastAllocatedScore(n *corev1.Node, pods []*corev1.Pod) float64 {
+	free := binpack.NewBin(n, pods).Free
+	score := 0.0
+	for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+		allocatable := n.Status.Allocatable[name]
+		available := free[name]
+		if allocatable.IsZero() {
+			continue
+		}
+		score += float64(available.MilliValue()) / float64(allocatable.MilliValue()) / 2
+	}
+	return score
+}
+
+// rejectionSummary describes why no node fits, like the scheduler's FailedScheduling events
+func rejectionSummary(nodes int, rejections map[string]int) string {
+	reasons := make([]string, 0, len(rejections))
+	for reason, count := range rejections {
+		reasons = append(reasons, fmt.Sprintf("%d %s", count, reason))
+	}
+	sort.Strings(reasons)
+	return fmt.Sprintf("0/%d nodes available: %s", nodes, strings.Join(reasons, ", "))
+}
+
+// toggleSimulation schedules the --simulate pods onto the current state and reveals where they
+// land one at a time, or clears a previous simulation
+func (m *Model) toggleSimulation() tea.Cmd {
+	if m.simulation != nil {
+		m.simulation = nil
+		return m.setStatus("simulation cleared")
+	}
+	if len(m.simulatePods) == 0 {
+		return m.setStatus("no pods to simulate, start with --simulate <manifests>")
+	}
+	m.simulation = m.simulate(m.simulatePods)
+	return m.stepSimulation()
+}
+
+// stepSimulation schedules revealing the next simulated pod
+func (m *Model) stepSimulation() tea.Cmd {
+	s := m.simulation
+	return tea.Tick(simulationStep, func(time.Time) tea.Msg { return simulationStepMsg{simulation: s} })
+}
+
+// advance reveals the next simulated pod, returning false once all are shown
+func (s *simulation) advance() bool {
+	if s.shown >= len(s.placements) {
+		return false
+	}
+	s.shown++
+	return s.shown < len(s.placements)
+}
+
+// placedOn counts the revealed simulated pods that landed on the node
+func (s *simulation) placedOn(nodeName string) int {
+	count := 0
+	for _, p := range s.placements[:s.shown] {
+		if p.node == nodeName {
+			count++
+		}
+	}
+	return count
+}
+
+// latest returns the placement revealed last
+func (s *simulation) latest() (placement, bool) {
+	if s.shown == 0 {
+		return placement{}, false
+	}
+	return s.placements[s.shown-1], true
+}
+
+// simulationBorder highlights the node the latest simulated pod landed on
+func (m *Model) simulationBorder(nodeName string, color lipgloss.TerminalColor) lipgloss.TerminalColor {
+	if m.simulation == nil {
+		return color
+	}
+	if latest, ok := m.simulation.latest(); ok && latest.node == nodeName {
+		return simulatedBorder
+	}
+	return color
+}
+
+// simulationBadges counts the simulated pods landed on the node
+func (m *Model) simulationBadges(nodeName string) []string {
+	if m.simulation == nil {
+		return nil
+	}
+	if count := m.simulation.placedOn(nodeName); count > 0 {
+		return []string{fmt.Sprintf("+%d simulated", count)}
+	}
+	return nil
+}
+
+// summary describes the progress of the simulation for the status line
+func (s *simulation) summary() string {
+	placed := 0
+	for _, p := range s.placements[:s.shown] {
+		if p.node != "" {
+			placed++
+		}
+	}
+	line := fmt.Sprintf("simulated %d/%d pods, %d scheduled, %d unschedulable", s.shown, len(s.placements), placed, s.shown-placed)
+	if latest, ok := s.latest(); ok {
+		if latest.node != "" {
+			line += fmt.Sprintf(" | %s → %s", latest.pod.Name, latest.node)
+		} else {
+			line += fmt.Sprintf(" | %s: %s", latest.pod.Name, latest.reason)
+		}
+	}
+	return line
+}
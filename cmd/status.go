@@ -0,0 +1,88 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// statusTimeout is how long transient status messages stay on screen
+const statusTimeout = 3 * time.Second
+
+// clearStatus is sent once a transient status message has expired
+type clearStatus struct {
+	id int
+}
+
+// setStatus shows a transient message in the status line
+func (m *Model) setStatus(msg string) tea.Cmd {
+	m.status = msg
+	m.statusID++
+	id := m.statusID
+	return tea.Tick(statusTimeout, func(_ time.Time) tea.Msg {
+		return clearStatus{id: id}
+	})
+}
+
+// statusLine renders the pause or scrub indicator, or any transient status message,
+// prefixed by the active filter, the focus breadcrumbs and the kubeconfig context and
+// impersonated identity, and followed by the rolling scheduling latency and running tasks
+func (m *Model) statusLine() string {
+	if m.prompt != nil {
+		return m.prompt.input.View()
+	}
+	if m.menu != nil {
+		return m.menu.View()
+	}
+	line := m.status
+	if line == "" && m.whatIf != nil {
+		line = m.whatIf.summary()
+	}
+	if line == "" && m.simulation != nil {
+		line = m.simulation.summary()
+	}
+	if line == "" && m.upgradeMode {
+		line = m.upgradeSummary()
+	}
+	if line == "" && m.showPacking {
+		line = m.packingSummary()
+	}
+	if line == "" && m.rightsizing {
+		line = m.rightsizingSummary()
+	}
+	if line == "" && m.diagnostics != nil {
+		line = m.diagnostics.summary()
+	}
+	if line == "" && m.netpolPod != "" {
+		line = m.networkPolicySummary()
+	}
+	if line == "" && m.rescheduling != nil {
+		line = m.rescheduling.summary()
+	}
+	if line == "" && m.colorMode != colorByState {
+		line = m.colorLegend()
+	}
+	if m.scrubbing {
+		line = m.timeline()
+	} else if m.frozen != nil {
+		line = fmt.Sprintf("PAUSED at %s (%d changes since)", m.frozen.takenAt.Format(time.Kitchen), m.pausedChanges)
+	}
+	if filter := m.filterLabel(); filter != "" {
+		line = strings.TrimSpace(filter + "  " + line)
+	}
+	if crumbs := m.breadcrumbs(); crumbs != "" {
+		line = strings.TrimSpace(crumbs + "  " + line)
+	}
+	if latency := m.latencySummary(); latency != "" {
+		line = strings.TrimSpace(line + "  " + latency)
+	}
+	if tasks := m.tasksSummary(); tasks != "" {
+		line = strings.TrimSpace(line + "  " + tasks)
+	}
+	if m.identity != "" {
+		line = strings.TrimSpace(m.identity + "  " + line)
+	}
+	return line
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/bwagner5/kube-demo/pkg/state"
+)
+
+// ClusterStore holds the caches the UI reads cluster objects from. In a live
+// session these are informer stores; anything else that fills a cache.Store
+// (benchmarks, offline input) can drive the UI without an API server.
+type ClusterStore struct {
+	Nodes                      cache.Store
+	Pods                       cache.Store
+	ResourceQuotas             cache.Store
+	LimitRanges                cache.Store
+	Events                     cache.Store
+	ReplicaSets                cache.Store
+	Services                   cache.Store
+	EndpointSlices             cache.Store
+	Ingresses                  cache.Store
+	NetworkPolicies            cache.Store
+	Namespaces                 cache.Store
+	CertificateSigningRequests cache.Store
+	// VerticalPodAutoscalers holds unstructured VPAs and stays empty on clusters without the CRD
+	VerticalPodAutoscalers cache.Store
+	Deployments            cache.Store
+	DaemonSets             cache.Store
+	// HTTPRoutes holds unstructured Gateway API HTTPRoutes and stays empty on clusters without them
+	HTTPRoutes cache.Store
+}
+
+// NewClusterStore returns a ClusterStore backed by empty in-memory stores, the pods indexed like the pod informer
+func NewClusterStore() ClusterStore {
+	return ClusterStore{
+		Nodes: cache.NewStore(cache.MetaNamespaceKeyFunc),
+		Pods: cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{
+			cache.NamespaceIndex: cache.MetaNamespaceIndexFunc,
+			state.NodeNameIndex:  state.NodeNameIndexFunc,
+		}),
+		ResourceQuotas:             cache.NewStore(cache.MetaNamespaceKeyFunc),
+		LimitRanges:                cache.NewStore(cache.MetaNamespaceKeyFunc),
+		Events:                     cache.NewStore(cache.MetaNamespaceKeyFunc),
+		ReplicaSets:                cache.NewStore(cache.MetaNamespaceKeyFunc),
+		Services:                   cache.NewStore(cache.MetaNamespaceKeyFunc),
+		EndpointSlices:             cache.NewStore(cache.MetaNamespaceKeyFunc),
+		Ingresses:                  cache.NewStore(cache.MetaNamespaceKeyFunc),
+		NetworkPolicies:            cache.NewStore(cache.MetaNamespaceKeyFunc),
+		Namespaces:                 cache.NewStore(cache.MetaNamespaceKeyFunc),
+		CertificateSigningRequests: cache.NewStore(cache.MetaNamespaceKeyFunc),
+		VerticalPodAutoscalers:     cache.NewStore(cache.MetaNamespaceKeyFunc),
+		Deployments:                cache.NewStore(cache.MetaNamespaceKeyFunc),
+		DaemonSets:                 cache.NewStore(cache.MetaNamespaceKeyFunc),
+		HTTPRoutes:                 cache.NewStore(cache.MetaNamespaceKeyFunc),
+	}
+}
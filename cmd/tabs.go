@@ -0,0 +1,249 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/yaml"
+)
+
+// logsTimeout bounds how long a log lookup may take
+const logsTimeout = 10 * time.Second
+
+// logTailLines is how many of the most recent log lines the logs tab shows
+const logTailLines int64 = 200
+
+// detailTab is one of the tabs of the node and pod details pane
+type detailTab int
+
+const (
+	tabYAML detailTab = iota
+	tabDescribe
+	tabEvents
+	tabLogs
+	tabMetrics
+)
+
+var detailTabNames = []string{"YAML", "Describe", "Events", "Logs", "Metrics"}
+
+var activeTabStyle = lipgloss.NewStyle().Reverse(true).Padding(0, 1)
+var tabStyle = lipgloss.NewStyle().Foreground(grey).Padding(0, 1)
+
+// podLogsMsg delivers the tail of a container's logs, keyed by logsKey
+type podLogsMsg struct {
+	key  string
+	logs string
+	err  error
+}
+
+// hasTabs returns true when the details pane shows the tabbed node or pod details
+func (m *Model) hasTabs() bool {
+	return m.showingDetails() && m.view == viewNodes
+}
+
+// selectTab switches the details pane to tab, remembering the scroll position of each tab
+func (m *Model) selectTab(tab detailTab) tea.Cmd {
+	m.tabOffsets[m.detailTab] = m.viewport.YOffset
+	m.detailTab = tab
+	m.viewport.YOffset = m.tabOffsets[tab]
+	return m.loadTab()
+}
+
+// nextTab cycles to the following tab
+func (m *Model) nextTab() tea.Cmd {
+	return m.selectTab((m.detailTab + 1) % detailTab(len(detailTabNames)))
+}
+
+// resetTabs scrolls every tab back to the top for a new selection
+func (m *Model) resetTabs() {
+	m.tabOffsets = map[detailTab]int{}
+	m.viewport.YOffset = 0
+}
+
+// loadTab starts the background lookups the active tab needs
+func (m *Model) loadTab() tea.Cmd {
+	n := m.selectedNodeOrNil()
+	if n == nil {
+		return nil
+	}
+	if !m.selectingPods() {
+		if m.detailTab == tabEvents || m.detailTab == tabDescribe {
+			return m.fetchNodeEvents(n)
+		}
+		if m.detailTab == tabYAML {
+			return m.fetchFullObject(n)
+		}
+		return nil
+	}
+	p := m.selectedPodOrNil()
+	if p == nil {
+		return nil
+	}
+	switch m.detailTab {
+	case tabYAML:
+		return m.fetchFullObject(p)
+	case tabDescribe:
+		return tea.Batch(m.fetchPodEvents(p), m.fetchServiceAccountAccess(p), m.fetchPullSecrets(p))
+	case tabEvents:
+		return m.fetchPodEvents(p)
+	case tabLogs:
+		return m.fetchPodLogs(p)
+	}
+	return nil
+}
+
+// tabBar renders the tab names with the active tab highlighted
+func (m *Model) tabBar() string {
+	var tabs []string
+	for i, name := range detailTabNames {
+		style := tabStyle
+		if detailTab(i) == m.detailTab {
+			style = activeTabStyle
+		}
+		tabs = append(tabs, style.Render(fmt.Sprintf("%d %s", i+1, name)))
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top, tabs...)
+}
+
+// tabContent renders the active tab for the selected node or pod
+func (m *Model) tabContent() string {
+	n := m.selectedNodeOrNil()
+	if n == nil {
+		return ""
+	}
+	if m.selectingPods() {
+		if p := m.selectedPodOrNil(); p != nil {
+			return m.podTab(p)
+		}
+		return ""
+	}
+	return m.nodeTab(n)
+}
+
+func (m *Model) podTab(p *corev1.Pod) string {
+	switch m.detailTab {
+	case tabYAML:
+		return m.fullYAML(p.DeepCopy())
+	case tabEvents:
+		return m.eventsSummary(p.UID)
+	case tabLogs:
+		return m.logsSummary(p)
+	case tabMetrics:
+		if usage := m.usageSummary(p) + m.vpaRecommendation(p); usage != "" {
+			return usage
+		}
+		return "No usage samples yet\n"
+	}
+	if m.light {
+		return "Pod details are not cached in --light mode, the YAML tab loads the full pod\n\n" + finalizersSummary(p) + m.describePod(p)
+	}
+	return m.terminatingDiagnosis(p) + m.pullDiagnosis(p) + initProgress(p) + finalizersSummary(p) + m.describePod(p) + "\n" + m.serviceAccountSummary(p) + "\n" + m.preemptionDetails(p) + podLatency(p)
+}
+
+func (m *Model) nodeTab(n *corev1.Node) string {
+	switch m.detailTab {
+	case tabYAML:
+		return m.fullYAML(n.DeepCopy())
+	case tabEvents:
+		return m.eventsSummary(n.UID)
+	case tabLogs:
+		return "Logs are only available for pods, select one with tab\n"
+	case tabMetrics:
+		return m.nodeUsage(n)
+	}
+	return finalizersSummary(n) + m.describeNode(n) + "\n" + m.nodeDetails(n)
+}
+
+// objectYAML renders an object the way kubectl get -o yaml does, without managed fields
+func objectYAML(obj metav1.Object) string {
+	obj.SetManagedFields(nil)
+	out, err := yaml.Marshal(obj)
+	if err != nil {
+		return fmt.Sprintf("could not render yaml: %v\n", err)
+	}
+	return string(out)
+}
+
+// nodeUsage renders the latest sampled usage of each pod on the node and their total
+func (m *Model) nodeUsage(n *corev1.Node) string {
+	var out strings.Builder
+	w := tabwriter.NewWriter(&out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "POD\tCPU\tMEMORY\t")
+	var cpu, memory int64
+	for _, p := range m.getPods(n) {
+		samples := m.podUsage[p.Namespace+"/"+p.Name]
+		if len(samples) == 0 {
+			continue
+		}
+		latest := samples[len(samples)-1]
+		cpu += latest.cpuMillis
+		memory += latest.memoryBytes
+		fmt.Fprintf(w, "%s/%s\t%dm\t%dMi\t\n", p.Namespace, p.Name, latest.cpuMillis, latest.memoryBytes>>20)
+	}
+	fmt.Fprintf(w, "TOTAL\t%dm\t%dMi\t\n", cpu, memory>>20)
+	w.Flush()
+	return out.String()
+}
+
+// logContainer returns the focused container, or else the one kubectl logs would
+// pick: the annotated default or the first one
+func (m *Model) logContainer(p *corev1.Pod) string {
+	if name, ok := m.selectedContainerName(p); ok {
+		return name
+	}
+	if name, ok := p.Annotations["kubectl.kubernetes.io/default-container"]; ok {
+		return name
+	}
+	if len(p.Spec.Containers) == 0 {
+		return ""
+	}
+	return p.Spec.Containers[0].Name
+}
+
+// fetchPodLogs looks up the tail of the pod's logs in the background
+func (m *Model) fetchPodLogs(p *corev1.Pod) tea.Cmd {
+	if m.kubeClient == nil {
+		return nil
+	}
+	client := m.kubeClient
+	tail := logTailLines
+	container := m.logContainer(p)
+	options := &corev1.PodLogOptions{Container: container, TailLines: &tail}
+	return func() tea.Msg {
+		ctx, cancel := m.callContext(logsTimeout)
+		defer cancel()
+		var logs []byte
+		err := retry(ctx, func(ctx context.Context) (err error) {
+			logs, err = client.CoreV1().Pods(p.Namespace).GetLogs(p.Name, options).DoRaw(ctx)
+			return err
+		})
+		return podLogsMsg{key: logsKey(p.UID, container), logs: string(logs), err: err}
+	}
+}
+
+// logsSummary renders the fetched logs of a pod, or why there are none
+func (m *Model) logsSummary(p *corev1.Pod) string {
+	container := m.logContainer(p)
+	logs, ok := m.podLogs[logsKey(p.UID, container)]
+	switch {
+	case m.kubeClient == nil:
+		return "<unavailable offline>\n"
+	case !ok:
+		return "loading...\n"
+	case logs == "":
+		return "<none>\n"
+	}
+	return fmt.Sprintf("Container %s, last %d lines:\n\n%s", container, logTailLines, logs)
+}
+
+func logsKey(uid types.UID, container string) string {
+	return string(uid) + "/" + container
+}
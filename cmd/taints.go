@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/kubernetes"
+	clientretry "k8s.io/client-go/util/retry"
+)
+
+// promptTaint reads a kubectl taint argument for the selected node:
+// key[=value]:Effect adds a taint and key[:Effect]- removes it
+func (m *Model) promptTaint() tea.Cmd {
+	nodes := m.getNodes()
+	if m.view != viewNodes || len(nodes) == 0 {
+		return nil
+	}
+	name := nodes[m.selectedNode].Name
+	return m.openPrompt("taint node "+name, "key=value:NoSchedule or key-", func(value string) tea.Cmd {
+		a, err := taintNode(name, strings.TrimSpace(value))
+		if err != nil {
+			return m.setStatus(fmt.Sprintf("taint node %s: %v", name, err))
+		}
+		a.undo = m.undoTaint(name, strings.TrimSpace(value))
+		if taint, remove, _ := parseTaint(strings.TrimSpace(value)); !remove {
+			a = m.tracked(a, name, "", taint.Key+":"+string(taint.Effect))
+		}
+		return m.runAction(a)
+	})
+}
+
+// parseTaint parses a kubectl taint argument. Effect is empty when removing
+// a taint without naming one, which removes the key for every effect.
+func parseTaint(arg string) (taint corev1.Taint, remove bool, err error) {
+	if strings.HasSuffix(arg, "-") {
+		remove = true
+		arg = strings.TrimSuffix(arg, "-")
+	}
+	spec, effect, hasEffect := strings.Cut(arg, ":")
+	taint.Key, taint.Value, _ = strings.Cut(spec, "=")
+	taint.Effect = corev1.TaintEffect(effect)
+	if errs := validation.IsQualifiedName(taint.Key); len(errs) > 0 {
+		return taint, remove, fmt.Errorf("invalid key %q: %s", taint.Key, strings.Join(errs, "; "))
+	}
+	if errs := validation.IsValidLabelValue(taint.Value); len(errs) > 0 {
+		return taint, remove, fmt.Errorf("invalid value %q: %s", taint.Value, strings.Join(errs, "; "))
+	}
+	switch {
+	case !hasEffect && !remove:
+		return taint, remove, fmt.Errorf("missing effect in %q", arg)
+	case !hasEffect:
+	case taint.Effect == corev1.TaintEffectNoSchedule, taint.Effect == corev1.TaintEffectPreferNoSchedule, taint.Effect == corev1.TaintEffectNoExecute:
+	default:
+		return taint, remove, fmt.Errorf("invalid effect %q, expected NoSchedule, PreferNoSchedule or NoExecute", effect)
+	}
+	return taint, remove, nil
+}
+
+// applyTaint adds or replaces the taint, or removes matching taints, returning an
+// error when removing a taint the node does not have
+func applyTaint(taints []corev1.Taint, taint corev1.Taint, remove bool) ([]corev1.Taint, error) {
+	var result []corev1.Taint
+	found := false
+	for _, t := range taints {
+		if t.Key == taint.Key && (taint.Effect == "" || t.Effect == taint.Effect) {
+			found = true
+			continue
+		}
+		result = append(result, t)
+	}
+	if remove {
+		if !found {
+			return nil, fmt.Errorf("taint %q not found", taint.ToString())
+		}
+		return result, nil
+	}
+	if taint.Effect == corev1.TaintEffectNoExecute {
+		now := metav1.Now()
+		taint.TimeAdded = &now
+	}
+	return append(result, taint), nil
+}
+
+// taintNode returns an action replacing the node's taints. The patch carries the
+// resourceVersion it was computed from, so the node is re-read on conflicts.
+func taintNode(name string, arg string) (action, error) {
+	taint, remove, err := parseTaint(arg)
+	if err != nil {
+		return action{}, err
+	}
+	kubectl := fmt.Sprintf("kubectl taint nodes %s %s", name, arg)
+	if !remove {
+		kubectl += " --overwrite"
+	}
+	return action{
+		description: fmt.Sprintf("taint node %s %s", name, arg),
+		kubectl:     kubectl,
+		requires:    []string{"patch nodes"},
+		run: func(ctx context.Context, client kubernetes.Interface, dryRun []string) error {
+			return clientretry.RetryOnConflict(clientretry.DefaultRetry, func() error {
+				return retry(ctx, func(ctx context.Context) error {
+					n, err := client.CoreV1().Nodes().Get(ctx, name, metav1.GetOptions{})
+					if err != nil {
+						return err
+					}
+					taints, err := applyTaint(n.Spec.Taints, taint, remove)
+					if err != nil {
+						return err
+					}
+					patch, err := json.Marshal(map[string]interface{}{
+						"metadata": map[string]interface{}{"resourceVersion": n.ResourceVersion},
+						"spec":     map[string]interface{}{"taints": taints},
+					})
+					if err != nil {
+						return err
+					}
+					_, err = client.CoreV1().Nodes().Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{DryRun: dryRun})
+					return err
+				})
+			})
+		},
+	}, nil
+}
@@ -0,0 +1,224 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/samber/lo"
+
+	"github.com/bwagner5/kube-demo/pkg/format"
+)
+
+// maxFinishedTasks is how many completed tasks the tasks panel keeps
+const maxFinishedTasks = 20
+
+// task is a long running operation executing in the background
+type task struct {
+	id          int
+	description string
+	started     time.Time
+	finished    time.Time
+	// done and total report progress for tasks made of several steps, total is 0 otherwise
+	done, total int
+	cancel      context.CancelFunc
+	err         error
+}
+
+func (t *task) running() bool {
+	return t.finished.IsZero()
+}
+
+// taskProgressMsg reports how many of its steps a task has completed
+type taskProgressMsg struct {
+	id, done, total int
+}
+
+type taskProgressKey struct{}
+
+// taskProgress is carried in a task's context so its steps can report progress
+type taskProgress struct {
+	id      int
+	updates chan taskProgressMsg
+}
+
+// startTask registers a background operation, returning its id and a context
+// that is cancelled from the tasks panel or when the timeout expires
+func (m *Model) startTask(description string, timeout time.Duration) (int, context.Context, tea.Cmd) {
+	ctx, cancel := m.callContext(timeout)
+	m.nextTaskID++
+	id := m.nextTaskID
+	ctx = context.WithValue(ctx, taskProgressKey{}, taskProgress{id: id, updates: m.taskCh})
+	m.tasks = append(m.tasks, &task{id: id, description: description, started: time.Now(), cancel: cancel})
+	if m.spinning {
+		return id, ctx, nil
+	}
+	m.spinning = true
+	return id, ctx, m.spinner.Tick
+}
+
+// reportProgress records that done of total steps of the task running under ctx are complete
+func reportProgress(ctx context.Context, done int, total int) {
+	if progress, ok := ctx.Value(taskProgressKey{}).(taskProgress); ok {
+		select {
+		case progress.updates <- taskProgressMsg{id: progress.id, done: done, total: total}:
+		default:
+		}
+	}
+}
+
+// waitForTaskProgress delivers task progress to the event loop one update at a time
+func (m *Model) waitForTaskProgress() tea.Msg {
+	select {
+	case msg := <-m.taskCh:
+		return msg
+	case <-m.stopCh:
+		return nil
+	}
+}
+
+// updateTaskProgress records a progress report from a running task
+func (m *Model) updateTaskProgress(msg taskProgressMsg) {
+	if t := m.taskByID(msg.id); t != nil && t.running() {
+		t.done, t.total = msg.done, msg.total
+	}
+}
+
+// finishTask records a task's outcome and drops the oldest finished tasks beyond maxFinishedTasks,
+// keeping the tasks panel cursor on a row
+func (m *Model) finishTask(id int, err error) {
+	t := m.taskByID(id)
+	if t == nil {
+		return
+	}
+	t.finished, t.err = time.Now(), err
+	t.cancel()
+	finished := 0
+	for i := len(m.tasks) - 1; i >= 0; i-- {
+		if m.tasks[i].running() {
+			continue
+		}
+		if finished++; finished > maxFinishedTasks {
+			m.tasks = append(m.tasks[:i], m.tasks[i+1:]...)
+		}
+	}
+	if m.selectedTask >= len(m.tasks) {
+		m.selectedTask = lo.Max([]int{len(m.tasks) - 1, 0})
+	}
+}
+
+func (m *Model) taskByID(id int) *task {
+	for _, t := range m.tasks {
+		if t.id == id {
+			return t
+		}
+	}
+	return nil
+}
+
+// runningTasks returns how many tasks have not finished
+func (m *Model) runningTasks() int {
+	running := 0
+	for _, t := range m.tasks {
+		if t.running() {
+			running++
+		}
+	}
+	return running
+}
+
+// spin advances the spinner while any task is running, and stops ticking once none are
+func (m *Model) spin(msg spinner.TickMsg) tea.Cmd {
+	if m.runningTasks() == 0 {
+		m.spinning = false
+		return nil
+	}
+	var cmd tea.Cmd
+	m.spinner, cmd = m.spinner.Update(msg)
+	return cmd
+}
+
+// cancelSelectedTask cancels the task under the cursor in the tasks panel
+func (m *Model) cancelSelectedTask() tea.Cmd {
+	if len(m.tasks) == 0 {
+		return nil
+	}
+	t := m.tasks[len(m.tasks)-1-m.selectedTask]
+	if !t.running() {
+		return m.setStatus(fmt.Sprintf("%s already finished", t.description))
+	}
+	t.cancel()
+	return m.setStatus("cancelling " + t.description)
+}
+
+// tasksSummary renders a spinner and the number of running tasks for the status line
+func (m *Model) tasksSummary() string {
+	if running := m.runningTasks(); running > 0 {
+		return fmt.Sprintf("%s %d running", m.spinner.View(), running)
+	}
+	return ""
+}
+
+// tasksPanel renders the session's background tasks, newest first
+func (m *Model) tasksPanel() string {
+	if len(m.tasks) == 0 {
+		return "No background tasks this session"
+	}
+	var table strings.Builder
+	w := tabwriter.NewWriter(&table, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "\tTASK\tPROGRESS\tELAPSED\tRESULT\t")
+	for i := len(m.tasks) - 1; i >= 0; i-- {
+		t := m.tasks[i]
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t\n", m.taskIcon(t), t.description, taskProgressBar(t), format.Precise(t.elapsed()), t.result())
+	}
+	w.Flush()
+	lines := strings.Split(strings.TrimRight(table.String(), "\n"), "\n")
+	lines[m.selectedTask+1] = selectedRowStyle.Render(lines[m.selectedTask+1])
+	return strings.Join(lines, "\n") + "\n\nenter cancels the selected task"
+}
+
+func (m *Model) taskIcon(t *task) string {
+	switch {
+	case t.running():
+		return m.spinner.View()
+	case errors.Is(t.err, context.Canceled):
+		return "⊘"
+	case t.err != nil:
+		return "✗"
+	}
+	return "✓"
+}
+
+func (t *task) elapsed() time.Duration {
+	if t.running() {
+		return time.Since(t.started)
+	}
+	return t.finished.Sub(t.started)
+}
+
+func (t *task) result() string {
+	switch {
+	case t.running():
+		return ""
+	case errors.Is(t.err, context.Canceled):
+		return "cancelled"
+	case t.err != nil:
+		return t.err.Error()
+	}
+	return "done"
+}
+
+// taskProgressBar renders the completed steps of a multi-step task
+func taskProgressBar(t *task) string {
+	const width = 10
+	if t.total == 0 {
+		return "-"
+	}
+	filled := t.done * width / t.total
+	return fmt.Sprintf("[%s%s] %d/%d", strings.Repeat("█", filled), strings.Repeat("░", width-filled), t.done, t.total)
+}
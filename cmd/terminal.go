@@ -0,0 +1,56 @@
+package main
+
+import (
+	"os"
+	"runtime"
+
+	"github.com/charmbracelet/lipgloss"
+	"golang.org/x/term"
+)
+
+// asciiBorder draws boxes with characters every terminal and font has
+var asciiBorder = lipgloss.Border{
+	Top: "-", Bottom: "-", Left: "|", Right: "|",
+	TopLeft: "+", TopRight: "+", BottomLeft: "+", BottomRight: "+",
+}
+
+// selectedBorder marks the selected box in monochrome mode
+var selectedBorder = lipgloss.DoubleBorder()
+
+// useASCIIBorders replaces the box drawing borders with ASCII ones
+func useASCIIBorders() {
+	podStyle = podStyle.BorderStyle(asciiBorder)
+	widgetStyle = widgetStyle.BorderStyle(asciiBorder)
+	detailsPaneStyle = detailsPaneStyle.BorderStyle(asciiBorder)
+	crashStyle = crashStyle.BorderStyle(asciiBorder)
+	tourStyle = tourStyle.BorderStyle(asciiBorder)
+	hudStyle = hudStyle.BorderStyle(asciiBorder)
+	selectedBorder = lipgloss.Border{
+		Top: "=", Bottom: "=", Left: "#", Right: "#",
+		TopLeft: "#", TopRight: "#", BottomLeft: "#", BottomRight: "#",
+	}
+}
+
+// terminalSize returns the window size reported by bubbletea, which works on every
+// platform, asking the terminal directly only until the first report arrives
+func (m *Model) terminalSize() (int, int) {
+	if m.width > 0 && m.height > 0 {
+		return m.width, m.height
+	}
+	width, height, err := term.GetSize(int(os.Stdout.Fd()))
+	if err != nil {
+		return m.width, m.height
+	}
+	return width, height
+}
+
+// pollWindowSize picks up resizes on Windows, where bubbletea only reports the size
+// at startup because consoles have no SIGWINCH
+func (m *Model) pollWindowSize() {
+	if runtime.GOOS != "windows" {
+		return
+	}
+	if width, height, err := term.GetSize(int(os.Stdout.Fd())); err == nil {
+		m.width, m.height = width, height
+	}
+}
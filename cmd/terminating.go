@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/bwagner5/kube-demo/pkg/format"
+	"github.com/bwagner5/kube-demo/pkg/node"
+)
+
+// stuckTerminatingSlack is how long past its grace period a terminating pod may linger before it counts as stuck
+const stuckTerminatingSlack = 30 * time.Second
+
+var stuckTerminatingBorder = orange
+
+// stuckTerminating returns why the pod is still around past its grace period, or false if it is not stuck.
+// The deletion timestamp already includes the grace period.
+func (m *Model) stuckTerminating(p *corev1.Pod) (string, bool) {
+	if p.DeletionTimestamp == nil || time.Since(p.DeletionTimestamp.Time) < stuckTerminatingSlack {
+		return "", false
+	}
+	if len(p.Finalizers) > 0 {
+		return "waiting on finalizers " + strings.Join(p.Finalizers, ", "), true
+	}
+	if p.Spec.NodeName != "" {
+		n, ok := m.nodeNamed(p.Spec.NodeName)
+		switch {
+		case !ok:
+			return fmt.Sprintf("node %s no longer exists", p.Spec.NodeName), true
+		case !node.IsReady(n):
+			return fmt.Sprintf("node %s is unreachable, so its kubelet cannot confirm the pod stopped", n.Name), true
+		}
+	}
+	return "the kubelet has not confirmed the containers stopped", true
+}
+
+// terminatingDiagnosis explains a stuck terminating pod for the pod details
+func (m *Model) terminatingDiagnosis(p *corev1.Pod) string {
+	reason, stuck := m.stuckTerminating(p)
+	if !stuck {
+		return ""
+	}
+	hint := "press d to force delete"
+	if len(p.Finalizers) > 0 {
+		hint = "press F to remove a finalizer"
+	}
+	return fmt.Sprintf("Stuck terminating for %s past its grace period: %s\n  %s\n",
+		format.Duration(time.Since(p.DeletionTimestamp.Time)), reason, hint)
+}
+
+// stuckTerminatingBadges counts the node's pods stuck terminating
+func (m *Model) stuckTerminatingBadges(n *corev1.Node) []string {
+	count := 0
+	for _, p := range m.getPods(n) {
+		if _, stuck := m.stuckTerminating(p); stuck {
+			count++
+		}
+	}
+	if count == 0 {
+		return nil
+	}
+	return []string{fmt.Sprintf("✝ %d stuck terminating", count)}
+}
+
+// forceDeletePod deletes the pod with a zero grace period, removing it from the API
+// without waiting for the kubelet. Finalizers still have to be removed to let it go.
+func forceDeletePod(p *corev1.Pod) action {
+	namespace, name := p.Namespace, p.Name
+	return action{
+		description: fmt.Sprintf("force delete pod %s/%s", namespace, name),
+		kubectl:     fmt.Sprintf("kubectl delete pod %s -n %s --grace-period=0 --force", name, namespace),
+		requires:    []string{"delete pods"},
+		run: func(ctx context.Context, client kubernetes.Interface, dryRun []string) error {
+			gracePeriod := int64(0)
+			return retry(ctx, func(ctx context.Context) error {
+				return client.CoreV1().Pods(namespace).Delete(ctx, name, metav1.DeleteOptions{DryRun: dryRun, GracePeriodSeconds: &gracePeriod})
+			})
+		},
+	}
+}
+
+// confirmForceDelete asks before force deleting a stuck pod, since its containers may still be running
+func (m *Model) confirmForceDelete(p *corev1.Pod, reason string) tea.Cmd {
+	title := fmt.Sprintf("%s/%s is stuck terminating: %s. Force delete? Its containers may still be running", p.Namespace, p.Name, reason)
+	m.menu = &menu{title: title, items: []menuItem{
+		{key: "y", label: "force delete", choose: func() tea.Cmd { return m.runConfirmedAction(forceDeletePod(p)) }},
+		{key: "n", label: "cancel", choose: func() tea.Cmd { return m.setStatus("cancelled force delete") }},
+	}}
+	return nil
+}
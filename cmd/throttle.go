@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"k8s.io/client-go/util/flowcontrol"
+)
+
+// throttleWarning is how long a request has to wait on the client-side rate
+// limiter before it is reported, matching client-go's own log threshold
+const throttleWarning = time.Second
+
+// throttledMsg reports that a request was delayed by client-side rate limiting
+type throttledMsg struct {
+	waited time.Duration
+}
+
+// throttleReporter wraps a rate limiter, reporting long waits on notices
+type throttleReporter struct {
+	flowcontrol.RateLimiter
+	notices chan throttledMsg
+}
+
+func newThrottleReporter(qps float32, burst int) *throttleReporter {
+	return &throttleReporter{
+		RateLimiter: flowcontrol.NewTokenBucketRateLimiter(qps, burst),
+		notices:     make(chan throttledMsg, 1),
+	}
+}
+
+func (t *throttleReporter) Wait(ctx context.Context) error {
+	start := time.Now()
+	err := t.RateLimiter.Wait(ctx)
+	if waited := time.Since(start); waited >= throttleWarning {
+		select {
+		case t.notices <- throttledMsg{waited: waited}:
+		default:
+		}
+	}
+	return err
+}
+
+func (t *throttleReporter) Accept() {
+	start := time.Now()
+	t.RateLimiter.Accept()
+	if waited := time.Since(start); waited >= throttleWarning {
+		select {
+		case t.notices <- throttledMsg{waited: waited}:
+		default:
+		}
+	}
+}
+
+// waitForThrottle delivers throttling notices to the event loop one at a time
+func (m *Model) waitForThrottle() tea.Msg {
+	select {
+	case msg := <-m.throttleCh:
+		return msg
+	case <-m.stopCh:
+		return nil
+	}
+}
+
+func (msg throttledMsg) String() string {
+	return fmt.Sprintf("client-side throttling: request waited %s (raise --qps/--burst)", msg.waited.Round(100*time.Millisecond))
+}
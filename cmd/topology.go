@@ -0,0 +1,124 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/samber/lo"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/bwagner5/kube-demo/pkg/pod"
+)
+
+// topologyDomain is the number of matching pods in one value of a topology key
+type topologyDomain struct {
+	value string
+	pods  int
+	skew  int
+}
+
+// showTopology opens the topology spread view for the selected pod's workload
+func (m *Model) showTopology() {
+	if m.view == viewTopology {
+		m.toggleView(viewTopology)
+		return
+	}
+	p := m.selectedPodOrNil()
+	if p == nil {
+		return
+	}
+	m.topologyWorkload = m.workloadOf(p)
+	m.toggleView(viewTopology)
+}
+
+// workloadPods returns the rendered pods belonging to the workload
+func (m *Model) workloadPods(workload workloadRef) []*corev1.Pod {
+	var pods []*corev1.Pod
+	for _, p := range m.listPods() {
+		if p.Namespace == workload.Namespace && m.workloadOf(p) == workload {
+			pods = append(pods, p)
+		}
+	}
+	return pods
+}
+
+// spreadDomains counts the pods matching the constraint per domain of its
+// topology key, computing each domain's skew against the least loaded one
+func (m *Model) spreadDomains(namespace string, c corev1.TopologySpreadConstraint) ([]topologyDomain, error) {
+	selector, err := metav1.LabelSelectorAsSelector(c.LabelSelector)
+	if err != nil {
+		return nil, err
+	}
+	domainOf := map[string]string{}
+	counts := map[string]int{}
+	for _, n := range m.listNodes() {
+		if value, ok := n.Labels[c.TopologyKey]; ok {
+			domainOf[n.Name] = value
+			counts[value] += 0
+		}
+	}
+	for _, p := range m.listPods() {
+		if p.Namespace != namespace || pod.IsTerminal(p) || p.DeletionTimestamp != nil || !selector.Matches(labels.Set(p.Labels)) {
+			continue
+		}
+		if value, ok := domainOf[p.Spec.NodeName]; ok {
+			counts[value]++
+		}
+	}
+	min := 0
+	if len(counts) > 0 && (c.MinDomains == nil || len(counts) >= int(*c.MinDomains)) {
+		min = lo.Min(lo.Values(counts))
+	}
+	domains := lo.MapToSlice(counts, func(value string, count int) topologyDomain {
+		return topologyDomain{value: value, pods: count, skew: count - min}
+	})
+	sort.Slice(domains, func(i, j int) bool { return domains[i].value < domains[j].value })
+	return domains, nil
+}
+
+// topologySpread reports the skew per domain for every spread constraint of the
+// chosen workload, highlighting domains beyond maxSkew
+func (m *Model) topologySpread() string {
+	workload := m.topologyWorkload
+	pods := m.workloadPods(workload)
+	if len(pods) == 0 {
+		return fmt.Sprintf("No pods found for %s", workload)
+	}
+	// the newest pod carries the current template's constraints
+	newest := lo.MaxBy(pods, func(a, b *corev1.Pod) bool { return a.CreationTimestamp.After(b.CreationTimestamp.Time) })
+	constraints := newest.Spec.TopologySpreadConstraints
+	if len(constraints) == 0 {
+		return fmt.Sprintf("%s declares no topologySpreadConstraints", workload)
+	}
+	var out strings.Builder
+	fmt.Fprintf(&out, "Topology spread of %s (%d pods)\n", workload, len(pods))
+	for _, c := range constraints {
+		fmt.Fprintf(&out, "\n%s maxSkew=%d whenUnsatisfiable=%s selector=%s\n", c.TopologyKey, c.MaxSkew, c.WhenUnsatisfiable, metav1.FormatLabelSelector(c.LabelSelector))
+		domains, err := m.spreadDomains(newest.Namespace, c)
+		if err != nil {
+			fmt.Fprintf(&out, "  invalid selector: %v\n", err)
+			continue
+		}
+		if len(domains) == 0 {
+			fmt.Fprintf(&out, "  no nodes are labeled %s\n", c.TopologyKey)
+			continue
+		}
+		var table strings.Builder
+		w := tabwriter.NewWriter(&table, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "  DOMAIN\tPODS\tSKEW\t")
+		for _, d := range domains {
+			skew := fmt.Sprint(d.skew)
+			if d.skew > int(c.MaxSkew) {
+				skew = pullErrorStyle.Render(fmt.Sprintf("%d > %d", d.skew, c.MaxSkew))
+			}
+			fmt.Fprintf(w, "  %s\t%d\t%s\t\n", d.value, d.pods, skew)
+		}
+		w.Flush()
+		out.WriteString(table.String())
+	}
+	return strings.TrimRight(out.String(), "\n")
+}
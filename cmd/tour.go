@@ -0,0 +1,238 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/samber/lo"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/yaml"
+
+	"github.com/bwagner5/kube-demo/pkg/node"
+	"github.com/bwagner5/kube-demo/pkg/pod"
+)
+
+// tourMetrics are the counts a tour step can expect, computed from the live state
+var tourMetrics = []string{"nodes", "ready-nodes", "cordoned-nodes", "pods", "ready-pods", "pending-pods"}
+
+// tourOperators are the comparisons of a tour condition, two character ones first so they parse greedily
+var tourOperators = []string{">=", "<=", "==", "!=", ">", "<"}
+
+var tourStyle = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(blue).Padding(0, 1)
+
+// tour is a workshop loaded with --tour: steps of instructions that advance once the
+// cluster reaches the state each expects
+type tour struct {
+	Title string     `json:"title"`
+	Steps []tourStep `json:"steps"`
+	// current is the index of the step shown, len(Steps) once the tour is complete
+	current int
+}
+
+// tourStep is an instruction and the conditions that complete it. A step without
+// conditions is advanced by hand.
+type tourStep struct {
+	Title string `json:"title"`
+	Text  string `json:"text"`
+	// Expect are conditions such as ready-nodes>=3 or pending-pods==0, all of which must hold
+	Expect []string `json:"expect,omitempty"`
+	// Selector narrows the pods counted by the pod metrics to those matching a label selector
+	Selector string `json:"selector,omitempty"`
+
+	conditions []tourCondition
+	selector   labels.Selector
+}
+
+// tourCondition compares a metric to a value
+type tourCondition struct {
+	text     string
+	metric   string
+	operator string
+	value    int
+}
+
+// loadTour reads and validates a tour file
+func loadTour(path string) (*tour, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	t := &tour{}
+	if err := yaml.UnmarshalStrict(data, t); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	if len(t.Steps) == 0 {
+		return nil, fmt.Errorf("%s has no steps", path)
+	}
+	if t.Title == "" {
+		t.Title = "Tour"
+	}
+	for i := range t.Steps {
+		step := &t.Steps[i]
+		step.selector = labels.Everything()
+		if step.Selector != "" {
+			if step.selector, err = labels.Parse(step.Selector); err != nil {
+				return nil, fmt.Errorf("step %d: invalid selector: %w", i+1, err)
+			}
+		}
+		for _, text := range step.Expect {
+			condition, err := parseTourCondition(text)
+			if err != nil {
+				return nil, fmt.Errorf("step %d: %w", i+1, err)
+			}
+			step.conditions = append(step.conditions, condition)
+		}
+	}
+	return t, nil
+}
+
+// parseTourCondition parses metric<operator>value, such as ready-nodes>=3
+func parseTourCondition(text string) (tourCondition, error) {
+	spec := strings.ReplaceAll(text, " ", "")
+	for _, operator := range tourOperators {
+		metric, value, found := strings.Cut(spec, operator)
+		if !found {
+			continue
+		}
+		if !lo.Contains(tourMetrics, metric) {
+			return tourCondition{}, fmt.Errorf("unknown metric %q in %q, expected one of %s", metric, text, strings.Join(tourMetrics, ", "))
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return tourCondition{}, fmt.Errorf("invalid value in %q: %w", text, err)
+		}
+		return tourCondition{text: text, metric: metric, operator: operator, value: n}, nil
+	}
+	return tourCondition{}, fmt.Errorf("expected metric<operator>value in %q, such as ready-nodes>=3", text)
+}
+
+// holds compares the metric's current count to the condition's value
+func (c tourCondition) holds(counts map[string]int) bool {
+	count := counts[c.metric]
+	switch c.operator {
+	case ">=":
+		return count >= c.value
+	case "<=":
+		return count <= c.value
+	case "==":
+		return count == c.value
+	case "!=":
+		return count != c.value
+	case ">":
+		return count > c.value
+	}
+	return count < c.value
+}
+
+// tourCounts computes the tour metrics from the live state, counting the pods matching the selector
+func (m *Model) tourCounts(selector labels.Selector) map[string]int {
+	counts := map[string]int{}
+	live := m.tracker.Current()
+	for _, n := range live.Nodes {
+		counts["nodes"]++
+		if node.IsReady(n) {
+			counts["ready-nodes"]++
+		}
+		if node.IsCordoned(n) {
+			counts["cordoned-nodes"]++
+		}
+	}
+	for _, p := range live.Pods {
+		if pod.IsTerminal(p) || !selector.Matches(labels.Set(p.Labels)) {
+			continue
+		}
+		counts["pods"]++
+		if pod.IsReady(p) {
+			counts["ready-pods"]++
+		}
+		if p.Spec.NodeName == "" {
+			counts["pending-pods"]++
+		}
+	}
+	return counts
+}
+
+// stepComplete reports whether every condition of the step holds
+func (m *Model) stepComplete(step tourStep) bool {
+	if len(step.conditions) == 0 {
+		return false
+	}
+	counts := m.tourCounts(step.selector)
+	for _, condition := range step.conditions {
+		if !condition.holds(counts) {
+			return false
+		}
+	}
+	return true
+}
+
+// advanceTour moves past the steps whose expected state the cluster has reached
+func (m *Model) advanceTour() tea.Cmd {
+	t := m.tour
+	if t == nil || t.current >= len(t.Steps) {
+		return nil
+	}
+	completed := t.current
+	for t.current < len(t.Steps) && m.stepComplete(t.Steps[t.current]) {
+		t.current++
+	}
+	if t.current == completed {
+		return nil
+	}
+	if t.current == len(t.Steps) {
+		return m.setStatus("tour complete")
+	}
+	return m.setStatus(fmt.Sprintf("step %d done: %s", t.current, t.Steps[t.current-1].Title))
+}
+
+// skipTourStep advances the tour by hand, for steps without conditions or to skip ahead
+func (m *Model) skipTourStep() tea.Cmd {
+	if m.tour == nil {
+		return m.setStatus("no tour loaded, start with --tour <file>")
+	}
+	if m.tour.current >= len(m.tour.Steps) {
+		m.tour.current = 0
+		return tea.Batch(m.setStatus("tour restarted"), m.advanceTour())
+	}
+	m.tour.current++
+	return m.advanceTour()
+}
+
+// tourOverlay renders the instructions of the current step with the state of each condition
+func (m *Model) tourOverlay(width int) string {
+	t := m.tour
+	if t == nil {
+		return ""
+	}
+	style := tourStyle
+	if width > tourStyle.GetHorizontalBorderSize() {
+		style = style.Width(width - tourStyle.GetHorizontalBorderSize())
+	}
+	if t.current >= len(t.Steps) {
+		return style.Render(fmt.Sprintf("%s: complete, press k to start over", t.Title))
+	}
+	step := t.Steps[t.current]
+	lines := []string{lipgloss.NewStyle().Bold(true).Render(fmt.Sprintf("%s — step %d/%d: %s", t.Title, t.current+1, len(t.Steps), step.Title))}
+	if step.Text != "" {
+		lines = append(lines, strings.TrimSpace(step.Text))
+	}
+	if len(step.conditions) == 0 {
+		lines = append(lines, "press k when done")
+	} else {
+		counts := m.tourCounts(step.selector)
+		var waiting []string
+		for _, condition := range step.conditions {
+			mark := "✗"
+			if condition.holds(counts) {
+				mark = "✓"
+			}
+			waiting = append(waiting, fmt.Sprintf("%s %s (%d)", mark, condition.text, counts[condition.metric]))
+		}
+		lines = append(lines, "waiting for "+strings.Join(waiting, "  "))
+	}
+	return style.Render(strings.Join(lines, "\n"))
+}
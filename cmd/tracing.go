@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+	"k8s.io/client-go/kubernetes"
+)
+
+// tracer records spans for informer sync, render frames and API actions. It is
+// a no-op unless --otlp-endpoint installs an exporting provider.
+var tracer = otel.Tracer("github.com/bwagner5/kube-demo")
+
+// setupTracing exports spans over OTLP/HTTP to endpoint (host:port), returning
+// a function that flushes and stops the exporter
+func setupTracing(endpoint string, insecure bool) (func(), error) {
+	options := []otlptracehttp.Option{otlptracehttp.WithEndpoint(endpoint)}
+	if insecure {
+		options = append(options, otlptracehttp.WithInsecure())
+	}
+	exporter, err := otlptracehttp.New(context.Background(), options...)
+	if err != nil {
+		return nil, err
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceNameKey.String(programName()))),
+	)
+	otel.SetTracerProvider(provider)
+	return func() { _ = provider.Shutdown(context.Background()) }, nil
+}
+
+// runTraced runs the action inside a span recording its outcome
+func (a action) runTraced(ctx context.Context, client kubernetes.Interface, dryRun []string) error {
+	ctx, span := tracer.Start(ctx, "action", trace.WithAttributes(
+		attribute.String("action.description", a.description),
+		attribute.Bool("action.dry_run", len(dryRun) > 0),
+	))
+	defer span.End()
+	err := a.run(ctx, client, dryRun)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return err
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+)
+
+var httpRouteResource = schema.GroupVersionResource{Group: "gateway.networking.k8s.io", Version: "v1beta1", Resource: "httproutes"}
+
+// trafficRoute is one host and path routed to a backend Service by an Ingress or HTTPRoute
+type trafficRoute struct {
+	kind      string
+	namespace string
+	name      string
+	host      string
+	path      string
+	service   string
+	port      string
+}
+
+// newOptionalInformer watches a custom resource through the dynamic client,
+// returning nil when the cluster does not serve it
+func newOptionalInformer(config *rest.Config, client discovery.DiscoveryInterface, resource schema.GroupVersionResource, namespace string) (cache.SharedIndexInformer, error) {
+	resources, err := client.ServerResourcesForGroupVersion(resource.GroupVersion().String())
+	if err != nil {
+		return nil, nil
+	}
+	served := false
+	for _, r := range resources.APIResources {
+		served = served || r.Name == resource.Resource
+	}
+	if !served {
+		return nil, nil
+	}
+	dynamicClient, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	return dynamicinformer.NewFilteredDynamicInformer(dynamicClient, resource, namespace, 10*time.Minute, cache.Indexers{}, nil).Informer(), nil
+}
+
+// ingressRoutes lists the hosts and paths of an Ingress with their backend Services
+func ingressRoutes(ingress *networkingv1.Ingress) []trafficRoute {
+	var routes []trafficRoute
+	add := func(host string, path string, backend *networkingv1.IngressBackend) {
+		if backend == nil || backend.Service == nil {
+			return
+		}
+		port := backend.Service.Port.Name
+		if port == "" {
+			port = fmt.Sprint(backend.Service.Port.Number)
+		}
+		routes = append(routes, trafficRoute{kind: "Ingress", namespace: ingress.Namespace, name: ingress.Name,
+			host: host, path: path, service: ingress.Namespace + "/" + backend.Service.Name, port: port})
+	}
+	add("*", "/", ingress.Spec.DefaultBackend)
+	for _, rule := range ingress.Spec.Rules {
+		host := rule.Host
+		if host == "" {
+			host = "*"
+		}
+		if rule.HTTP == nil {
+			continue
+		}
+		for _, path := range rule.HTTP.Paths {
+			path := path
+			add(host, path.Path, &path.Backend)
+		}
+	}
+	return routes
+}
+
+// httpRouteRoutes lists the hostnames and path matches of an HTTPRoute with their backend Services
+func httpRouteRoutes(route *unstructured.Unstructured) []trafficRoute {
+	hosts, _, _ := unstructured.NestedStringSlice(route.Object, "spec", "hostnames")
+	if len(hosts) == 0 {
+		hosts = []string{"*"}
+	}
+	rules, _, _ := unstructured.NestedSlice(route.Object, "spec", "rules")
+	var routes []trafficRoute
+	for _, r := range rules {
+		rule, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		path := "/"
+		if matches, _, _ := unstructured.NestedSlice(rule, "matches"); len(matches) > 0 {
+			if match, ok := matches[0].(map[string]interface{}); ok {
+				if value, found, _ := unstructured.NestedString(match, "path", "value"); found {
+					path = value
+				}
+			}
+		}
+		refs, _, _ := unstructured.NestedSlice(rule, "backendRefs")
+		for _, b := range refs {
+			ref, ok := b.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if kind, found, _ := unstructured.NestedString(ref, "kind"); found && kind != "Service" {
+				continue
+			}
+			name, _, _ := unstructured.NestedString(ref, "name")
+			namespace, found, _ := unstructured.NestedString(ref, "namespace")
+			if !found {
+				namespace = route.GetNamespace()
+			}
+			port, _, _ := unstructured.NestedInt64(ref, "port")
+			for _, host := range hosts {
+				routes = append(routes, trafficRoute{kind: "HTTPRoute", namespace: route.GetNamespace(), name: route.GetName(),
+					host: host, path: path, service: namespace + "/" + name, port: fmt.Sprint(port)})
+			}
+		}
+	}
+	return routes
+}
+
+// getRoutes returns the routes of every Ingress and HTTPRoute, sorted by host and path
+func (m *Model) getRoutes() []trafficRoute {
+	var routes []trafficRoute
+	for _, obj := range m.store.Ingresses.List() {
+		routes = append(routes, ingressRoutes(obj.(*networkingv1.Ingress))...)
+	}
+	for _, obj := range m.store.HTTPRoutes.List() {
+		routes = append(routes, httpRouteRoutes(obj.(*unstructured.Unstructured))...)
+	}
+	sort.SliceStable(routes, func(i, j int) bool {
+		if routes[i].host != routes[j].host {
+			return routes[i].host < routes[j].host
+		}
+		return routes[i].path < routes[j].path
+	})
+	return routes
+}
+
+// traffic renders where each route's traffic lands: the backend Service's
+// ready endpoint pods and the nodes they run on
+func (m *Model) traffic() string {
+	routes := m.getRoutes()
+	if len(routes) == 0 {
+		return "No ingresses or HTTPRoutes"
+	}
+	endpoints := map[string]serviceEndpoints{}
+	for _, s := range m.getServices() {
+		endpoints[s.service.Namespace+"/"+s.service.Name] = s
+	}
+	nodeOf := map[string]string{}
+	for _, p := range m.listPods() {
+		nodeOf[string(p.UID)] = p.Spec.NodeName
+	}
+	var table strings.Builder
+	w := tabwriter.NewWriter(&table, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ROUTE\tHOST\tPATH\tSERVICE\tPODS\tNODES\t")
+	for _, r := range routes {
+		s, ok := endpoints[r.service]
+		pods, nodes := "missing", "-"
+		if ok {
+			landsOn := map[string]bool{}
+			for uid, ready := range s.pods {
+				if node := nodeOf[string(uid)]; ready && node != "" {
+					landsOn[node] = true
+				}
+			}
+			names := make([]string, 0, len(landsOn))
+			for node := range landsOn {
+				names = append(names, node)
+			}
+			sort.Strings(names)
+			pods = fmt.Sprintf("%d/%d ready", s.ready, s.ready+s.notReady)
+			nodes = strings.Join(names, ",")
+		}
+		fmt.Fprintf(w, "%s %s/%s\t%s\t%s\t%s:%s\t%s\t%s\t\n", r.kind, r.namespace, r.name, r.host, r.path, r.service, r.port, pods, nodes)
+	}
+	w.Flush()
+	lines := strings.Split(strings.TrimRight(table.String(), "\n"), "\n")
+	lines[m.selectedRoute+1] = selectedRowStyle.Render(lines[m.selectedRoute+1])
+	return strings.Join(lines, "\n")
+}
+
+// highlightRoute highlights the selected route's backend Service pods on the node grid
+func (m *Model) highlightRoute() {
+	routes := m.getRoutes()
+	if len(routes) == 0 {
+		return
+	}
+	m.highlightService = routes[m.selectedRoute].service
+	m.trackServiceHighlight()
+	m.toggleView(viewTraffic)
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/samber/lo"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// maxCachedAnnotationBytes is the longest annotation value kept in the informer caches
+const maxCachedAnnotationBytes = 1024
+
+// lastAppliedAnnotation holds a copy of the whole object applied with kubectl apply
+const lastAppliedAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+// trimmedAnnotation lists the keys of the annotations trimObject dropped from a cached object,
+// comma separated, so code reading the cache can tell them from annotations the object lacks
+const trimmedAnnotation = "kube-demo.dev/trimmed-annotations"
+
+// trimmedValue stands in for the value of a trimmed annotation where annotations are listed
+const trimmedValue = "<trimmed from the cache, see the YAML tab>"
+
+// fullObjectTimeout bounds fetching the untrimmed object for the YAML tab
+const fullObjectTimeout = 10 * time.Second
+
+// trimObject is the informer transform dropping what the UI never shows from cached objects:
+// managed fields and large annotations such as the last applied configuration. On clusters
+// with tens of thousands of pods they are most of the cache. The keys of the dropped
+// annotations are kept in the trimmedAnnotation. The YAML tab fetches the full object when
+// opened.
+func trimObject(obj interface{}) (interface{}, error) {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		// tombstones and other wrappers are passed through as they are
+		return obj, nil
+	}
+	accessor.SetManagedFields(nil)
+	annotations := accessor.GetAnnotations()
+	var trimmed []string
+	for key, value := range annotations {
+		if key != trimmedAnnotation && (key == lastAppliedAnnotation || len(value) > maxCachedAnnotationBytes) {
+			delete(annotations, key)
+			trimmed = append(trimmed, key)
+		}
+	}
+	if len(trimmed) > 0 {
+		sort.Strings(trimmed)
+		annotations[trimmedAnnotation] = strings.Join(trimmed, ",")
+	}
+	return obj, nil
+}
+
+// isTrimmed reports whether the annotation was dropped from the cached object
+func isTrimmed(obj metav1.Object, key string) bool {
+	return lo.Contains(strings.Split(obj.GetAnnotations()[trimmedAnnotation], ","), key)
+}
+
+// cachedAnnotations returns the annotations of the cached object to list, the trimmed ones
+// valued with trimmedValue
+func cachedAnnotations(obj metav1.Object) map[string]string {
+	annotations := make(map[string]string, len(obj.GetAnnotations()))
+	for key, value := range obj.GetAnnotations() {
+		if key != trimmedAnnotation {
+			annotations[key] = value
+		}
+	}
+	if trimmed := obj.GetAnnotations()[trimmedAnnotation]; trimmed != "" {
+		for _, key := range strings.Split(trimmed, ",") {
+			annotations[key] = trimmedValue
+		}
+	}
+	return annotations
+}
+
+// fullObject is the YAML of an object fetched from the API server because the cached copy is trimmed
+type fullObject struct {
+	uid             types.UID
+	resourceVersion string
+	yaml            string
+}
+
+// fullObjectMsg delivers an object fetched in full
+type fullObjectMsg struct {
+	object fullObject
+	err    error
+}
+
+// fetchFullObject looks up the untrimmed pod or node in the background for the YAML tab
+func (m *Model) fetchFullObject(obj metav1.Object) tea.Cmd {
+	if m.kubeClient == nil {
+		return nil
+	}
+	if m.isFullObject(obj) {
+		return nil
+	}
+	client := m.kubeClient
+	uid, namespace, name := obj.GetUID(), obj.GetNamespace(), obj.GetName()
+	_, isPod := obj.(*corev1.Pod)
+	return func() tea.Msg {
+		ctx, cancel := m.callContext(fullObjectTimeout)
+		defer cancel()
+		var full metav1.Object
+		err := retry(ctx, func(ctx context.Context) (err error) {
+			if isPod {
+				full, err = client.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+			} else {
+				full, err = client.CoreV1().Nodes().Get(ctx, name, metav1.GetOptions{})
+			}
+			return err
+		})
+		if err != nil {
+			return fullObjectMsg{object: fullObject{uid: uid}, err: err}
+		}
+		return fullObjectMsg{object: fullObject{uid: uid, resourceVersion: full.GetResourceVersion(), yaml: objectYAML(full)}}
+	}
+}
+
+// isFullObject reports whether the fetched full object is the cached one at the same version
+func (m *Model) isFullObject(obj metav1.Object) bool {
+	return m.fullObject.uid == obj.GetUID() && m.fullObject.resourceVersion == obj.GetResourceVersion()
+}
+
+// fullYAML renders the fetched full object when it is as recent as the cached one, otherwise
+// the trimmed cached copy. Only the latest fetched object is kept.
+func (m *Model) fullYAML(obj metav1.Object) string {
+	if m.isFullObject(obj) {
+		return m.fullObject.yaml
+	}
+	out := objectYAML(obj)
+	if m.kubeClient != nil {
+		out = "# large annotations are trimmed from the cache, the full object loads when the tab opens\n" + out
+	}
+	return out
+}
+
+// lightPod is the pod transform of --light mode: only the metadata, the node and the phase and
+// Ready condition the views color pods by are cached. Containers, volumes and the rest of the
+// status are dropped, so requests, usage and describe are empty; the YAML tab fetches the full pod.
+func lightPod(obj interface{}) (interface{}, error) {
+	p, ok := obj.(*corev1.Pod)
+	if !ok {
+		return trimObject(obj)
+	}
+	if _, err := trimObject(p); err != nil {
+		return nil, err
+	}
+	light := &corev1.Pod{
+		TypeMeta:   p.TypeMeta,
+		ObjectMeta: p.ObjectMeta,
+		Spec:       corev1.PodSpec{NodeName: p.Spec.NodeName},
+		Status:     corev1.PodStatus{Phase: p.Status.Phase},
+	}
+	for _, condition := range p.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			light.Status.Conditions = []corev1.PodCondition{{Type: condition.Type, Status: condition.Status, LastTransitionTime: condition.LastTransitionTime}}
+		}
+	}
+	return light, nil
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// maxUndo is how many applied actions the undo stack remembers
+const maxUndo = 20
+
+// undoEntry is an applied action and, when it can be reverted, the action that reverts it
+type undoEntry struct {
+	description string
+	undo        *action
+}
+
+// pushUndo remembers an applied action so "u" can revert it. Actions run by
+// undo are not recorded, so repeated undos walk further back.
+func (m *Model) pushUndo(a action) {
+	if a.undoing {
+		return
+	}
+	m.undoStack = append(m.undoStack, undoEntry{description: a.description, undo: a.undo})
+	if len(m.undoStack) > maxUndo {
+		m.undoStack = m.undoStack[len(m.undoStack)-maxUndo:]
+	}
+}
+
+// undoLast reverts the most recent applied action. Cordons, labels, annotations,
+// taints and Deployment scaling can be undone; deletes, drains, evictions and
+// StatefulSet scaling cannot, and are dropped from the stack with a notice.
+func (m *Model) undoLast() tea.Cmd {
+	if len(m.undoStack) == 0 {
+		return m.setStatus("nothing to undo")
+	}
+	entry := m.undoStack[len(m.undoStack)-1]
+	m.undoStack = m.undoStack[:len(m.undoStack)-1]
+	if entry.undo == nil {
+		return m.setStatus(fmt.Sprintf("%s cannot be undone", entry.description))
+	}
+	a := *entry.undo
+	a.description = "undo " + entry.description
+	a.undoing = true
+	return m.runAction(a)
+}
+
+// nodeNamed returns the cached node with the given name
+func (m *Model) nodeNamed(name string) (*corev1.Node, bool) {
+	obj, ok, _ := m.store.Nodes.GetByKey(name)
+	if !ok {
+		return nil, false
+	}
+	return obj.(*corev1.Node), true
+}
+
+// undoMetadata sets the label or annotation change restoring the key's cached value as the
+// undo of a. The cache lacks the value of trimmed annotations, so it is read from the API
+// server right before a changes it instead.
+func (m *Model) undoMetadata(a *action, name string, verb string, arg string) {
+	n, ok := m.nodeNamed(name)
+	if !ok {
+		return
+	}
+	key := strings.TrimSuffix(arg, "-")
+	if k, _, set := strings.Cut(arg, "="); set {
+		key = k
+	}
+	values := n.Labels
+	if verb == "annotate" {
+		if isTrimmed(n, key) {
+			undoTrimmedAnnotation(a, name, key)
+			return
+		}
+		values = n.Annotations
+	}
+	inverse := key + "-"
+	if value, had := values[key]; had {
+		inverse = key + "=" + value
+	}
+	undo, err := setNodeMetadata(name, verb, inverse)
+	if err != nil {
+		return
+	}
+	a.undo = &undo
+}
+
+// undoTrimmedAnnotation makes a record the annotation's value before changing it, and sets
+// the undo of a to restore the recorded value
+func undoTrimmedAnnotation(a *action, name string, key string) {
+	var previous *string
+	run := a.run
+	a.run = func(ctx context.Context, client kubernetes.Interface, dryRun []string) error {
+		if err := retry(ctx, func(ctx context.Context) error {
+			n, err := client.CoreV1().Nodes().Get(ctx, name, metav1.GetOptions{})
+			if err != nil {
+				return err
+			}
+			previous = nil
+			if value, had := n.Annotations[key]; had {
+				previous = &value
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+		return run(ctx, client, dryRun)
+	}
+	a.undo = &action{
+		description: fmt.Sprintf("annotate node %s %s", name, key),
+		kubectl:     fmt.Sprintf("kubectl annotate node %s %s=<value before the change> --overwrite", name, key),
+		requires:    []string{"patch nodes"},
+		run: func(ctx context.Context, client kubernetes.Interface, dryRun []string) error {
+			inverse := key + "-"
+			if previous != nil {
+				inverse = key + "=" + *previous
+			}
+			restore, err := setNodeMetadata(name, "annotate", inverse)
+			if err != nil {
+				return err
+			}
+			return restore.run(ctx, client, dryRun)
+		},
+	}
+}
+
+// undoTaint returns the taint change restoring the cached taint the argument
+// replaces or removes. Removing a key with several effects cannot be undone.
+func (m *Model) undoTaint(name string, arg string) *action {
+	n, ok := m.nodeNamed(name)
+	if !ok {
+		return nil
+	}
+	taint, remove, err := parseTaint(arg)
+	if err != nil {
+		return nil
+	}
+	var matched []corev1.Taint
+	for _, t := range n.Spec.Taints {
+		if t.Key == taint.Key && (taint.Effect == "" || t.Effect == taint.Effect) {
+			matched = append(matched, t)
+		}
+	}
+	var inverse string
+	switch {
+	case len(matched) == 1:
+		inverse = fmt.Sprintf("%s=%s:%s", matched[0].Key, matched[0].Value, matched[0].Effect)
+	case len(matched) == 0 && !remove:
+		inverse = fmt.Sprintf("%s:%s-", taint.Key, taint.Effect)
+	default:
+		return nil
+	}
+	undo, err := taintNode(name, inverse)
+	if err != nil {
+		return nil
+	}
+	return &undo
+}
+
+// undoScale returns the scale back to a Deployment's cached replica count
+func (m *Model) undoScale(workload workloadRef) *action {
+	if workload.Kind != "Deployment" {
+		return nil
+	}
+	obj, ok, _ := m.store.Deployments.GetByKey(workload.Namespace + "/" + workload.Name)
+	if !ok {
+		return nil
+	}
+	deployment := obj.(*appsv1.Deployment)
+	if deployment.Spec.Replicas == nil {
+		return nil
+	}
+	undo := scaleWorkload(workload, *deployment.Spec.Replicas)
+	return &undo
+}
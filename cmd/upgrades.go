@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/samber/lo"
+	"k8s.io/apimachinery/pkg/util/version"
+)
+
+// upgradeBarWidth is the number of cells in the upgrade progress bar
+const upgradeBarWidth = 20
+
+// olderVersionColors color nodes on older kubelet versions, newest first
+var olderVersionColors = []lipgloss.Color{yellow, orange, red}
+
+// kubeletVersions returns the kubelet versions of the rendered nodes, newest first
+func (m *Model) kubeletVersions() []string {
+	seen := map[string]bool{}
+	for _, n := range m.getNodes() {
+		seen[n.Status.NodeInfo.KubeletVersion] = true
+	}
+	versions := make([]string, 0, len(seen))
+	for v := range seen {
+		versions = append(versions, v)
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		vi, erri := version.ParseGeneric(versions[i])
+		vj, errj := version.ParseGeneric(versions[j])
+		if erri != nil || errj != nil {
+			return versions[i] > versions[j]
+		}
+		return vj.LessThan(vi)
+	})
+	return versions
+}
+
+// upgradeBorder colors nodes by kubelet version while the upgrade mode is on:
+// the newest version is green and older versions get warmer colors
+func (m *Model) upgradeBorder(kubeletVersion string, color lipgloss.TerminalColor) lipgloss.TerminalColor {
+	if !m.upgradeMode {
+		return color
+	}
+	for i, v := range m.versions {
+		if v != kubeletVersion {
+			continue
+		}
+		if i == 0 {
+			return green
+		}
+		return olderVersionColors[lo.Min([]int{i - 1, len(olderVersionColors) - 1})]
+	}
+	return color
+}
+
+// upgradeSummary renders the share of nodes on the newest kubelet version as a progress bar
+func (m *Model) upgradeSummary() string {
+	nodes := m.getNodes()
+	if len(nodes) == 0 || len(m.versions) == 0 {
+		return "upgrade: no nodes"
+	}
+	target := m.versions[0]
+	upgraded := 0
+	for _, n := range nodes {
+		if n.Status.NodeInfo.KubeletVersion == target {
+			upgraded++
+		}
+	}
+	filled := upgraded * upgradeBarWidth / len(nodes)
+	bar := strings.Repeat("█", filled) + strings.Repeat("░", upgradeBarWidth-filled)
+	return fmt.Sprintf("upgrade to %s: [%s] %d/%d nodes, versions %s", target, bar, upgraded, len(nodes), strings.Join(m.versions, ", "))
+}
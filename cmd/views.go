@@ -0,0 +1,75 @@
+package main
+
+import "fmt"
+
+// viewMode selects which grid is rendered on the canvas
+type viewMode int
+
+const (
+	viewNodes viewMode = iota
+	viewNamespaces
+	viewImages
+	viewPending
+	viewAudit
+	viewTopology
+	viewServices
+	viewTraffic
+	viewCertificates
+	viewControlPlane
+	viewChurn
+	viewTasks
+	viewFragmentation
+	viewDashboard
+	viewCompare
+)
+
+// viewNames name the views in saved profiles, in viewMode order
+var viewNames = []string{
+	"nodes", "namespaces", "images", "pending", "audit", "topology", "services", "traffic",
+	"certificates", "control-plane", "churn", "tasks", "fragmentation", "dashboard", "compare",
+}
+
+func (v viewMode) String() string {
+	if int(v) < len(viewNames) {
+		return viewNames[v]
+	}
+	return fmt.Sprintf("view(%d)", int(v))
+}
+
+// toggleView switches to the given view, or back to the node grid if it is already active
+func (m *Model) toggleView(view viewMode) {
+	if m.view == view {
+		m.view = viewNodes
+		return
+	}
+	m.view = view
+	m.unfocus(focusDetails)
+}
+
+// moveListCursor moves a cursor up or down a single-column list, or to its first or last
+// item with home and end. Moving past an end continues at the other one if wrap is set.
+func moveListCursor(key string, cursor int, total int, wrap bool) int {
+	switch key {
+	case "home":
+		cursor = 0
+	case "end":
+		cursor = total - 1
+	case "up", "left":
+		cursor--
+		if cursor < 0 && wrap {
+			cursor = total - 1
+		}
+	case "down", "right":
+		cursor++
+		if cursor >= total && wrap {
+			cursor = 0
+		}
+	}
+	if cursor >= total {
+		cursor = total - 1
+	}
+	if cursor < 0 {
+		cursor = 0
+	}
+	return cursor
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var vpaResource = schema.GroupVersionResource{Group: "autoscaling.k8s.io", Version: "v1", Resource: "verticalpodautoscalers"}
+
+// vpaFor returns the VerticalPodAutoscaler targeting the pod's workload, or nil
+func (m *Model) vpaFor(p *corev1.Pod) *unstructured.Unstructured {
+	workload := m.workloadOf(p)
+	for _, obj := range m.store.VerticalPodAutoscalers.List() {
+		vpa := obj.(*unstructured.Unstructured)
+		kind, _, _ := unstructured.NestedString(vpa.Object, "spec", "targetRef", "kind")
+		name, _, _ := unstructured.NestedString(vpa.Object, "spec", "targetRef", "name")
+		if vpa.GetNamespace() == workload.Namespace && kind == workload.Kind && name == workload.Name {
+			return vpa
+		}
+	}
+	return nil
+}
+
+// vpaRecommendation renders the VPA's recommended requests next to the pod's
+// current requests, per container, for pod details
+func (m *Model) vpaRecommendation(p *corev1.Pod) string {
+	vpa := m.vpaFor(p)
+	if vpa == nil {
+		return ""
+	}
+	mode, found, _ := unstructured.NestedString(vpa.Object, "spec", "updatePolicy", "updateMode")
+	if !found {
+		mode = "Auto"
+	}
+	recommendations, _, _ := unstructured.NestedSlice(vpa.Object, "status", "recommendation", "containerRecommendations")
+	var out strings.Builder
+	fmt.Fprintf(&out, "VPA %s/%s (updateMode %s):\n", vpa.GetNamespace(), vpa.GetName(), mode)
+	if len(recommendations) == 0 {
+		out.WriteString("  no recommendation yet\n\n")
+		return out.String()
+	}
+	w := tabwriter.NewWriter(&out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "  CONTAINER\tREQUESTS\tTARGET\tLOWER\tUPPER\t")
+	for _, r := range recommendations {
+		rec, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _, _ := unstructured.NestedString(rec, "containerName")
+		current := "-"
+		for _, c := range p.Spec.Containers {
+			if c.Name == name {
+				current = formatResources(c.Resources.Requests)
+			}
+		}
+		fmt.Fprintf(w, "  %s\t%s\t%s\t%s\t%s\t\n", name, current,
+			recommendedResources(rec, "target"), recommendedResources(rec, "lowerBound"), recommendedResources(rec, "upperBound"))
+	}
+	w.Flush()
+	out.WriteString("\n")
+	return out.String()
+}
+
+// recommendedResources parses one bound of a container recommendation into a formatted resource list
+func recommendedResources(rec map[string]interface{}, field string) string {
+	values, found, _ := unstructured.NestedStringMap(rec, field)
+	if !found {
+		return "-"
+	}
+	list := corev1.ResourceList{}
+	for name, value := range values {
+		if quantity, err := resource.ParseQuantity(value); err == nil {
+			list[corev1.ResourceName(name)] = quantity
+		}
+	}
+	return formatResources(list)
+}
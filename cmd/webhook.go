@@ -0,0 +1,53 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// webhookTimeout bounds posting a notification, including retries
+const webhookTimeout = 10 * time.Second
+
+// postWebhook posts the payload as JSON to the URL, retrying transient failures
+func postWebhook(url string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), webhookTimeout)
+	defer cancel()
+	return retry(ctx, func(ctx context.Context) error {
+		request, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		request.Header.Set("Content-Type", "application/json")
+		response, err := http.DefaultClient.Do(request)
+		if err != nil {
+			return err
+		}
+		response.Body.Close()
+		if response.StatusCode >= 300 {
+			return &responseError{code: response.StatusCode, status: response.Status}
+		}
+		return nil
+	})
+}
+
+// responseError is an HTTP response other than a success. Throttling and server errors are
+// transient, other client errors are not.
+type responseError struct {
+	code   int
+	status string
+}
+
+func (e *responseError) Error() string {
+	return "webhook responded " + e.status
+}
+
+func (e *responseError) transient() bool {
+	return e.code == http.StatusTooManyRequests || e.code >= http.StatusInternalServerError
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/bwagner5/kube-demo/pkg/binpack"
+	"github.com/bwagner5/kube-demo/pkg/node"
+)
+
+var whatIfBorder = green
+
+// whatIf is the result of packing a hypothetical workload onto the current nodes
+type whatIf struct {
+	replicas int
+	requests corev1.ResourceList
+	// placed counts replicas per node name that fit on existing capacity
+	placed   map[string]int
+	newNodes int
+	// unschedulable counts replicas too large for even an empty node of the template shape
+	unschedulable int
+}
+
+// promptWhatIf asks for a hypothetical workload and simulates it on submit
+func (m *Model) promptWhatIf() tea.Cmd {
+	return m.openPrompt("what if (replicas cpu memory)", "10 500m 1Gi", func(value string) tea.Cmd {
+		replicas, requests, err := parseWorkload(value)
+		if err != nil {
+			return m.setStatus(fmt.Sprintf("what if: %v", err))
+		}
+		m.whatIf = m.simulateWhatIf(replicas, requests)
+		return nil
+	})
+}
+
+// parseWorkload parses "<replicas> <cpu> [memory]"
+func parseWorkload(value string) (int, corev1.ResourceList, error) {
+	fields := strings.Fields(value)
+	if len(fields) < 2 || len(fields) > 3 {
+		return 0, nil, fmt.Errorf("expected \"<replicas> <cpu> [memory]\"")
+	}
+	replicas, err := strconv.Atoi(fields[0])
+	if err != nil || replicas < 1 {
+		return 0, nil, fmt.Errorf("invalid replica count %q", fields[0])
+	}
+	requests := corev1.ResourceList{}
+	for i, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+		if i+1 >= len(fields) {
+			break
+		}
+		quantity, err := resource.ParseQuantity(fields[i+1])
+		if err != nil {
+			return 0, nil, fmt.Errorf("invalid %s quantity %q", name, fields[i+1])
+		}
+		requests[name] = quantity
+	}
+	return replicas, requests, nil
+}
+
+// schedulableBins returns the free capacity of every rendered node that accepts new pods
+func (m *Model) schedulableBins() []*binpack.Bin {
+	var bins []*binpack.Bin
+	for _, n := range m.getNodes() {
+		if node.IsCordoned(n) || !node.IsReady(n) {
+			continue
+		}
+		bins = append(bins, binpack.NewBin(n, m.getPods(n)))
+	}
+	return bins
+}
+
+// simulateWhatIf first-fit packs the replicas onto existing nodes and sizes the
+// overflow in new nodes shaped like the largest existing node
+func (m *Model) simulateWhatIf(replicas int, requests corev1.ResourceList) *whatIf {
+	result := &whatIf{replicas: replicas, requests: requests, placed: map[string]int{}}
+	bins := m.schedulableBins()
+	var template corev1.ResourceList
+	for _, n := range m.getNodes() {
+		if template == nil || n.Status.Allocatable.Cpu().Cmp(*template.Cpu()) > 0 {
+			template = n.Status.Allocatable
+		}
+	}
+	existing := len(bins)
+	for i := 0; i < replicas; i++ {
+		index := binpack.Place(bins, requests)
+		switch {
+		case index >= existing:
+			// landed on a node added earlier in the simulation
+		case index >= 0:
+			result.placed[bins[index].Name]++
+		case template == nil || !binpack.Fits(binpack.WithPodSlot(requests), template):
+			result.unschedulable++
+		default:
+			result.newNodes++
+			bins = append(bins, &binpack.Bin{Name: fmt.Sprintf("new-%d", result.newNodes), Free: template.DeepCopy()})
+			binpack.Place(bins[len(bins)-1:], requests)
+		}
+	}
+	return result
+}
+
+// summary describes the simulation result for the status line
+func (w *whatIf) summary() string {
+	fit := 0
+	for _, count := range w.placed {
+		fit += count
+	}
+	line := fmt.Sprintf("what if %d x (%s): %d fit on %d existing nodes, %d need %d new nodes",
+		w.replicas, formatResources(w.requests), fit, len(w.placed), w.replicas-fit-w.unschedulable, w.newNodes)
+	if w.unschedulable > 0 {
+		line += fmt.Sprintf(", %d fit nowhere", w.unschedulable)
+	}
+	return line
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// workloadRef identifies the top level controller that owns a pod
+type workloadRef struct {
+	Kind      string
+	Namespace string
+	Name      string
+}
+
+func (w workloadRef) String() string {
+	return w.Kind + "/" + w.Namespace + "/" + w.Name
+}
+
+// workloadOf resolves the pod's owning workload, following ReplicaSets up to
+// their Deployment. Pods without a controller are their own workload.
+func (m *Model) workloadOf(p *corev1.Pod) workloadRef {
+	owner := metav1.GetControllerOf(p)
+	if owner == nil {
+		return workloadRef{Kind: "Pod", Namespace: p.Namespace, Name: p.Name}
+	}
+	if owner.Kind == "ReplicaSet" {
+		if obj, ok, _ := m.store.ReplicaSets.GetByKey(p.Namespace + "/" + owner.Name); ok {
+			if deployment := metav1.GetControllerOf(obj.(*appsv1.ReplicaSet)); deployment != nil && deployment.Kind == "Deployment" {
+				return workloadRef{Kind: "Deployment", Namespace: p.Namespace, Name: deployment.Name}
+			}
+		}
+	}
+	return workloadRef{Kind: owner.Kind, Namespace: p.Namespace, Name: owner.Name}
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// podNameWidth is the width of a pod box showing the pod's name
+const podNameWidth = 12
+
+// zoomedStyle grows a node box to twice its size, leaving room for its pods by name
+func zoomedStyle(style lipgloss.Style) lipgloss.Style {
+	return style.Copy().Width(style.GetWidth() * 2).Height(style.GetHeight() * 2)
+}
+
+// shrunkStyle shrinks a node box to its name and pod count while another node is zoomed
+func shrunkStyle(style lipgloss.Style) lipgloss.Style {
+	return style.Copy().Width(style.GetWidth() * 2 / 3).Height(2 + style.GetVerticalPadding())
+}
+
+// podBoxStyle is the style of the pod boxes, wide enough for the pod name when named
+func (m *Model) podBoxStyle(named bool) lipgloss.Style {
+	if named {
+		return podStyle.Copy().Width(podNameWidth).Align(lipgloss.Left)
+	}
+	return podStyle
+}
+
+// podLabel is the content of a named pod box, the pod's marker or privilege badge followed by its name
+func (m *Model) podLabel(pod *corev1.Pod) string {
+	if content := m.podContent(pod); content != "" {
+		return content + " " + abbreviatePodName(pod, podNameWidth-1-lipgloss.Width(content))
+	}
+	return abbreviatePodName(pod, podNameWidth)
+}
+
+// shrunkNode is the content of a node box shrunk while another node is zoomed
+func (m *Model) shrunkNode(node *corev1.Node, style lipgloss.Style) []string {
+	return []string{middleTruncate(node.Name, contentWidth(style)), fmt.Sprintf("%d pods", len(m.shownPods(node)))}
+}
+
+// toggleZoom grows the selected node in place to show its pods by name, shrinking the other nodes
+func (m *Model) toggleZoom() tea.Cmd {
+	m.zoomed = !m.zoomed
+	if m.zoomed {
+		return m.setStatus("zoomed into the selected node")
+	}
+	return m.setStatus("zoomed out")
+}
@@ -0,0 +1,96 @@
+// Package v1 is the versioned gRPC API streaming the cluster state kube-demo renders, so
+// companion tools such as web frontends and recording bots see the same nodes and pods.
+//
+// The service is kubedemo.state.v1.State, defined in state.proto, with a single server
+// streaming method, Watch. A subscriber first receives the current state as EventSnapshot
+// events ended by an EventSynced event, then an event per node or pod change. Messages are
+// protobuf encoded. Clients that prefer the proto3 JSON mapping register Codec and call
+// with the "json" content-subtype.
+package v1
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative state.proto
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/bwagner5/kube-demo/pkg/node"
+	"github.com/bwagner5/kube-demo/pkg/pod"
+)
+
+// ServiceName is the fully qualified name of the State service
+const ServiceName = "kubedemo.state.v1.State"
+
+const (
+	// EventSnapshot is part of the state the subscriber starts from
+	EventSnapshot = EventType_SNAPSHOT
+	// EventSynced ends the snapshot, every later event is a change
+	EventSynced  = EventType_SYNCED
+	EventAdded   = EventType_ADDED
+	EventUpdated = EventType_UPDATED
+	EventDeleted = EventType_DELETED
+)
+
+// NodeOf converts a node to its API representation
+func NodeOf(n *corev1.Node) *Node {
+	taints := make([]string, 0, len(n.Spec.Taints))
+	for _, taint := range n.Spec.Taints {
+		taints = append(taints, taint.ToString())
+	}
+	return &Node{
+		Name:         n.Name,
+		Labels:       n.Labels,
+		Ready:        node.IsReady(n),
+		Cordoned:     node.IsCordoned(n),
+		Taints:       taints,
+		Allocatable:  quantities(n.Status.Allocatable),
+		CreationTime: n.CreationTimestamp.Unix(),
+	}
+}
+
+// PodOf converts a pod to its API representation
+func PodOf(p *corev1.Pod) *Pod {
+	return &Pod{
+		Namespace: p.Namespace,
+		Name:      p.Name,
+		NodeName:  p.Spec.NodeName,
+		Phase:     string(p.Status.Phase),
+		Ready:     pod.IsReady(p),
+		Labels:    p.Labels,
+		Requests:  quantities(pod.Requests(p)),
+	}
+}
+
+func quantities(resources corev1.ResourceList) map[string]string {
+	result := make(map[string]string, len(resources))
+	for name, quantity := range resources {
+		result[string(name)] = quantity.String()
+	}
+	return result
+}
+
+// Codec encodes the messages of the State service in the proto3 JSON mapping, for the "json"
+// content-subtype. Servers and clients register it with encoding.RegisterCodec, protobuf
+// stays the default encoding.
+type Codec struct{}
+
+func (Codec) Marshal(v interface{}) ([]byte, error) {
+	message, ok := v.(proto.Message)
+	if !ok {
+		return nil, fmt.Errorf("encoding %T as JSON: not a protobuf message", v)
+	}
+	return protojson.Marshal(message)
+}
+
+func (Codec) Unmarshal(data []byte, v interface{}) error {
+	message, ok := v.(proto.Message)
+	if !ok {
+		return fmt.Errorf("decoding JSON into %T: not a protobuf message", v)
+	}
+	return protojson.UnmarshalOptions{DiscardUnknown: true}.Unmarshal(data, message)
+}
+
+func (Codec) Name() string { return "json" }
@@ -0,0 +1,151 @@
+package binpack
+
+import (
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/bwagner5/kube-demo/pkg/pod"
+)
+
+// Bin is the remaining schedulable capacity of a node
+type Bin struct {
+	Name string
+	Free corev1.ResourceList
+}
+
+// NewBin returns the node's allocatable capacity minus the requests of the
+// non-terminal pods bound to it. Each pod also consumes one "pods" slot.
+func NewBin(node *corev1.Node, pods []*corev1.Pod) *Bin {
+	free := node.Status.Allocatable.DeepCopy()
+	if free == nil {
+		free = corev1.ResourceList{}
+	}
+	for _, p := range pods {
+		if pod.IsTerminal(p) {
+			continue
+		}
+		Subtract(free, WithPodSlot(pod.Requests(p)))
+	}
+	return &Bin{Name: node.Name, Free: free}
+}
+
+// WithPodSlot returns a copy of requests that also claims a single "pods" slot
+func WithPodSlot(requests corev1.ResourceList) corev1.ResourceList {
+	out := requests.DeepCopy()
+	if out == nil {
+		out = corev1.ResourceList{}
+	}
+	out[corev1.ResourcePods] = *resource.NewQuantity(1, resource.DecimalSI)
+	return out
+}
+
+// Subtract removes every quantity in requests from free. Resources missing from free are left alone.
+func Subtract(free corev1.ResourceList, requests corev1.ResourceList) {
+	for name, quantity := range requests {
+		if current, ok := free[name]; ok {
+			current.Sub(quantity)
+			free[name] = current
+		}
+	}
+}
+
+// Fits returns true if every requested resource the bin tracks has enough free capacity
+func Fits(requests corev1.ResourceList, free corev1.ResourceList) bool {
+	for name, quantity := range requests {
+		if available, ok := free[name]; ok && quantity.Cmp(available) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Place claims requests from the first bin they fit in, returning its index or -1
+func Place(bins []*Bin, requests corev1.ResourceList) int {
+	requests = WithPodSlot(requests)
+	for i, bin := range bins {
+		if Fits(requests, bin.Free) {
+			Subtract(bin.Free, requests)
+			return i
+		}
+	}
+	return -1
+}
+
+// Copy returns deep copies of the bins so a simulation does not modify the originals
+func Copy(bins []*Bin) []*Bin {
+	out := make([]*Bin, 0, len(bins))
+	for _, bin := range bins {
+		out = append(out, &Bin{Name: bin.Name, Free: bin.Free.DeepCopy()})
+	}
+	return out
+}
+
+// strandedThreshold is the free fraction of a resource below which the node is
+// considered full in that dimension
+const strandedThreshold = 0.1
+
+// Packing is how much of a node's allocatable CPU and memory is requested
+type Packing struct {
+	CPU    float64
+	Memory float64
+	// StrandedCPU and StrandedMemory are free capacity that cannot be used because
+	// the node has run out of the other resource
+	StrandedCPU    resource.Quantity
+	StrandedMemory resource.Quantity
+}
+
+// Score is the mean of the CPU and memory packing, between 0 and 1
+func (p Packing) Score() float64 {
+	return (p.CPU + p.Memory) / 2
+}
+
+// NewPacking compares the node's allocatable CPU and memory with what the bin has left free
+func NewPacking(node *corev1.Node, bin *Bin) Packing {
+	var packing Packing
+	cpuFree := fraction(bin.Free.Cpu().MilliValue(), node.Status.Allocatable.Cpu().MilliValue())
+	memoryFree := fraction(bin.Free.Memory().Value(), node.Status.Allocatable.Memory().Value())
+	packing.CPU, packing.Memory = 1-cpuFree, 1-memoryFree
+	if cpuFree < strandedThreshold && memoryFree >= strandedThreshold {
+		packing.StrandedMemory = bin.Free.Memory().DeepCopy()
+	}
+	if memoryFree < strandedThreshold && cpuFree >= strandedThreshold {
+		packing.StrandedCPU = bin.Free.Cpu().DeepCopy()
+	}
+	return packing
+}
+
+// fraction returns part/whole clamped to [0, 1], treating an empty whole as fully free
+func fraction(part int64, whole int64) float64 {
+	if whole <= 0 {
+		return 1
+	}
+	f := float64(part) / float64(whole)
+	if f < 0 {
+		return 0
+	}
+	if f > 1 {
+		return 1
+	}
+	return f
+}
+
+// PlaceAll places every request with first-fit-decreasing on copies of the
+// bins, largest CPU request first, returning false if any request fits nowhere
+func PlaceAll(bins []*Bin, requests []corev1.ResourceList) bool {
+	bins = Copy(bins)
+	sorted := append([]corev1.ResourceList(nil), requests...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if c := sorted[i].Cpu().Cmp(*sorted[j].Cpu()); c != 0 {
+			return c > 0
+		}
+		return sorted[i].Memory().Cmp(*sorted[j].Memory()) > 0
+	})
+	for _, requests := range sorted {
+		if Place(bins, requests) == -1 {
+			return false
+		}
+	}
+	return true
+}
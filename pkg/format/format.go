@@ -0,0 +1,154 @@
+package format
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// Locale is how numbers and relative times are written in a language
+type Locale struct {
+	// Decimal separates the integer and fractional parts of a number
+	Decimal string
+	// Ago and In are format strings placing a duration in the past or future
+	Ago string
+	In  string
+}
+
+var locales = map[string]Locale{
+	"en": {Decimal: ".", Ago: "%s ago", In: "in %s"},
+	"de": {Decimal: ",", Ago: "vor %s", In: "in %s"},
+	"es": {Decimal: ",", Ago: "hace %s", In: "en %s"},
+	"fr": {Decimal: ",", Ago: "il y a %s", In: "dans %s"},
+	"it": {Decimal: ",", Ago: "%s fa", In: "tra %s"},
+	"nl": {Decimal: ",", Ago: "%s geleden", In: "over %s"},
+	"pt": {Decimal: ",", Ago: "há %s", In: "em %s"},
+	"ja": {Decimal: ".", Ago: "%s前", In: "%s後"},
+}
+
+var current = locales["en"]
+
+// SetLocale switches the formatting to a locale given as a language such as "de"
+// or a POSIX locale such as "de_DE.UTF-8". Unknown languages fall back to English.
+func SetLocale(name string) error {
+	language := strings.ToLower(name)
+	if i := strings.IndexAny(language, "_.-@"); i >= 0 {
+		language = language[:i]
+	}
+	if language == "" || language == "c" || language == "posix" {
+		language = "en"
+	}
+	locale, ok := locales[language]
+	if !ok {
+		current = locales["en"]
+		return fmt.Errorf("unsupported locale %q, using English", name)
+	}
+	current = locale
+	return nil
+}
+
+// LocaleFromEnv returns the locale of the environment in the order POSIX programs look it up
+func LocaleFromEnv() string {
+	for _, name := range []string{"LC_ALL", "LC_NUMERIC", "LANG"} {
+		if value := os.Getenv(name); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// Duration formats a duration in its largest whole unit, such as "45s", "5m", "3h" or "2d"
+func Duration(d time.Duration) string {
+	if d < 0 {
+		d = -d
+	}
+	switch {
+	case d >= 24*time.Hour:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	case d >= time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	case d >= time.Minute:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	}
+	return fmt.Sprintf("%ds", int(d.Seconds()))
+}
+
+// Precise formats a duration in its two largest units, such as "1m23s" or "2d4h", for
+// elapsed times where the remainder matters
+func Precise(d time.Duration) string {
+	if d < 0 {
+		d = -d
+	}
+	days, hours := int(d.Hours()/24), int(d.Hours())%24
+	minutes, seconds := int(d.Minutes())%60, int(d.Seconds())%60
+	switch {
+	case days > 0:
+		return fmt.Sprintf("%dd%dh", days, hours)
+	case hours > 0:
+		return fmt.Sprintf("%dh%dm", hours, minutes)
+	case minutes > 0:
+		return fmt.Sprintf("%dm%ds", minutes, seconds)
+	}
+	return fmt.Sprintf("%ds", seconds)
+}
+
+// Ago formats how long ago t was, such as "5m ago"
+func Ago(t time.Time) string {
+	return fmt.Sprintf(current.Ago, Duration(time.Since(t)))
+}
+
+// Until formats how long until t, such as "in 2d"
+func Until(t time.Time) string {
+	return fmt.Sprintf(current.In, Duration(time.Until(t)))
+}
+
+// Number formats a number with at most one decimal in the current locale
+func Number(value float64) string {
+	s := strconv.FormatFloat(value, 'f', 1, 64)
+	s = strings.TrimSuffix(s, ".0")
+	return strings.Replace(s, ".", current.Decimal, 1)
+}
+
+// CPU formats cores compactly: millicores below a core, such as "250m", and cores above, such as "1.5"
+func CPU(q resource.Quantity) string {
+	millis := q.MilliValue()
+	if millis < 1000 && millis > -1000 {
+		return fmt.Sprintf("%dm", millis)
+	}
+	return Number(float64(millis) / 1000)
+}
+
+var binaryUnits = []string{"Ki", "Mi", "Gi", "Ti", "Pi", "Ei"}
+
+// Bytes formats a byte quantity in the largest binary unit it reaches, such as "512Mi" or "1.5Gi"
+func Bytes(q resource.Quantity) string {
+	value := float64(q.Value())
+	unit := ""
+	for _, next := range binaryUnits {
+		if value < 1024 && value > -1024 {
+			break
+		}
+		value /= 1024
+		unit = next
+	}
+	return Number(value) + unit
+}
+
+// Quantity formats a quantity of the named resource, CPU in cores, memory and storage in bytes
+func Quantity(name corev1.ResourceName, q resource.Quantity) string {
+	switch name {
+	case corev1.ResourceCPU:
+		return CPU(q)
+	case corev1.ResourceMemory, corev1.ResourceEphemeralStorage, corev1.ResourceStorage:
+		return Bytes(q)
+	}
+	if strings.HasPrefix(string(name), corev1.ResourceHugePagesPrefix) {
+		return Bytes(q)
+	}
+	return q.String()
+}
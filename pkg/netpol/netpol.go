@@ -0,0 +1,110 @@
+package netpol
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// Namespaces maps namespace names to their labels, used to evaluate namespaceSelectors
+type Namespaces map[string]labels.Set
+
+// labelsOf returns the namespace's labels, falling back to the label the API
+// server sets on every namespace when the namespace is unknown
+func (n Namespaces) labelsOf(namespace string) labels.Set {
+	if l, ok := n[namespace]; ok {
+		return l
+	}
+	return labels.Set{corev1.LabelMetadataName: namespace}
+}
+
+// Allowed returns true if the policies permit a connection from one pod to
+// another, checking both the egress policies of the source and the ingress
+// policies of the destination. Ports and ipBlocks are not considered.
+func Allowed(policies []*networkingv1.NetworkPolicy, namespaces Namespaces, from *corev1.Pod, to *corev1.Pod) bool {
+	return allowedEgress(policies, namespaces, from, to) && allowedIngress(policies, namespaces, from, to)
+}
+
+func allowedIngress(policies []*networkingv1.NetworkPolicy, namespaces Namespaces, from *corev1.Pod, to *corev1.Pod) bool {
+	isolated := false
+	for _, policy := range policies {
+		if !selects(policy, to, networkingv1.PolicyTypeIngress) {
+			continue
+		}
+		isolated = true
+		for _, rule := range policy.Spec.Ingress {
+			if peersMatch(rule.From, policy.Namespace, namespaces, from) {
+				return true
+			}
+		}
+	}
+	return !isolated
+}
+
+func allowedEgress(policies []*networkingv1.NetworkPolicy, namespaces Namespaces, from *corev1.Pod, to *corev1.Pod) bool {
+	isolated := false
+	for _, policy := range policies {
+		if !selects(policy, from, networkingv1.PolicyTypeEgress) {
+			continue
+		}
+		isolated = true
+		for _, rule := range policy.Spec.Egress {
+			if peersMatch(rule.To, policy.Namespace, namespaces, to) {
+				return true
+			}
+		}
+	}
+	return !isolated
+}
+
+// selects returns true if the policy applies to the pod for the policy type
+func selects(policy *networkingv1.NetworkPolicy, p *corev1.Pod, policyType networkingv1.PolicyType) bool {
+	if policy.Namespace != p.Namespace || !hasType(policy, policyType) {
+		return false
+	}
+	selector, err := metav1.LabelSelectorAsSelector(&policy.Spec.PodSelector)
+	return err == nil && selector.Matches(labels.Set(p.Labels))
+}
+
+// hasType applies the API defaulting of policyTypes: Ingress always, Egress only with egress rules
+func hasType(policy *networkingv1.NetworkPolicy, policyType networkingv1.PolicyType) bool {
+	if len(policy.Spec.PolicyTypes) == 0 {
+		return policyType == networkingv1.PolicyTypeIngress || len(policy.Spec.Egress) > 0
+	}
+	for _, t := range policy.Spec.PolicyTypes {
+		if t == policyType {
+			return true
+		}
+	}
+	return false
+}
+
+// peersMatch returns true if the pod matches any peer, where no peers match every pod
+func peersMatch(peers []networkingv1.NetworkPolicyPeer, policyNamespace string, namespaces Namespaces, p *corev1.Pod) bool {
+	if len(peers) == 0 {
+		return true
+	}
+	for _, peer := range peers {
+		if peer.PodSelector == nil && peer.NamespaceSelector == nil {
+			// ipBlock peers apply to cluster external traffic
+			continue
+		}
+		if peer.NamespaceSelector == nil && p.Namespace != policyNamespace {
+			continue
+		}
+		if peer.NamespaceSelector != nil && !matches(peer.NamespaceSelector, namespaces.labelsOf(p.Namespace)) {
+			continue
+		}
+		if peer.PodSelector != nil && !matches(peer.PodSelector, labels.Set(p.Labels)) {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+func matches(selector *metav1.LabelSelector, set labels.Set) bool {
+	s, err := metav1.LabelSelectorAsSelector(selector)
+	return err == nil && s.Matches(set)
+}
@@ -1 +1,31 @@
 package node
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+// IsReady returns true if the node reports a Ready condition with status True
+func IsReady(node *corev1.Node) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// IsCordoned returns true if the node has been marked unschedulable
+func IsCordoned(node *corev1.Node) bool {
+	return node.Spec.Unschedulable
+}
+
+// HasTaint returns true if the node carries a taint with the given key.
+// An empty key matches any taint.
+func HasTaint(node *corev1.Node, key string) bool {
+	for _, taint := range node.Spec.Taints {
+		if key == "" || taint.Key == key {
+			return true
+		}
+	}
+	return false
+}
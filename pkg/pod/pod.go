@@ -0,0 +1,140 @@
+package pod
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// Requests returns the effective resource requests of the pod as the scheduler
+// sees them: the larger of the summed app containers and any single init
+// container, plus pod overhead.
+func Requests(pod *corev1.Pod) corev1.ResourceList {
+	requests := corev1.ResourceList{}
+	for _, container := range pod.Spec.Containers {
+		AddResources(requests, container.Resources.Requests)
+	}
+	for _, container := range pod.Spec.InitContainers {
+		for name, quantity := range container.Resources.Requests {
+			if current, ok := requests[name]; !ok || quantity.Cmp(current) > 0 {
+				requests[name] = quantity.DeepCopy()
+			}
+		}
+	}
+	AddResources(requests, pod.Spec.Overhead)
+	return requests
+}
+
+// Limits returns the effective resource limits of the pod, combined the same way as Requests
+func Limits(pod *corev1.Pod) corev1.ResourceList {
+	limits := corev1.ResourceList{}
+	for _, container := range pod.Spec.Containers {
+		AddResources(limits, container.Resources.Limits)
+	}
+	for _, container := range pod.Spec.InitContainers {
+		for name, quantity := range container.Resources.Limits {
+			if current, ok := limits[name]; !ok || quantity.Cmp(current) > 0 {
+				limits[name] = quantity.DeepCopy()
+			}
+		}
+	}
+	if len(limits) > 0 {
+		AddResources(limits, pod.Spec.Overhead)
+	}
+	return limits
+}
+
+// AddResources adds every quantity in delta into total
+func AddResources(total corev1.ResourceList, delta corev1.ResourceList) {
+	for name, quantity := range delta {
+		current, ok := total[name]
+		if !ok {
+			current = resource.Quantity{}
+		}
+		current.Add(quantity)
+		total[name] = current
+	}
+}
+
+// IsTerminal returns true if the pod has finished and no longer holds node resources
+func IsTerminal(pod *corev1.Pod) bool {
+	return pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed
+}
+
+// IsReady returns true if the pod is running with a Ready condition of True
+func IsReady(pod *corev1.Pod) bool {
+	if pod.Status.Phase != corev1.PodRunning {
+		return false
+	}
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// conditionSince returns when the pod's condition last became True
+func conditionSince(pod *corev1.Pod, conditionType corev1.PodConditionType) (time.Time, bool) {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == conditionType && condition.Status == corev1.ConditionTrue {
+			return condition.LastTransitionTime.Time, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// SchedulingLatency returns the time from the pod's creation until it was scheduled
+func SchedulingLatency(pod *corev1.Pod) (time.Duration, bool) {
+	scheduled, ok := conditionSince(pod, corev1.PodScheduled)
+	if !ok {
+		return 0, false
+	}
+	return scheduled.Sub(pod.CreationTimestamp.Time), true
+}
+
+// ReadyLatency returns the time from the pod's creation until it became Ready
+func ReadyLatency(pod *corev1.Pod) (time.Duration, bool) {
+	ready, ok := conditionSince(pod, corev1.PodReady)
+	if !ok {
+		return 0, false
+	}
+	return ready.Sub(pod.CreationTimestamp.Time), true
+}
+
+// Privileges returns the elevated privileges the pod's spec asks for: "privileged"
+// and "root" when any container is privileged or runs as UID 0, and the host
+// namespaces it shares. Images that default to root without a runAsUser are not detected.
+func Privileges(pod *corev1.Pod) []string {
+	var privileges []string
+	privileged, root := false, false
+	podUser := pod.Spec.SecurityContext != nil && pod.Spec.SecurityContext.RunAsUser != nil && *pod.Spec.SecurityContext.RunAsUser == 0
+	for _, container := range append(append([]corev1.Container(nil), pod.Spec.InitContainers...), pod.Spec.Containers...) {
+		security := container.SecurityContext
+		if security != nil && security.Privileged != nil && *security.Privileged {
+			privileged = true
+		}
+		if security != nil && security.RunAsUser != nil {
+			root = root || *security.RunAsUser == 0
+		} else {
+			root = root || podUser
+		}
+	}
+	if privileged {
+		privileges = append(privileges, "privileged")
+	}
+	if root {
+		privileges = append(privileges, "root")
+	}
+	if pod.Spec.HostNetwork {
+		privileges = append(privileges, "hostNetwork")
+	}
+	if pod.Spec.HostPID {
+		privileges = append(privileges, "hostPID")
+	}
+	if pod.Spec.HostIPC {
+		privileges = append(privileges, "hostIPC")
+	}
+	return privileges
+}
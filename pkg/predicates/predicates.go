@@ -0,0 +1,134 @@
+package predicates
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/samber/lo"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/bwagner5/kube-demo/pkg/binpack"
+	"github.com/bwagner5/kube-demo/pkg/node"
+	"github.com/bwagner5/kube-demo/pkg/pod"
+)
+
+// Explain evaluates a basic subset of the scheduler's filters for the pod
+// against the node and returns a reason for every filter that rejects it.
+// An empty result means the pod would fit.
+func Explain(p *corev1.Pod, n *corev1.Node, nodePods []*corev1.Pod) []string {
+	var reasons []string
+	if !node.IsReady(n) {
+		reasons = append(reasons, "node is not Ready")
+	}
+	if taint := untoleratedTaint(p, n); taint != nil {
+		reasons = append(reasons, fmt.Sprintf("untolerated taint %s", taint.ToString()))
+	}
+	if node.IsCordoned(n) && !toleratesUnschedulable(p) {
+		reasons = append(reasons, "node is cordoned")
+	}
+	for key, value := range p.Spec.NodeSelector {
+		if n.Labels[key] != value {
+			reasons = append(reasons, fmt.Sprintf("nodeSelector %s=%s does not match", key, value))
+		}
+	}
+	if !matchesRequiredAffinity(p, n) {
+		reasons = append(reasons, "required node affinity does not match")
+	}
+	free := binpack.NewBin(n, nodePods).Free
+	requests := binpack.WithPodSlot(pod.Requests(p))
+	for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory, corev1.ResourcePods} {
+		requested, ok := requests[name]
+		available, tracked := free[name]
+		if ok && tracked && requested.Cmp(available) > 0 {
+			reasons = append(reasons, fmt.Sprintf("insufficient %s (requests %s, free %s)", name, requested.String(), available.String()))
+		}
+	}
+	return reasons
+}
+
+// untoleratedTaint returns the first NoSchedule or NoExecute taint the pod does not tolerate
+func untoleratedTaint(p *corev1.Pod, n *corev1.Node) *corev1.Taint {
+	for i := range n.Spec.Taints {
+		taint := &n.Spec.Taints[i]
+		if taint.Effect == corev1.TaintEffectPreferNoSchedule {
+			continue
+		}
+		if !Tolerates(p.Spec.Tolerations, taint) {
+			return taint
+		}
+	}
+	return nil
+}
+
+// Tolerates returns true if any of the tolerations tolerates the taint
+func Tolerates(tolerations []corev1.Toleration, taint *corev1.Taint) bool {
+	for i := range tolerations {
+		if tolerations[i].ToleratesTaint(taint) {
+			return true
+		}
+	}
+	return false
+}
+
+func toleratesUnschedulable(p *corev1.Pod) bool {
+	return Tolerates(p.Spec.Tolerations, &corev1.Taint{Key: corev1.TaintNodeUnschedulable, Effect: corev1.TaintEffectNoSchedule})
+}
+
+// matchesRequiredAffinity evaluates requiredDuringSchedulingIgnoredDuringExecution
+// node affinity: terms are ORed, expressions within a term are ANDed
+func matchesRequiredAffinity(p *corev1.Pod, n *corev1.Node) bool {
+	if p.Spec.Affinity == nil || p.Spec.Affinity.NodeAffinity == nil || p.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		return true
+	}
+	terms := p.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+	for _, term := range terms {
+		matched := true
+		for _, requirement := range term.MatchExpressions {
+			if !matchesRequirement(requirement, n.Labels) {
+				matched = false
+				break
+			}
+		}
+		for _, requirement := range term.MatchFields {
+			if requirement.Key == "metadata.name" && !matchesRequirement(requirement, map[string]string{"metadata.name": n.Name}) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return len(terms) == 0
+}
+
+func matchesRequirement(requirement corev1.NodeSelectorRequirement, labels map[string]string) bool {
+	value, exists := labels[requirement.Key]
+	switch requirement.Operator {
+	case corev1.NodeSelectorOpIn:
+		return exists && lo.Contains(requirement.Values, value)
+	case corev1.NodeSelectorOpNotIn:
+		return !exists || !lo.Contains(requirement.Values, value)
+	case corev1.NodeSelectorOpExists:
+		return exists
+	case corev1.NodeSelectorOpDoesNotExist:
+		return !exists
+	case corev1.NodeSelectorOpGt, corev1.NodeSelectorOpLt:
+		if !exists || len(requirement.Values) != 1 {
+			return false
+		}
+		actual, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return false
+		}
+		bound, err := strconv.ParseInt(requirement.Values[0], 10, 64)
+		if err != nil {
+			return false
+		}
+		if requirement.Operator == corev1.NodeSelectorOpGt {
+			return actual > bound
+		}
+		return actual < bound
+	}
+	return false
+}
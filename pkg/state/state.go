@@ -0,0 +1,242 @@
+// Package state turns informer stores into immutable ClusterState snapshots: nodes, pods
+// indexed by node and workloads as of one moment. The TUI renders from them, and headless
+// modes and tests can Subscribe to them or build them from plain stores.
+package state
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/bwagner5/kube-demo/pkg/pod"
+)
+
+// NodeNameIndex is the name of the pod index keyed by spec.nodeName, "" for unbound pods
+const NodeNameIndex = "spec.nodeName"
+
+// NodeNameIndexFunc indexes pods by the node they are bound to
+func NodeNameIndexFunc(obj interface{}) ([]string, error) {
+	p, ok := obj.(*corev1.Pod)
+	if !ok {
+		return nil, nil
+	}
+	return []string{p.Spec.NodeName}, nil
+}
+
+// Stores are the caches snapshots are built from. A Pods store that is a cache.Indexer with
+// the NodeNameIndex has its pods grouped by node through the index.
+type Stores struct {
+	Nodes cache.Store
+	// PreloadedNodes are nodes listed ahead of the Nodes store while it syncs. Those the Nodes
+	// store does not have yet are included, the Nodes store's copy wins otherwise.
+	PreloadedNodes cache.Store
+	Pods           cache.Store
+	Deployments    cache.Store
+	ReplicaSets    cache.Store
+	DaemonSets     cache.Store
+}
+
+// ClusterState is the cluster as of one moment. It must not be modified: the objects are
+// shared with the informer caches and with every other holder of the snapshot.
+type ClusterState struct {
+	// Revision increases with every change observed by the Tracker that built the snapshot
+	Revision uint64
+	Time     time.Time
+	// Nodes and Pods are ordered oldest first
+	Nodes       []*corev1.Node
+	Pods        []*corev1.Pod
+	Deployments []*appsv1.Deployment
+	ReplicaSets []*appsv1.ReplicaSet
+	DaemonSets  []*appsv1.DaemonSet
+
+	podsByNode map[string][]*corev1.Pod
+	pending    []*corev1.Pod
+}
+
+// Build snapshots the stores. Stores left nil are treated as empty.
+func Build(stores Stores, revision uint64) *ClusterState {
+	s := &ClusterState{Revision: revision, Time: time.Now(), podsByNode: map[string][]*corev1.Pod{}}
+	s.Nodes = list[*corev1.Node](stores.Nodes)
+	if stores.PreloadedNodes != nil {
+		for _, n := range list[*corev1.Node](stores.PreloadedNodes) {
+			if stores.Nodes == nil {
+				s.Nodes = append(s.Nodes, n)
+			} else if _, exists, _ := stores.Nodes.Get(n); !exists {
+				s.Nodes = append(s.Nodes, n)
+			}
+		}
+	}
+	sort.SliceStable(s.Nodes, func(i, j int) bool { return olderThan(s.Nodes[i], s.Nodes[j]) })
+	s.Pods = list[*corev1.Pod](stores.Pods)
+	sort.SliceStable(s.Pods, func(i, j int) bool { return olderThan(s.Pods[i], s.Pods[j]) })
+	if indexer, ok := stores.Pods.(cache.Indexer); ok && indexer.GetIndexers()[NodeNameIndex] != nil {
+		s.groupByIndex(indexer)
+	} else {
+		for _, p := range s.Pods {
+			if p.Spec.NodeName != "" {
+				s.podsByNode[p.Spec.NodeName] = append(s.podsByNode[p.Spec.NodeName], p)
+			} else if !pod.IsTerminal(p) {
+				s.pending = append(s.pending, p)
+			}
+		}
+	}
+	s.Deployments = list[*appsv1.Deployment](stores.Deployments)
+	s.ReplicaSets = list[*appsv1.ReplicaSet](stores.ReplicaSets)
+	s.DaemonSets = list[*appsv1.DaemonSet](stores.DaemonSets)
+	return s
+}
+
+// groupByIndex reads the pods of each node from the NodeNameIndex instead of filtering every
+// pod. A change landing between listing the pods and reading the index bumps the revision, so
+// the next snapshot is consistent again.
+func (s *ClusterState) groupByIndex(indexer cache.Indexer) {
+	for _, nodeName := range indexer.ListIndexFuncValues(NodeNameIndex) {
+		objs, err := indexer.ByIndex(NodeNameIndex, nodeName)
+		if err != nil {
+			continue
+		}
+		pods := make([]*corev1.Pod, 0, len(objs))
+		for _, obj := range objs {
+			if p := obj.(*corev1.Pod); nodeName != "" || !pod.IsTerminal(p) {
+				pods = append(pods, p)
+			}
+		}
+		sort.SliceStable(pods, func(i, j int) bool { return olderThan(pods[i], pods[j]) })
+		if nodeName == "" {
+			s.pending = pods
+		} else {
+			s.podsByNode[nodeName] = pods
+		}
+	}
+}
+
+func list[T any](store cache.Store) []T {
+	if store == nil {
+		return nil
+	}
+	objs := store.List()
+	typed := make([]T, 0, len(objs))
+	for _, obj := range objs {
+		typed = append(typed, obj.(T))
+	}
+	return typed
+}
+
+// olderThan orders objects by creation, to the second as the API server records it, then by UID
+func olderThan(a, b metav1.Object) bool {
+	ai, bi := a.GetCreationTimestamp().Unix(), b.GetCreationTimestamp().Unix()
+	if ai == bi {
+		return a.GetUID() < b.GetUID()
+	}
+	return ai < bi
+}
+
+// PodsOn returns the pods bound to the node, oldest first. The slice is shared and must not be modified.
+func (s *ClusterState) PodsOn(nodeName string) []*corev1.Pod {
+	pods := s.podsByNode[nodeName]
+	return pods[:len(pods):len(pods)]
+}
+
+// Pending returns the pods not bound to a node and not finished, oldest first. The slice is
+// shared and must not be modified.
+func (s *ClusterState) Pending() []*corev1.Pod {
+	return s.pending[:len(s.pending):len(s.pending)]
+}
+
+// Tracker keeps the latest snapshot of the stores, rebuilding it on demand once its
+// Handler has observed a change, and delivers new snapshots to subscribers
+type Tracker struct {
+	stores Stores
+	// changed wakes Run up, coalescing changes made while a snapshot is built
+	changed chan struct{}
+
+	mu          sync.Mutex
+	revision    uint64
+	current     *ClusterState
+	subscribers map[chan *ClusterState]struct{}
+}
+
+// NewTracker returns a Tracker of the stores. Changes made to the stores only show in
+// snapshots once reported through Handler.
+func NewTracker(stores Stores) *Tracker {
+	return &Tracker{stores: stores, revision: 1, changed: make(chan struct{}, 1), subscribers: map[chan *ClusterState]struct{}{}}
+}
+
+// Current returns the snapshot of the latest observed revision, building it if needed
+func (t *Tracker) Current() *ClusterState {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.current == nil || t.current.Revision != t.revision {
+		t.current = Build(t.stores, t.revision)
+	}
+	return t.current
+}
+
+// Handler returns informer callbacks recording changes to the kinds snapshots hold.
+// Other kinds are ignored, so it can be added to every informer.
+func (t *Tracker) Handler() cache.ResourceEventHandlerFuncs {
+	observe := func(obj interface{}) {
+		if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+			obj = tombstone.Obj
+		}
+		switch obj.(type) {
+		case *corev1.Node, *corev1.Pod, *appsv1.Deployment, *appsv1.ReplicaSet, *appsv1.DaemonSet:
+		default:
+			return
+		}
+		t.mu.Lock()
+		t.revision++
+		t.mu.Unlock()
+		select {
+		case t.changed <- struct{}{}:
+		default:
+		}
+	}
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc:    observe,
+		UpdateFunc: func(_, obj interface{}) { observe(obj) },
+		DeleteFunc: observe,
+	}
+}
+
+// Subscribe returns a channel receiving the current snapshot and then every newer one while
+// Run is running. A slow subscriber only misses intermediate snapshots, the latest always
+// arrives. cancel stops the deliveries.
+func (t *Tracker) Subscribe() (snapshots <-chan *ClusterState, cancel func()) {
+	ch := make(chan *ClusterState, 1)
+	ch <- t.Current()
+	t.mu.Lock()
+	t.subscribers[ch] = struct{}{}
+	t.mu.Unlock()
+	return ch, func() {
+		t.mu.Lock()
+		delete(t.subscribers, ch)
+		t.mu.Unlock()
+	}
+}
+
+// Run delivers a snapshot to the subscribers after every change until stopCh is closed
+func (t *Tracker) Run(stopCh <-chan struct{}) {
+	for {
+		select {
+		case <-stopCh:
+			return
+		case <-t.changed:
+		}
+		s := t.Current()
+		t.mu.Lock()
+		for ch := range t.subscribers {
+			select {
+			case <-ch:
+			default:
+			}
+			ch <- s
+		}
+		t.mu.Unlock()
+	}
+}
@@ -0,0 +1,213 @@
+package state
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+)
+
+var created = time.Date(2022, 9, 1, 0, 0, 0, 0, time.UTC)
+
+func testNode(name, uid string, age time.Duration) *corev1.Node {
+	return &corev1.Node{ObjectMeta: metav1.ObjectMeta{
+		Name:              name,
+		UID:               types.UID(uid),
+		CreationTimestamp: metav1.NewTime(created.Add(-age)),
+	}}
+}
+
+func testPod(name, uid, nodeName string, age time.Duration, phase corev1.PodPhase) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         "default",
+			UID:               types.UID(uid),
+			CreationTimestamp: metav1.NewTime(created.Add(-age)),
+		},
+		Spec:   corev1.PodSpec{NodeName: nodeName},
+		Status: corev1.PodStatus{Phase: phase},
+	}
+}
+
+func storeOf(t *testing.T, store cache.Store, objs ...interface{}) cache.Store {
+	t.Helper()
+	for _, obj := range objs {
+		if err := store.Add(obj); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return store
+}
+
+func names[T metav1.Object](objs []T) []string {
+	result := make([]string, 0, len(objs))
+	for _, obj := range objs {
+		result = append(result, obj.GetName())
+	}
+	return result
+}
+
+func assertNames(t *testing.T, what string, got, want []string) {
+	t.Helper()
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("%s = %v, want %v", what, got, want)
+	}
+}
+
+// testPods is a cluster of two nodes, a pending pod and two finished ones, one still bound
+func testPods() []interface{} {
+	return []interface{}{
+		testPod("a-new", "a", "node-a", time.Minute, corev1.PodRunning),
+		testPod("a-old", "b", "node-a", time.Hour, corev1.PodRunning),
+		testPod("a-done", "c", "node-a", 2*time.Hour, corev1.PodSucceeded),
+		testPod("b-tie-2", "e", "node-b", time.Hour, corev1.PodRunning),
+		testPod("b-tie-1", "d", "node-b", time.Hour, corev1.PodRunning),
+		testPod("pending", "f", "", time.Minute, corev1.PodPending),
+		testPod("unbound-failed", "g", "", time.Hour, corev1.PodFailed),
+	}
+}
+
+func TestBuildOrdersOldestFirst(t *testing.T) {
+	stores := Stores{
+		Nodes: storeOf(t, cache.NewStore(cache.MetaNamespaceKeyFunc),
+			testNode("young", "1", time.Minute),
+			testNode("tie-2", "3", time.Hour),
+			testNode("old", "0", 2*time.Hour),
+			testNode("tie-1", "2", time.Hour),
+		),
+		Pods: storeOf(t, cache.NewStore(cache.MetaNamespaceKeyFunc), testPods()...),
+	}
+	s := Build(stores, 7)
+	if s.Revision != 7 {
+		t.Errorf("Revision = %d, want 7", s.Revision)
+	}
+	assertNames(t, "Nodes", names(s.Nodes), []string{"old", "tie-1", "tie-2", "young"})
+	assertNames(t, "Pods", names(s.Pods), []string{"a-done", "a-old", "b-tie-1", "b-tie-2", "unbound-failed", "a-new", "pending"})
+}
+
+func TestBuildNilStores(t *testing.T) {
+	s := Build(Stores{}, 1)
+	if len(s.Nodes) != 0 || len(s.Pods) != 0 || len(s.Pending()) != 0 || len(s.PodsOn("node-a")) != 0 {
+		t.Errorf("Build of nil stores is not empty: %+v", s)
+	}
+}
+
+func TestBuildPreloadedNodes(t *testing.T) {
+	synced := testNode("node-a", "a", time.Hour)
+	synced.Labels = map[string]string{"synced": "true"}
+	stores := Stores{
+		Nodes:          storeOf(t, cache.NewStore(cache.MetaNamespaceKeyFunc), synced),
+		PreloadedNodes: storeOf(t, cache.NewStore(cache.MetaNamespaceKeyFunc), testNode("node-a", "a", time.Hour), testNode("node-b", "b", 2*time.Hour)),
+	}
+	s := Build(stores, 1)
+	assertNames(t, "Nodes", names(s.Nodes), []string{"node-b", "node-a"})
+	if s.Nodes[1] != synced {
+		t.Errorf("Nodes[1] is the preloaded copy of node-a, want the Nodes store's")
+	}
+
+	stores.Nodes = nil
+	assertNames(t, "Nodes without a Nodes store", names(Build(stores, 1).Nodes), []string{"node-b", "node-a"})
+}
+
+// TestGroupByIndex builds the same pods from an indexer with the NodeNameIndex, grouped by
+// groupByIndex, and from a plain store, grouped by filtering, and expects the same groups
+func TestGroupByIndex(t *testing.T) {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{NodeNameIndex: NodeNameIndexFunc})
+	for name, store := range map[string]cache.Store{
+		"indexer": storeOf(t, indexer, testPods()...),
+		"store":   storeOf(t, cache.NewStore(cache.MetaNamespaceKeyFunc), testPods()...),
+	} {
+		t.Run(name, func(t *testing.T) {
+			s := Build(Stores{Pods: store}, 1)
+			assertNames(t, "PodsOn(node-a)", names(s.PodsOn("node-a")), []string{"a-done", "a-old", "a-new"})
+			assertNames(t, "PodsOn(node-b)", names(s.PodsOn("node-b")), []string{"b-tie-1", "b-tie-2"})
+			assertNames(t, "PodsOn(node-c)", names(s.PodsOn("node-c")), []string{})
+			assertNames(t, "Pending", names(s.Pending()), []string{"pending"})
+		})
+	}
+}
+
+func TestPodsOnIsNotAppendable(t *testing.T) {
+	s := Build(Stores{Pods: storeOf(t, cache.NewStore(cache.MetaNamespaceKeyFunc), testPods()...)}, 1)
+	_ = append(s.PodsOn("node-b"), testPod("appended", "x", "node-b", 0, corev1.PodRunning))
+	_ = append(s.Pending(), testPod("appended", "y", "", 0, corev1.PodPending))
+	assertNames(t, "PodsOn(node-b)", names(s.PodsOn("node-b")), []string{"b-tie-1", "b-tie-2"})
+	assertNames(t, "Pending", names(s.Pending()), []string{"pending"})
+}
+
+func TestTrackerHandler(t *testing.T) {
+	nodes := cache.NewStore(cache.MetaNamespaceKeyFunc)
+	tracker := NewTracker(Stores{Nodes: nodes})
+	first := tracker.Current()
+	if tracker.Current() != first {
+		t.Errorf("Current rebuilt the snapshot without a change")
+	}
+
+	tracker.Handler().OnAdd(&corev1.Service{})
+	if tracker.Current() != first {
+		t.Errorf("Current rebuilt the snapshot after a change to an ignored kind")
+	}
+
+	n := testNode("node-a", "a", time.Hour)
+	storeOf(t, nodes, n)
+	if len(tracker.Current().Nodes) != 0 {
+		t.Errorf("Current shows a change not reported through Handler")
+	}
+	tracker.Handler().OnAdd(n)
+	if s := tracker.Current(); s.Revision != first.Revision+1 || len(s.Nodes) != 1 {
+		t.Errorf("Current = revision %d with %d nodes, want revision %d with 1 node", s.Revision, len(s.Nodes), first.Revision+1)
+	}
+
+	if err := nodes.Delete(n); err != nil {
+		t.Fatal(err)
+	}
+	tracker.Handler().OnDelete(cache.DeletedFinalStateUnknown{Key: "node-a", Obj: n})
+	if s := tracker.Current(); s.Revision != first.Revision+2 || len(s.Nodes) != 0 {
+		t.Errorf("Current = revision %d with %d nodes, want revision %d with none", s.Revision, len(s.Nodes), first.Revision+2)
+	}
+}
+
+func TestTrackerSubscribe(t *testing.T) {
+	nodes := cache.NewStore(cache.MetaNamespaceKeyFunc)
+	tracker := NewTracker(Stores{Nodes: nodes})
+	snapshots, cancel := tracker.Subscribe()
+	if s := <-snapshots; s != tracker.Current() {
+		t.Errorf("Subscribe did not deliver the current snapshot first")
+	}
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	go tracker.Run(stopCh)
+
+	// the subscriber reads nothing while the changes are made, so Run has to coalesce them
+	const changes = 20
+	for i := 0; i < changes; i++ {
+		n := testNode(fmt.Sprintf("node-%02d", i), fmt.Sprint(i), time.Duration(i)*time.Minute)
+		storeOf(t, nodes, n)
+		tracker.Handler().OnAdd(n)
+	}
+	timeout := time.After(5 * time.Second)
+	for latest := false; !latest; {
+		select {
+		case s := <-snapshots:
+			latest = s.Revision == 1+changes
+			if latest && len(s.Nodes) != changes {
+				t.Errorf("latest snapshot has %d nodes, want %d", len(s.Nodes), changes)
+			}
+		case <-timeout:
+			t.Fatalf("the snapshot of revision %d never arrived", 1+changes)
+		}
+	}
+
+	cancel()
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+	if len(tracker.subscribers) != 0 {
+		t.Errorf("cancel left %d subscribers", len(tracker.subscribers))
+	}
+}